@@ -2,7 +2,9 @@
 package restrict
 
 import (
+	"context"
 	"fmt"
+
 	"github.com/el-mike/restrict/v2/internal/utils"
 )
 
@@ -11,173 +13,778 @@ import (
 type AccessManager struct {
 	// PolicyProvider instance, responsible for providing PolicyDefinition.
 	policyManager PolicyProvider
+	// Clock instance, responsible for providing current time for Conditions.
+	clock Clock
+	// CombiningAlgorithm, responsible for resolving Effects of applicable Permissions.
+	combiningAlgorithm CombiningAlgorithm
+	// DecisionCache instance, nil if decisions are not cached.
+	decisionCache *DecisionCache
+	// strictEvaluation - whether errors encountered after the Action has been allowed fail the authorization.
+	strictEvaluation bool
 }
 
 // NewAccessManager - returns new AccessManager instance.
 func NewAccessManager(policyManager PolicyProvider) *AccessManager {
 	return &AccessManager{
-		policyManager: policyManager,
+		policyManager:      policyManager,
+		clock:              systemClock{},
+		combiningAlgorithm: DenyOverrides,
+	}
+}
+
+// SetClock - sets the Clock used for providing current time for Conditions.
+// Passing nil restores the default, system Clock.
+func (am *AccessManager) SetClock(clock Clock) {
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	am.clock = clock
+}
+
+// SetCombiningAlgorithm - sets the CombiningAlgorithm used for resolving Effects of applicable
+// Permissions - either one of BuiltInCombiningAlgorithms, or a custom one (e.g. CombiningAlgorithmFunc).
+// Passing nil restores the default, DenyOverrides algorithm.
+func (am *AccessManager) SetCombiningAlgorithm(algorithm CombiningAlgorithm) {
+	if algorithm == nil {
+		algorithm = DenyOverrides
+	}
+
+	am.combiningAlgorithm = algorithm
+
+	// Cached decisions have been made with the previous algorithm.
+	if am.decisionCache != nil {
+		am.decisionCache.Purge()
+	}
+}
+
+// SetDecisionCache - sets the DecisionCache used for caching decisions. Passing nil disables caching.
+// When PolicyProvider implements PolicyVersionProvider (as PolicyManager does), cached decisions
+// are dropped whenever the policy changes.
+func (am *AccessManager) SetDecisionCache(cache *DecisionCache) {
+	am.decisionCache = cache
+}
+
+// SetStrictEvaluation - sets whether errors encountered while checking remaining Roles for deny Permissions,
+// after the Action has already been allowed (e.g. missing Roles, or Conditions returning errors other than
+// ConditionNotSatisfiedError), fail the authorization. Disabled by default - such Roles and Permissions
+// are skipped, the same way they were never reached before the Action has been allowed.
+func (am *AccessManager) SetStrictEvaluation(strict bool) {
+	am.strictEvaluation = strict
+
+	// Cached decisions could have been made with skipped errors.
+	if am.decisionCache != nil {
+		am.decisionCache.Purge()
 	}
 }
 
 // Authorize - checks if given AccessRequest can be satisfied given currently loaded policy.
 // Returns an error if access is not granted or any other problem occurred, nil otherwise.
 func (am *AccessManager) Authorize(request *AccessRequest) error {
+	return am.AuthorizeContext(context.Background(), request)
+}
+
+// AuthorizeContext - works as Authorize, but given context.Context is passed to Conditions implementing
+// ContextCondition and to PolicyProvider implementing ContextPolicyProvider. Authorization is aborted
+// with context's error once the context is canceled or its deadline is exceeded.
+func (am *AccessManager) AuthorizeContext(ctx context.Context, request *AccessRequest) error {
+	// If CompleteValidation is false, we want to return early.
+	decision, err := am.evaluate(ctx, request, !request.CompleteValidation, am.newPolicyState())
+	if err != nil {
+		return err
+	}
+
+	if decision.Allowed {
+		return nil
+	}
+
+	accessDeniedError := newAccessDeniedError(request, decision.Reasons())
+	accessDeniedError.Trace = decision.Trace
+
+	return accessDeniedError
+}
+
+// Evaluate - evaluates given AccessRequest given currently loaded policy, and returns a Decision
+// describing whether every Action is allowed, and why. Unlike Authorize, all Actions are always
+// evaluated, and denied access is not an error - returned error describes other problems only.
+func (am *AccessManager) Evaluate(request *AccessRequest) (*Decision, error) {
+	return am.evaluate(context.Background(), request, false, am.newPolicyState())
+}
+
+// evaluate - evaluates given AccessRequest within given context, against the policy described by given
+// policyState. If failEarly is true, evaluation stops at the first denied Action.
+func (am *AccessManager) evaluate(
+	ctx context.Context,
+	request *AccessRequest,
+	failEarly bool,
+	state *policyState,
+) (*Decision, error) {
 	if request.Subject == nil || request.Resource == nil {
-		return newRequestMalformedError(request, fmt.Errorf("Subject or Resource not defined"))
+		return nil, newRequestMalformedError(request, fmt.Errorf("Subject or Resource not defined"))
 	}
 
 	roles := request.Subject.GetRoles()
 	resourceName := request.Resource.GetResourceName()
 
 	if len(roles) == 0 || resourceName == "" {
-		return newRequestMalformedError(request, fmt.Errorf("missing roles or resourceName"))
+		return nil, newRequestMalformedError(request, fmt.Errorf("missing roles or resourceName"))
 	}
 
-	allPermissionErrors := PermissionErrors{}
+	for _, action := range request.Actions {
+		if action == "" {
+			return nil, newRequestMalformedError(request, fmt.Errorf("action cannot be empty"))
+		}
+	}
 
-	for _, roleName := range roles {
-		permissionErrors, err := am.authorize(request, roleName, resourceName, []string{})
+	// Current time is captured once, so all Conditions are checked against the same moment.
+	timedRequest := request.withTime(am.clock.Now())
+	timedRequest.ctx = ctx
+	timedRequest.resolvedValues = map[string]interface{}{}
+	timedRequest.index = state.snapshot.index
 
-		// If error is not authorization-specific, we return immediately.
-		if err != nil {
-			return err
+	if request.Trace {
+		timedRequest.tracer = newTracer()
+	}
+
+	decision := newDecision(request)
+	version := state.snapshot.version
+
+	for _, action := range request.Actions {
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
 
-		// If AccessRequest is satisfied by a Role, we return immediately.
-		if permissionErrors == nil {
-			return nil
+		actionDecision := am.getCachedDecision(timedRequest, roles, resourceName, action, version)
+
+		if actionDecision == nil {
+			var err error
+
+			actionDecision, err = am.decideAction(timedRequest, action, roles, resourceName, state)
+			// If error is not authorization-specific, we return immediately.
+			if err != nil {
+				return nil, err
+			}
+
+			am.cacheDecision(timedRequest, roles, resourceName, actionDecision, version)
 		}
 
-		// Otherwise, we save it to PermissionErrors, so we can return it to the caller
-		// if no Role satisfies the AccessRequest.
-		allPermissionErrors = append(allPermissionErrors, permissionErrors...)
+		decision.addAction(actionDecision)
+
+		if !actionDecision.Allowed && failEarly {
+			break
+		}
 	}
 
-	return newAccessDeniedError(request, allPermissionErrors)
+	decision.Trace = timedRequest.tracer.getTrace()
+
+	return decision, nil
+}
+
+// actionEvaluation - state of a single Action's evaluation across Subject's Roles and their Parents.
+type actionEvaluation struct {
+	action       string
+	resourceName string
+	// impliedBy - Actions implying evaluated Action.
+	impliedBy []string
+
+	// roleIndex - index of currently evaluated Subject's Role.
+	roleIndex int
+	// effects - Effects of applicable Permissions, in order of evaluation.
+	effects []Effect
+	// applied - applicable Permissions, in order of evaluation.
+	applied []*AppliedPermission
+	// isFinal - true if CombiningAlgorithm has reached a final decision.
+	isFinal bool
+	// allowIsFinal - true if the policy is known to have no deny Permissions, so the decision is final
+	// as soon as CombiningAlgorithm allows the Action.
+	allowIsFinal bool
+	// conditional - true if any of evaluated Permissions has Conditions.
+	conditional bool
+	// isAllowed - true if at least one applicable Permission allows the Action.
+	isAllowed bool
+
+	// permissionErrors - errors of Subject's Roles own Permissions, per Role.
+	permissionErrors []PermissionErrors
+	// denyErrors - errors of applicable Permissions explicitly denying the Action, per Role.
+	denyErrors []PermissionErrors
 }
 
-// authorize - helper function for decoupling role and resource names retrieval from recursive search.
-func (am *AccessManager) authorize(request *AccessRequest, roleName, resourceName string, checkedRoles []string) (PermissionErrors, error) {
-	role, err := am.policyManager.GetRole(roleName)
+// decideAction - checks if given Action is allowed for any of the Subject's Roles, according to
+// CombiningAlgorithm. If no Permission applies to the Resource, its ancestors are checked, up the chain.
+func (am *AccessManager) decideAction(
+	request *AccessRequest,
+	action string,
+	roles []string,
+	resourceName string,
+	state *policyState,
+) (*ActionDecision, error) {
+	actionStep := request.tracer.start(ActionStep, action)
+	defer request.tracer.finish(actionStep)
+
+	actionDecision, err := am.decideActionForResource(request, action, roles, resourceName, state)
 	if err != nil {
+		actionStep.setOutcome(FailedOutcome, err.Error())
+
 		return nil, err
 	}
 
-	var grants Permissions
-
-	if role.Grants == nil {
-		grants = Permissions{}
+	if actionDecision.Allowed {
+		actionStep.setOutcome(AllowedOutcome, "")
 	} else {
-		grants = role.Grants[resourceName]
+		actionStep.setOutcome(DeniedOutcome, "")
 	}
 
-	parents := role.Parents
-	allPermissionErrors := PermissionErrors{}
+	return actionDecision, nil
+}
 
-	for _, action := range request.Actions {
-		if action == "" {
-			return nil, newRequestMalformedError(request, fmt.Errorf("action cannot be empty"))
+// decideActionForResource - decides about given Action for the Resource, or its ancestors.
+func (am *AccessManager) decideActionForResource(
+	request *AccessRequest,
+	action string,
+	roles []string,
+	resourceName string,
+	state *policyState,
+) (*ActionDecision, error) {
+	var originalPermissionErrors []PermissionErrors
+
+	conditional := false
+
+	hierarchy := newResourceHierarchy(request.Resource, resourceName)
+
+	for {
+		ancestorRequest := request
+
+		// Conditions are checked against the original Resource, unless ancestor's definition
+		// says otherwise and its instance is known.
+		definition := am.getResourceDefinition(state, hierarchy.name)
+
+		if hierarchy.isAncestor() &&
+			hierarchy.resource != nil &&
+			definition.getConditionsTarget() == AncestorResource {
+			ancestorRequest = request.withResource(hierarchy.resource)
 		}
 
-		permissionErrors, err := am.validateAction(grants, action, roleName, request)
-		// If non-policy related error happened, we return it directly.
+		resourceStep := request.tracer.start(ResourceStep, hierarchy.name)
+
+		evaluation, err := am.evaluateAction(ancestorRequest, action, roles, hierarchy.name, state)
+
+		request.tracer.finish(resourceStep)
+
+		if err != nil {
+			resourceStep.setOutcome(FailedOutcome, err.Error())
+
+			return nil, err
+		}
+
+		conditional = conditional || evaluation.conditional
+
+		// Errors for ancestors should not override the original ones.
+		if originalPermissionErrors == nil {
+			originalPermissionErrors = evaluation.permissionErrors
+		}
+
+		effect, _ := am.combiningAlgorithm.Combine(evaluation.effects)
+
+		switch effect {
+		case AllowEffect:
+			resourceStep.setOutcome(AllowedOutcome, "")
+		case DenyEffect:
+			resourceStep.setOutcome(DeniedOutcome, "")
+		default:
+			resourceStep.setOutcome(NotApplicableOutcome, "")
+		}
+
+		var actionDecision *ActionDecision
+
+		switch effect {
+		case AllowEffect:
+			actionDecision = newActionDecision(action, effect, evaluation.getApplied(effect), nil)
+		case DenyEffect:
+			actionDecision = newActionDecision(action, effect, evaluation.getApplied(effect), evaluation.denyErrors)
+		}
+
+		if actionDecision != nil {
+			actionDecision.conditional = conditional
+
+			return actionDecision, nil
+		}
+
+		// Ancestors of Resource instance may differ between instances of the same Resource.
+		if _, ok := hierarchy.resource.(HierarchicalResource); ok {
+			conditional = true
+		}
+
+		// If no Permission applies, we check the parent Resource.
+		hasParent, err := hierarchy.next(definition)
 		if err != nil {
 			return nil, err
 		}
 
-		// If access is not granted for given action on current Role, check if
-		// any parent Role can satisfy the request.
-		if permissionErrors != nil && len(parents) > 0 {
-			checkedRoles = append(checkedRoles, roleName)
+		if !hasParent {
+			actionDecision = newActionDecision(action, "", nil, originalPermissionErrors)
+			actionDecision.conditional = conditional
 
-			for _, parent := range parents {
-				parentRequest := &AccessRequest{
-					Subject:  request.Subject,
-					Resource: request.Resource,
-					Actions:  []string{action},
-					Context:  request.Context,
-				}
+			return actionDecision, nil
+		}
+	}
+}
 
-				// If parent has already been checked, we want to return an error - otherwise
-				// this function will fall into infinite loop.
-				if utils.StringSliceContains(checkedRoles, parent) {
-					return nil, newRoleInheritanceCycleError(checkedRoles)
-				}
+// evaluateAction - evaluates given Action for all Subject's Roles and given Resource name.
+func (am *AccessManager) evaluateAction(
+	request *AccessRequest,
+	action string,
+	roles []string,
+	resourceName string,
+	state *policyState,
+) (*actionEvaluation, error) {
+	evaluation := &actionEvaluation{
+		action:           action,
+		resourceName:     resourceName,
+		impliedBy:        am.getImplyingActions(request, resourceName, action),
+		allowIsFinal:     request.index != nil && !request.index.hasDeny,
+		effects:          []Effect{},
+		permissionErrors: make([]PermissionErrors, len(roles)),
+		denyErrors:       make([]PermissionErrors, len(roles)),
+	}
 
-				parentPermissionErrors, err := am.authorize(parentRequest, parent, resourceName, checkedRoles)
-				if err != nil {
-					return nil, err
-				}
+	for i, roleName := range roles {
+		evaluation.roleIndex = i
 
-				// If .authorize call with parent Role has returned nil,
-				// that means the request is satisfied.
-				// Otherwise, returned errors should not override the original ones.
-				if parentPermissionErrors == nil {
-					permissionErrors = nil
-				}
+		var err error
+
+		if request.index != nil {
+			err = am.authorizeIndexed(request, evaluation, roleName)
+		} else {
+			err = am.authorize(request, evaluation, roleName, []string{}, state)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if evaluation.isFinal {
+			break
+		}
+	}
+
+	return evaluation, nil
+}
+
+// getImplyingActions - returns Actions implying given Action for given Resource name, precompiled in
+// request's PolicyIndex or, if it's not available, provided by PolicyProvider implementing
+// ActionHierarchyProvider. Returns nil otherwise.
+func (am *AccessManager) getImplyingActions(request *AccessRequest, resourceName, action string) []string {
+	if request.index != nil {
+		return request.index.getImplyingActions(resourceName, action)
+	}
+
+	provider, ok := am.policyManager.(ActionHierarchyProvider)
+	if !ok {
+		return nil
+	}
+
+	return provider.GetImplyingActions(resourceName, action)
+}
+
+// getPolicyIndex - returns PolicyIndex, if PolicyProvider implements PolicyIndexProvider.
+// Returns nil otherwise.
+func (am *AccessManager) getPolicyIndex() *PolicyIndex {
+	provider, ok := am.policyManager.(PolicyIndexProvider)
+	if !ok {
+		return nil
+	}
+
+	return provider.GetPolicyIndex()
+}
+
+// getResourceDefinition - returns ResourceDefinition for given Resource name from the policy snapshot or,
+// if it's not available, provided by PolicyProvider implementing ResourceDefinitionProvider. Returns nil otherwise.
+func (am *AccessManager) getResourceDefinition(state *policyState, resourceName string) *ResourceDefinition {
+	if policy := state.snapshot.policy; policy != nil {
+		return policy.Resources[resourceName]
+	}
+
+	provider, ok := am.policyManager.(ResourceDefinitionProvider)
+	if !ok {
+		return nil
+	}
+
+	return provider.GetResourceDefinition(resourceName)
+}
+
+// authorize - evaluates Permissions of given Role and its Parents (depth-first) for currently evaluated Action.
+func (am *AccessManager) authorize(
+	request *AccessRequest,
+	evaluation *actionEvaluation,
+	roleName string,
+	checkedRoles []string,
+	state *policyState,
+) error {
+	roleStep := request.tracer.start(RoleStep, roleName)
+	defer request.tracer.finish(roleStep)
+
+	role, err := am.getRole(request.getContext(), roleName, state)
+	if err != nil {
+		if am.canSkipError(request, evaluation) {
+			roleStep.setOutcome(SkippedOutcome, err.Error())
+
+			return nil
+		}
+
+		roleStep.setOutcome(FailedOutcome, err.Error())
+
+		return err
+	}
+
+	// Permissions granted for Actions implying evaluated Action are checked as well.
+	actions := append([]string{evaluation.action}, evaluation.impliedBy...)
+
+	rolePath := append(append([]string{}, checkedRoles...), roleName)
+
+	permissions := role.Grants.forActions(evaluation.resourceName, actions)
+
+	permissionErrors, err := am.validateAction(permissions, rolePath, request, evaluation)
+	// If non-policy related error happened, we return it directly.
+	if err != nil {
+		return err
+	}
+
+	// Only errors of Subject's Roles are reported - the ones returned for Parents
+	// should not override the original ones.
+	if len(checkedRoles) == 0 {
+		evaluation.permissionErrors[evaluation.roleIndex] = permissionErrors
+	}
+
+	if evaluation.isFinal {
+		return nil
+	}
+
+	checkedRoles = rolePath
+
+	for _, parent := range role.Parents {
+		// If parent has already been checked, we want to return an error - otherwise
+		// this function will fall into infinite loop. Cycle is ignored when the Action
+		// has already been allowed, as it would not bring any new Permissions.
+		if utils.StringSliceContains(checkedRoles, parent) {
+			if evaluation.isAllowed {
+				request.tracer.add(RoleStep, parent, SkippedOutcome, "inheritance cycle")
+
+				continue
 			}
+
+			err := newRoleInheritanceCycleError(checkedRoles)
+			request.tracer.add(RoleStep, parent, FailedOutcome, err.Error())
+
+			return err
+		}
+
+		if err := am.authorize(request, evaluation, parent, checkedRoles, state); err != nil {
+			return err
 		}
 
-		// If request has not been granted, abort the loop and return an error,
-		// skipping rest of the Actions in the request.
-		if permissionErrors != nil {
-			allPermissionErrors = append(allPermissionErrors, permissionErrors...)
+		if evaluation.isFinal {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// authorizeIndexed - evaluates Permissions of given Role and its Parents for currently evaluated Action,
+// the same way authorize does, but using request's PolicyIndex - Roles are not fetched from PolicyProvider,
+// and ancestors are evaluated in their precompiled, depth-first order.
+func (am *AccessManager) authorizeIndexed(request *AccessRequest, evaluation *actionEvaluation, roleName string) error {
+	if err := request.getContext().Err(); err != nil {
+		return err
+	}
 
-			// If CompleteValidation is false, we want to return early.
-			// Otherwise, loop will continue for the rest of the actions.
-			if !request.CompleteValidation {
-				return allPermissionErrors, nil
+	// Role steps are kept per inheritance depth, so the Trace is nested the same way as with authorize.
+	roleSteps := []*TraceStep{}
+
+	finishRoleSteps := func(depth int) {
+		if depth < len(roleSteps) {
+			request.tracer.finish(roleSteps[depth])
+			roleSteps = roleSteps[:depth]
+		}
+	}
+
+	defer finishRoleSteps(0)
+
+	// Permissions granted for Actions implying evaluated Action are checked as well.
+	actions := append([]string{evaluation.action}, evaluation.impliedBy...)
+
+	for _, ancestor := range request.index.getAncestors(roleName) {
+		if ancestor.isCycle {
+			finishRoleSteps(len(ancestor.path))
+
+			// Cycle is ignored when the Action has already been allowed, as it would not bring any new Permissions.
+			if evaluation.isAllowed {
+				request.tracer.add(RoleStep, ancestor.name, SkippedOutcome, "inheritance cycle")
+
+				continue
 			}
+
+			err := newRoleInheritanceCycleError(ancestor.path)
+			request.tracer.add(RoleStep, ancestor.name, FailedOutcome, err.Error())
+
+			return err
+		}
+
+		depth := len(ancestor.path) - 1
+
+		finishRoleSteps(depth)
+
+		roleStep := request.tracer.start(RoleStep, ancestor.name)
+		roleSteps = append(roleSteps, roleStep)
+
+		if ancestor.role == nil {
+			err := newRoleNotFoundError(ancestor.name)
+
+			if am.canSkipError(request, evaluation) {
+				roleStep.setOutcome(SkippedOutcome, err.Error())
+
+				continue
+			}
+
+			roleStep.setOutcome(FailedOutcome, err.Error())
+
+			return err
+		}
+
+		permissions := ancestor.role.getPermissions(evaluation.resourceName, actions)
+
+		permissionErrors, err := am.validateAction(permissions, ancestor.path, request, evaluation)
+		// If non-policy related error happened, we return it directly.
+		if err != nil {
+			return err
+		}
+
+		// Only errors of Subject's Roles are reported.
+		if depth == 0 {
+			evaluation.permissionErrors[evaluation.roleIndex] = permissionErrors
+		}
+
+		if evaluation.isFinal {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// policyState - the policy a request (or a batch of requests) is evaluated against. Policy snapshot
+// is taken once, so Roles, PolicyIndex and the version used for caching decisions all describe
+// the same version of the policy.
+type policyState struct {
+	// snapshot - policySnapshot used for the evaluation. If PolicyProvider does not provide
+	// snapshots, the policy is nil, and Roles are fetched with PolicyProvider's methods.
+	snapshot *policySnapshot
+	// roles - Roles fetched so far, by their IDs.
+	roles map[string]*Role
+}
+
+// newPolicyState - returns new policyState instance, with a snapshot of the current policy.
+func (am *AccessManager) newPolicyState() *policyState {
+	state := &policyState{
+		roles: map[string]*Role{},
+	}
+
+	if provider, ok := am.policyManager.(policySnapshotProvider); ok {
+		state.snapshot = provider.getSnapshot()
+
+		return state
+	}
+
+	// The version is read first - the policy can only be newer than the version decisions
+	// are cached with, so outdated decisions are never cached under the current version.
+	version := am.getPolicyVersion()
+
+	state.snapshot = &policySnapshot{
+		index:   am.getPolicyIndex(),
+		version: version,
+	}
+
+	return state
+}
+
+// getRole - returns a Role with given name, taking it from the policy snapshot, or fetching it
+// from PolicyProvider if needed.
+func (am *AccessManager) getRole(ctx context.Context, roleName string, state *policyState) (*Role, error) {
+	if role, ok := state.roles[roleName]; ok {
+		return role, nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var role *Role
+	var err error
+
+	if policy := state.snapshot.policy; policy != nil {
+		// Roles are only read during the evaluation, so they are not copied.
+		if role = policy.Roles[roleName]; role == nil {
+			err = newRoleNotFoundError(roleName)
 		}
+	} else if provider, ok := am.policyManager.(ContextPolicyProvider); ok {
+		role, err = provider.GetRoleContext(ctx, roleName)
+	} else {
+		role, err = am.policyManager.GetRole(roleName)
 	}
 
-	if len(allPermissionErrors) > 0 {
-		return allPermissionErrors, nil
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, nil
+	state.roles[roleName] = role
+
+	return role, nil
 }
 
-// validateAction - checks whether a Permission is granted for Action.
-func (am *AccessManager) validateAction(permissions []*Permission, action, roleName string, request *AccessRequest) (PermissionErrors, error) {
+// validateAction - checks Permissions matching currently evaluated Action, adding Effects of the applicable
+// ones to the evaluation. Returns PermissionErrors describing why the Action is not allowed by given
+// Permissions, or nil if it is.
+func (am *AccessManager) validateAction(
+	permissions []*Permission,
+	rolePath []string,
+	request *AccessRequest,
+	evaluation *actionEvaluation,
+) (PermissionErrors, error) {
+	action := evaluation.action
+	resourceName := evaluation.resourceName
+	roleName := rolePath[len(rolePath)-1]
+
 	permissionErrors := PermissionErrors{}
+	isAllowed := false
 
 	for _, permission := range permissions {
-		if permission.Action == action {
-			// If a Permission with given Action is found, and has no Conditions, access should be granted.
-			if len(permission.Conditions) == 0 || request.SkipConditions {
-				return nil, nil
-			}
+		var conditionErrors ConditionErrors
+
+		// Permissions coming from other PolicyProviders may not be validated - unknown Effect
+		// is never treated as allow.
+		if err := permission.Effect.validate(); err != nil {
+			return nil, err
+		}
+
+		permissionStep := request.tracer.start(PermissionStep, permission.Action)
+
+		if len(permission.Conditions) > 0 {
+			evaluation.conditional = true
+		}
+
+		// If a Permission with given Action is found, and has no Conditions, it applies.
+		if len(permission.Conditions) > 0 && !request.SkipConditions {
+			var err error
 
-			conditionErrors, err := am.checkConditions(permission, request)
+			conditionErrors, err = am.checkConditions(permission, request)
 			// If non-policy related error happened, we return it directly.
 			if err != nil {
+				if am.canSkipError(request, evaluation) {
+					permissionStep.setOutcome(SkippedOutcome, err.Error())
+					request.tracer.finish(permissionStep)
+
+					continue
+				}
+
+				permissionStep.setOutcome(FailedOutcome, err.Error())
+				request.tracer.finish(permissionStep)
+
 				return nil, err
 			}
+		}
+
+		request.tracer.finish(permissionStep)
+
+		details := fmt.Sprintf("effect: %s", permission.getEffect())
+
+		if len(permission.Conditions) > 0 && request.SkipConditions {
+			details += ", Conditions skipped"
+		}
 
+		if conditionErrors != nil {
+			permissionStep.setOutcome(NotApplicableOutcome, details)
+		} else {
+			permissionStep.setOutcome(AppliedOutcome, details)
+		}
+
+		if permission.isDeny() {
+			// Deny Permission with failed Conditions does not apply.
+			if conditionErrors != nil {
+				continue
+			}
+
+			permissionError := newPermissionError(action, roleName, resourceName, nil)
+			permissionError.Denied = true
+
+			evaluation.denyErrors[evaluation.roleIndex] = append(evaluation.denyErrors[evaluation.roleIndex], permissionError)
+			evaluation.addEffect(DenyEffect, permission, rolePath, am.combiningAlgorithm)
+		} else {
 			// If error is nil, Conditions have been satisfied.
 			if conditionErrors == nil {
-				return nil, nil
+				isAllowed = true
+				evaluation.addEffect(AllowEffect, permission, rolePath, am.combiningAlgorithm)
+			} else {
+				// Otherwise, we add new PermissionError to result slice.
+				permissionError := newPermissionError(action, roleName, resourceName, conditionErrors)
+				permissionErrors = append(permissionErrors, permissionError)
 			}
+		}
 
-			// Otherwise, we add new PermissionError to result slice.
-			permissionError := newPermissionError(action, roleName, request.Resource.GetResourceName(), conditionErrors)
-			permissionErrors = append(permissionErrors, permissionError)
+		if evaluation.isFinal {
+			break
 		}
 	}
 
+	if isAllowed {
+		return nil, nil
+	}
+
 	// If there are no permissionErrors at this point, this means there was no Permission
 	// for given Action (even one with failing Conditions).
 	// In such case, a PermissionError with no ConditionErrors is added to reflect that.
 	if len(permissionErrors) == 0 {
-		permissionError := newPermissionError(action, roleName, request.Resource.GetResourceName(), nil)
+		permissionError := newPermissionError(action, roleName, resourceName, nil)
 		permissionErrors = append(permissionErrors, permissionError)
 	}
 
 	return permissionErrors, nil
 }
 
+// addEffect - adds an Effect of applicable Permission, and checks if CombiningAlgorithm
+// has reached a final decision.
+func (e *actionEvaluation) addEffect(effect Effect, permission *Permission, rolePath []string, algorithm CombiningAlgorithm) {
+	e.effects = append(e.effects, effect)
+	e.applied = append(e.applied, &AppliedPermission{
+		Permission:      permission,
+		RoleName:        rolePath[len(rolePath)-1],
+		InheritancePath: rolePath,
+		ResourceName:    e.resourceName,
+	})
+
+	if effect == AllowEffect {
+		e.isAllowed = true
+	}
+
+	result, isFinal := algorithm.Combine(e.effects)
+
+	// Without deny Permissions, further Permissions cannot change the allowing result,
+	// so the remaining Roles and Parents are not evaluated.
+	e.isFinal = isFinal || (e.allowIsFinal && result == AllowEffect)
+}
+
+// getApplied - returns applied Permissions with given Effect.
+func (e *actionEvaluation) getApplied(effect Effect) []*AppliedPermission {
+	result := []*AppliedPermission{}
+
+	for i, applied := range e.applied {
+		if e.effects[i] == effect {
+			result = append(result, applied)
+		}
+	}
+
+	return result
+}
+
 // checkConditions - returns nil if all conditions specified for given actions
 // are satisfied, error otherwise.
 func (am *AccessManager) checkConditions(permission *Permission, request *AccessRequest) (ConditionErrors, error) {
@@ -185,28 +792,69 @@ func (am *AccessManager) checkConditions(permission *Permission, request *Access
 		return nil, nil
 	}
 
-	conditionErrors := ConditionErrors{}
+	return permission.Conditions.check(request)
+}
 
-	for _, condition := range permission.Conditions {
-		if err := condition.Check(request); err != nil {
-			// If error returned is ConditionNotSatisfiedError, we add it to the result slice.
-			// Otherwise, we want to abort immediately and return it directly.
-			if conditionError, ok := err.(*ConditionNotSatisfiedError); ok {
-				conditionErrors = append(conditionErrors, conditionError)
+// canSkipError - returns true if an error of currently evaluated Role or Permission can be skipped, because
+// the Action has already been allowed, and strict evaluation is disabled. Context's errors are never skipped.
+func (am *AccessManager) canSkipError(request *AccessRequest, evaluation *actionEvaluation) bool {
+	return evaluation.isAllowed && !am.strictEvaluation && request.getContext().Err() == nil
+}
 
-				// If CompleteValidation is not enabled, we return first encountered error.
-				if !request.CompleteValidation {
-					break
-				}
-			} else {
-				return nil, err
-			}
-		}
+// getPolicyVersion - returns the version of the policy, if PolicyProvider implements PolicyVersionProvider.
+// Returns 0 otherwise.
+func (am *AccessManager) getPolicyVersion() uint64 {
+	provider, ok := am.policyManager.(PolicyVersionProvider)
+	if !ok {
+		return 0
 	}
 
-	if len(conditionErrors) > 0 {
-		return conditionErrors, nil
+	return provider.GetPolicyVersion()
+}
+
+// getCachedDecision - returns cached ActionDecision for given Action, or nil if there is none
+// or the decision cannot be cached for given request.
+func (am *AccessManager) getCachedDecision(
+	request *AccessRequest,
+	roles []string,
+	resourceName string,
+	action string,
+	version uint64,
+) *ActionDecision {
+	if am.decisionCache == nil || request.Trace {
+		return nil
 	}
 
-	return nil, nil
+	now := request.Now()
+
+	if decision := am.decisionCache.get(getDecisionCacheKey(request, roles, resourceName, action, false), version, now); decision != nil {
+		return decision
+	}
+
+	if request.CacheKey == "" {
+		return nil
+	}
+
+	return am.decisionCache.get(getDecisionCacheKey(request, roles, resourceName, action, true), version, now)
+}
+
+// cacheDecision - caches given ActionDecision, if it's possible for given request.
+func (am *AccessManager) cacheDecision(
+	request *AccessRequest,
+	roles []string,
+	resourceName string,
+	decision *ActionDecision,
+	version uint64,
+) {
+	if am.decisionCache == nil || request.Trace {
+		return
+	}
+
+	if decision.conditional && request.CacheKey == "" {
+		return
+	}
+
+	key := getDecisionCacheKey(request, roles, resourceName, decision.Action, decision.conditional)
+
+	am.decisionCache.set(key, decision, version, request.Now())
 }
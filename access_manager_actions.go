@@ -0,0 +1,242 @@
+package restrict
+
+import (
+	"context"
+
+	"github.com/el-mike/restrict/v2/internal/utils"
+)
+
+// AllowedActions - returns all Actions that given Subject is allowed to perform on given Resource,
+// with given Context. Candidate Actions are collected from Permissions granted for the Resource
+// (and its ancestors) through Subject's Roles and their parents, along with the Actions they imply.
+// Every candidate is then evaluated as with Authorize, including Conditions and deny Permissions.
+// Action patterns (e.g. "*") are expanded to matching Actions known from the policy - used in its
+// Permissions or ActionHierarchy, when PolicyIndex is available, or granted explicitly otherwise.
+func (am *AccessManager) AllowedActions(subject Subject, resource Resource, requestContext Context) ([]string, error) {
+	return am.AllowedActionsContext(context.Background(), subject, resource, requestContext)
+}
+
+// AllowedActionsContext - works as AllowedActions, but given context.Context is passed to Conditions
+// and PolicyProvider, as with AuthorizeContext.
+func (am *AccessManager) AllowedActionsContext(
+	ctx context.Context,
+	subject Subject,
+	resource Resource,
+	requestContext Context,
+) ([]string, error) {
+	request := &AccessRequest{
+		Subject:  subject,
+		Resource: resource,
+		Context:  requestContext,
+	}
+
+	if subject == nil || resource == nil {
+		return am.allowedActions(ctx, request, nil)
+	}
+
+	state := am.newPolicyState()
+
+	candidates, err := am.collectActions(ctx, subject.GetRoles(), resource, state)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Actions = candidates
+
+	return am.allowedActions(ctx, request, state)
+}
+
+// allowedActions - evaluates given AccessRequest within given context, and returns its allowed Actions.
+func (am *AccessManager) allowedActions(
+	ctx context.Context,
+	request *AccessRequest,
+	state *policyState,
+) ([]string, error) {
+	if state == nil {
+		state = am.newPolicyState()
+	}
+
+	decision, err := am.evaluate(ctx, request, false, state)
+	if err != nil {
+		return nil, err
+	}
+
+	actions := []string{}
+
+	for _, actionDecision := range decision.Actions {
+		if actionDecision.Allowed {
+			actions = append(actions, actionDecision.Action)
+		}
+	}
+
+	return actions, nil
+}
+
+// grantedActions - Actions collected from Permissions granted for a Resource.
+type grantedActions struct {
+	// actions - collected Actions, along with the Actions they imply, in order of discovery.
+	actions []string
+	// patterns - collected Action patterns, along with Resource names they are granted for.
+	patterns []*grantedActionPattern
+}
+
+// grantedActionPattern - Action pattern granted for a Resource name.
+type grantedActionPattern struct {
+	pattern      string
+	resourceName string
+}
+
+// collectActions - returns all Actions of Permissions granted for given Resource and its ancestors through
+// given Roles and their parents, along with Actions they imply, in order of discovery. Action patterns
+// are expanded to the known Actions they match (see getKnownActions), and follow the other Actions.
+func (am *AccessManager) collectActions(
+	ctx context.Context,
+	roles []string,
+	resource Resource,
+	state *policyState,
+) ([]string, error) {
+	granted := &grantedActions{
+		actions: []string{},
+	}
+
+	hierarchy := newResourceHierarchy(resource, resource.GetResourceName())
+
+	for {
+		// Roles reachable through multiple Roles or inheritance paths are checked only once.
+		visited := map[string]bool{}
+
+		for _, roleName := range roles {
+			if err := am.collectRoleActions(ctx, roleName, hierarchy.name, state, visited, granted); err != nil {
+				return nil, err
+			}
+		}
+
+		hasParent, err := hierarchy.next(am.getResourceDefinition(state, hierarchy.name))
+		if err != nil {
+			return nil, err
+		}
+
+		if !hasParent {
+			break
+		}
+	}
+
+	known := am.getKnownActions(state, granted.actions)
+
+	for _, pattern := range granted.patterns {
+		for _, action := range known {
+			if utils.MatchPattern(pattern.pattern, action) {
+				am.addGrantedAction(pattern.resourceName, action, &granted.actions)
+			}
+		}
+	}
+
+	return granted.actions, nil
+}
+
+// collectRoleActions - collects Actions granted for given Resource name by given Role and its parents,
+// skipping already visited Roles. When PolicyIndex is available, Role's flattened ancestors are used.
+// Inheritance cycles are skipped - they will be reported while evaluating the Actions, if relevant.
+func (am *AccessManager) collectRoleActions(
+	ctx context.Context,
+	roleName string,
+	resourceName string,
+	state *policyState,
+	visited map[string]bool,
+	granted *grantedActions,
+) error {
+	if index := state.snapshot.index; index != nil {
+		for _, ancestor := range index.getAncestors(roleName) {
+			if ancestor.isCycle || visited[ancestor.name] {
+				continue
+			}
+
+			visited[ancestor.name] = true
+
+			role, err := am.getRole(ctx, ancestor.name, state)
+			if err != nil {
+				return err
+			}
+
+			am.collectGrantedActions(role, resourceName, granted)
+		}
+
+		return nil
+	}
+
+	if visited[roleName] {
+		return nil
+	}
+
+	visited[roleName] = true
+
+	role, err := am.getRole(ctx, roleName, state)
+	if err != nil {
+		return err
+	}
+
+	am.collectGrantedActions(role, resourceName, granted)
+
+	for _, parent := range role.Parents {
+		if err := am.collectRoleActions(ctx, parent, resourceName, state, visited, granted); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// collectGrantedActions - collects Actions of given Role's Permissions granted for given Resource name.
+func (am *AccessManager) collectGrantedActions(role *Role, resourceName string, granted *grantedActions) {
+	for _, key := range role.Grants.matching(resourceName) {
+		for _, permission := range role.Grants[key] {
+			if permission.Action == "" {
+				continue
+			}
+
+			if utils.IsPattern(permission.Action) {
+				granted.patterns = append(granted.patterns, &grantedActionPattern{
+					pattern:      permission.Action,
+					resourceName: resourceName,
+				})
+
+				continue
+			}
+
+			am.addGrantedAction(resourceName, permission.Action, &granted.actions)
+		}
+	}
+}
+
+// addGrantedAction - adds given Action and the Actions it implies for given Resource name
+// to given Actions, unless they're already added.
+func (am *AccessManager) addGrantedAction(resourceName, action string, actions *[]string) {
+	candidates := append([]string{action}, am.getImpliedActions(resourceName, action)...)
+
+	for _, candidate := range candidates {
+		if !utils.StringSliceContains(*actions, candidate) {
+			*actions = append(*actions, candidate)
+		}
+	}
+}
+
+// getKnownActions - returns Actions Action patterns can be expanded to: all Actions used in the policy's
+// Permissions and ActionHierarchy when PolicyIndex is available, or given collected Actions otherwise.
+func (am *AccessManager) getKnownActions(state *policyState, collected []string) []string {
+	if index := state.snapshot.index; index != nil {
+		return index.actions
+	}
+
+	return append([]string{}, collected...)
+}
+
+// getImpliedActions - returns Actions implied by given Action for given Resource name, if PolicyProvider
+// implements ActionHierarchyProvider. Returns nil otherwise.
+func (am *AccessManager) getImpliedActions(resourceName, action string) []string {
+	provider, ok := am.policyManager.(ActionHierarchyProvider)
+	if !ok {
+		return nil
+	}
+
+	return provider.GetImpliedActions(resourceName, action)
+}
@@ -0,0 +1,236 @@
+package restrict
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type accessManagerActionsSuite struct {
+	suite.Suite
+}
+
+func TestAccessManagerActionsSuite(t *testing.T) {
+	suite.Run(t, new(accessManagerActionsSuite))
+}
+
+func (s *accessManagerActionsSuite) TestAllowedActions() {
+	testRole := getBasicRoleOne()
+	testParentRole := getBasicParentRole()
+
+	testRole.Parents = []string{testParentRole.ID}
+
+	testRole.Grants[basicResourceOneName] = append(
+		testRole.Grants[basicResourceOneName],
+		&Permission{
+			Action: "share",
+			Conditions: Conditions{
+				&EqualCondition{
+					Left:  &ValueDescriptor{Source: ResourceField, Field: "CreatedBy"},
+					Right: &ValueDescriptor{Source: ContextField, Field: "UserID"},
+				},
+			},
+		},
+		&Permission{Action: "*"},
+		&Permission{Action: createAction, Effect: DenyEffect},
+	)
+
+	testPolicyProvider := new(extendedPolicyProviderMock)
+	testPolicyProvider.On("GetRole", basicRoleOneName).Return(testRole, nil)
+	testPolicyProvider.On("GetRole", basicParentRoleName).Return(testParentRole, nil)
+
+	manager := NewAccessManager(testPolicyProvider)
+
+	testSubject := new(subjectMock)
+	testSubject.On("GetRoles").Return([]string{basicRoleOneName})
+
+	testResource := &resourceMock{CreatedBy: "user-1"}
+	testResource.On("GetResourceName").Return(basicResourceOneName)
+
+	actions, err := manager.AllowedActions(testSubject, testResource, Context{"UserID": "user-2"})
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{readAction, updateAction}, actions)
+
+	// Conditions are checked against given Resource and Context.
+	actions, err = manager.AllowedActions(testSubject, testResource, Context{"UserID": "user-1"})
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{readAction, "share", updateAction}, actions)
+
+	// Implied Actions.
+	testPolicyProvider.actions = &ActionHierarchy{
+		Global: ActionImplications{
+			updateAction: {"rename", "move"},
+		},
+	}
+
+	actions, err = manager.AllowedActions(testSubject, testResource, nil)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{readAction, updateAction, "rename", "move"}, actions)
+
+	// Ancestors' Permissions.
+	testProjectRole := &Role{
+		ID: "ProjectMember",
+		Grants: GrantsMap{
+			"Project": {&Permission{Action: "comment"}},
+		},
+	}
+
+	testPolicyProvider.On("GetRole", testProjectRole.ID).Return(testProjectRole, nil)
+
+	testDocument := &hierarchicalResourceMock{Name: "Document", Parent: &hierarchicalResourceMock{Name: "Project"}}
+
+	testSubject = new(subjectMock)
+	testSubject.On("GetRoles").Return([]string{testProjectRole.ID})
+
+	actions, err = manager.AllowedActions(testSubject, testDocument, nil)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{"comment"}, actions)
+
+	// No Actions.
+	testProjectRole.Grants = nil
+
+	actions, err = manager.AllowedActions(testSubject, testDocument, nil)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{}, actions)
+}
+
+func (s *accessManagerActionsSuite) TestAllowedActions_Patterns() {
+	testAdminRole := &Role{
+		ID: "Admin",
+		Grants: GrantsMap{
+			"*": {&Permission{Action: "*"}},
+			basicResourceOneName: {
+				&Permission{Action: readAction},
+				&Permission{Action: "export:*"},
+				&Permission{Action: deleteAction, Effect: DenyEffect},
+			},
+		},
+	}
+
+	testPolicy := &PolicyDefinition{
+		Roles: Roles{
+			testAdminRole.ID: testAdminRole,
+			basicRoleOneName: getBasicRoleOne(),
+		},
+		Actions: &ActionHierarchy{
+			Global: ActionImplications{
+				"manage": {updateAction, deleteAction},
+			},
+			Resources: map[string]ActionImplications{
+				basicResourceOneName: {"export:pdf": {"export:csv"}},
+			},
+		},
+	}
+
+	testAdapter := new(storageAdapterMock)
+	testAdapter.On("LoadPolicy").Return(testPolicy, nil)
+
+	policyManager, _ := NewPolicyManager(testAdapter, false)
+
+	manager := NewAccessManager(policyManager)
+
+	// Action patterns are expanded to Actions used in the policy and its ActionHierarchy.
+	actions, err := manager.AllowedActions(UseSubject([]string{testAdminRole.ID}), UseResource(basicResourceOneName), nil)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{readAction, "export:csv", "export:pdf", createAction, "manage", updateAction}, actions)
+
+	// Without PolicyIndex, patterns are expanded to Actions granted explicitly.
+	manager = NewAccessManager(newNotIndexedPolicyProvider(policyManager))
+
+	actions, err = manager.AllowedActions(UseSubject([]string{testAdminRole.ID}), UseResource(basicResourceOneName), nil)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{readAction}, actions)
+}
+
+func (s *accessManagerActionsSuite) TestAllowedActions_DiamondHierarchy() {
+	testPolicy := getDiamondHierarchyPolicy(30)
+
+	testAdapter := new(storageAdapterMock)
+	testAdapter.On("LoadPolicy").Return(testPolicy, nil)
+
+	policyManager, _ := NewPolicyManager(testAdapter, false)
+
+	// Every Role is visited once - otherwise the number of inheritance paths (2^30) would never be traversed.
+	actions, err := NewAccessManager(policyManager).AllowedActions(UseSubject([]string{"Role0", "Role1A"}), UseResource(basicResourceOneName), nil)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{readAction}, actions)
+
+	// Without PolicyIndex, Actions are collected with a single traversal as well.
+	manager := NewAccessManager(newNotIndexedPolicyProvider(policyManager))
+
+	granted, err := manager.collectActions(context.Background(), []string{"Role0", "Role1A"}, UseResource(basicResourceOneName), manager.newPolicyState())
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{readAction}, granted)
+}
+
+func (s *accessManagerActionsSuite) TestAllowedActions_Errors() {
+	testPolicyProvider := new(policyProviderMock)
+	testPolicyProvider.On("GetRole", mock.Anything).Return(nil, assert.AnError)
+
+	manager := NewAccessManager(testPolicyProvider)
+
+	testSubject := new(subjectMock)
+	testResource := new(resourceMock)
+
+	testResource.On("GetResourceName").Return(basicResourceOneName)
+
+	_, err := manager.AllowedActions(nil, testResource, nil)
+
+	assert.IsType(s.T(), new(RequestMalformedError), err)
+
+	testSubject.On("GetRoles").Return([]string{}).Twice()
+
+	_, err = manager.AllowedActions(testSubject, testResource, nil)
+
+	assert.IsType(s.T(), new(RequestMalformedError), err)
+
+	testSubject.On("GetRoles").Return([]string{basicRoleOneName})
+
+	_, err = manager.AllowedActions(testSubject, testResource, nil)
+
+	assert.Equal(s.T(), assert.AnError, err)
+}
+
+func (s *accessManagerActionsSuite) TestAllowedActionsContext() {
+	type contextKey string
+
+	ctx := context.WithValue(context.Background(), contextKey("key"), "value")
+
+	testPolicyProvider := new(contextPolicyProviderMock)
+	testPolicyProvider.On("GetRoleContext", ctx, basicRoleOneName).Return(getBasicRoleOne(), nil)
+
+	manager := NewAccessManager(testPolicyProvider)
+
+	testSubject := new(subjectMock)
+	testSubject.On("GetRoles").Return([]string{basicRoleOneName})
+
+	testResource := new(resourceMock)
+	testResource.On("GetResourceName").Return(basicResourceOneName)
+
+	actions, err := manager.AllowedActionsContext(ctx, testSubject, testResource, nil)
+
+	assert.Nil(s.T(), err)
+	assert.NotEmpty(s.T(), actions)
+	testPolicyProvider.AssertCalled(s.T(), "GetRoleContext", ctx, basicRoleOneName)
+	testPolicyProvider.AssertNotCalled(s.T(), "GetRole", mock.Anything)
+
+	// Canceled context.
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	_, err = manager.AllowedActionsContext(canceledCtx, testSubject, testResource, nil)
+
+	assert.Equal(s.T(), context.Canceled, err)
+}
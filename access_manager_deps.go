@@ -1,7 +1,69 @@
 package restrict
 
+import (
+	"context"
+	"time"
+)
+
 // PolicyProvider - interface for an entity that will provide Role configuration
 // for AccessProvider.
 type PolicyProvider interface {
 	GetRole(roleID string) (*Role, error)
 }
+
+// ContextPolicyProvider - optional interface for PolicyProvider, providing Roles within given context.
+// When implemented, it's used instead of GetRole, receiving the context passed to AuthorizeContext.
+type ContextPolicyProvider interface {
+	// GetRoleContext - returns a Role with given ID, honoring given context's cancellation.
+	GetRoleContext(ctx context.Context, roleID string) (*Role, error)
+}
+
+// policySnapshotProvider - implemented by PolicyManager, providing the whole policySnapshot at once,
+// so AccessManager never mixes different versions of the policy within a single evaluation.
+type policySnapshotProvider interface {
+	getSnapshot() *policySnapshot
+}
+
+// PolicyVersionProvider - optional interface for PolicyProvider, providing the version of the policy.
+// The version has to increase whenever the policy changes, so DecisionCache can drop outdated decisions.
+type PolicyVersionProvider interface {
+	// GetPolicyVersion - returns current version of the policy.
+	GetPolicyVersion() uint64
+}
+
+// PolicyIndexProvider - optional interface for PolicyProvider, providing PolicyIndex compiled from
+// the current policy. When implemented, Roles are not fetched with GetRole while evaluating Permissions.
+type PolicyIndexProvider interface {
+	// GetPolicyIndex - returns PolicyIndex of the current policy.
+	GetPolicyIndex() *PolicyIndex
+}
+
+// ResourceDefinitionProvider - optional interface for PolicyProvider, providing ResourceDefinitions
+// describing the hierarchy of Resources.
+type ResourceDefinitionProvider interface {
+	// GetResourceDefinition - returns ResourceDefinition for given Resource name, or nil
+	// if Resource has no definition.
+	GetResourceDefinition(resourceName string) *ResourceDefinition
+}
+
+// ActionHierarchyProvider - optional interface for PolicyProvider, providing Actions that imply other Actions.
+type ActionHierarchyProvider interface {
+	// GetImplyingActions - returns all Actions implying given Action for given Resource, directly or not.
+	GetImplyingActions(resourceName, action string) []string
+	// GetImpliedActions - returns all Actions implied by given Action for given Resource, directly or not.
+	GetImpliedActions(resourceName, action string) []string
+}
+
+// Clock - interface for an entity that will provide current time for AccessManager.
+// Custom implementation can be used to make time-based Conditions deterministic.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock - default Clock, returning system time.
+type systemClock struct{}
+
+// Now - returns current system time.
+func (c systemClock) Now() time.Time {
+	return time.Now()
+}
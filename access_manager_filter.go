@@ -0,0 +1,80 @@
+package restrict
+
+import "context"
+
+// DeniedResource - describes a Resource filtered out by FilterAuthorized.
+type DeniedResource struct {
+	// Index - position of the Resource in the filtered slice.
+	Index int
+	// Resource - the Resource that has been filtered out.
+	Resource Resource
+	// Reasons - PermissionErrors describing why the access to the Resource is not granted.
+	Reasons PermissionErrors
+}
+
+// FilterResult - describes the result of FilterAuthorized.
+type FilterResult struct {
+	// Authorized - Resources the access is granted to, in their original order.
+	Authorized []Resource
+	// Denied - Resources the access is not granted to, in their original order.
+	Denied []*DeniedResource
+}
+
+// FilterAuthorized - checks whether given Subject can perform given Actions on every one of given Resources,
+// with given Context, and returns Resources the access is granted to, along with the reasons for every
+// denied one. The whole batch is evaluated against a single version of the policy - Roles (and their parents)
+// are fetched from PolicyProvider once for the whole batch. Every Resource is validated as with Authorize
+// and CompleteValidation set to false, so only the first denied Action is described. Errors other than
+// denied access are returned immediately.
+func (am *AccessManager) FilterAuthorized(
+	subject Subject,
+	actions []string,
+	resources []Resource,
+	requestContext Context,
+) (*FilterResult, error) {
+	return am.FilterAuthorizedContext(context.Background(), subject, actions, resources, requestContext)
+}
+
+// FilterAuthorizedContext - works as FilterAuthorized, but given context.Context is passed to Conditions
+// and PolicyProvider, as with AuthorizeContext.
+func (am *AccessManager) FilterAuthorizedContext(
+	ctx context.Context,
+	subject Subject,
+	actions []string,
+	resources []Resource,
+	requestContext Context,
+) (*FilterResult, error) {
+	result := &FilterResult{
+		Authorized: []Resource{},
+		Denied:     []*DeniedResource{},
+	}
+
+	state := am.newPolicyState()
+
+	for index, resource := range resources {
+		request := &AccessRequest{
+			Subject:  subject,
+			Resource: resource,
+			Actions:  actions,
+			Context:  requestContext,
+		}
+
+		decision, err := am.evaluate(ctx, request, true, state)
+		if err != nil {
+			return nil, err
+		}
+
+		if decision.Allowed {
+			result.Authorized = append(result.Authorized, resource)
+			continue
+		}
+
+		result.Denied = append(result.Denied, &DeniedResource{
+			Index:    index,
+			Resource: resource,
+			Reasons:  decision.Reasons(),
+		})
+	}
+
+	return result, nil
+}
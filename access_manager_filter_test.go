@@ -0,0 +1,197 @@
+package restrict
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type accessManagerFilterSuite struct {
+	suite.Suite
+}
+
+func TestAccessManagerFilterSuite(t *testing.T) {
+	suite.Run(t, new(accessManagerFilterSuite))
+}
+
+func (s *accessManagerFilterSuite) TestFilterAuthorized() {
+	testRole := getBasicRoleOne()
+	testParentRole := getBasicParentRole()
+
+	testRole.Parents = []string{testParentRole.ID}
+	testRole.Grants[basicResourceOneName] = append(testRole.Grants[basicResourceOneName], &Permission{
+		Action: "share",
+		Conditions: Conditions{
+			&EqualCondition{
+				Left:  &ValueDescriptor{Source: ResourceField, Field: "CreatedBy"},
+				Right: &ValueDescriptor{Source: ContextField, Field: "UserID"},
+			},
+		},
+	})
+
+	testPolicyProvider := new(policyProviderMock)
+	testPolicyProvider.On("GetRole", basicRoleOneName).Return(testRole, nil)
+	testPolicyProvider.On("GetRole", basicParentRoleName).Return(testParentRole, nil)
+
+	manager := NewAccessManager(testPolicyProvider)
+
+	testSubject := new(subjectMock)
+	testSubject.On("GetRoles").Return([]string{basicRoleOneName})
+
+	resources := []Resource{}
+
+	for _, createdBy := range []string{"user-1", "user-2", "user-1", "user-3"} {
+		testResource := &resourceMock{CreatedBy: createdBy}
+		testResource.On("GetResourceName").Return(basicResourceOneName)
+
+		resources = append(resources, testResource)
+	}
+
+	result, err := manager.FilterAuthorized(testSubject, []string{readAction, "share"}, resources, Context{"UserID": "user-1"})
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []Resource{resources[0], resources[2]}, result.Authorized)
+	assert.Len(s.T(), result.Denied, 2)
+
+	assert.Equal(s.T(), 1, result.Denied[0].Index)
+	assert.Equal(s.T(), resources[1], result.Denied[0].Resource)
+	assert.Len(s.T(), result.Denied[0].Reasons, 1)
+	assert.Equal(s.T(), "share", result.Denied[0].Reasons[0].Action)
+
+	assert.Equal(s.T(), 3, result.Denied[1].Index)
+	assert.Equal(s.T(), resources[3], result.Denied[1].Resource)
+
+	// Roles are fetched once per batch.
+	testPolicyProvider.AssertNumberOfCalls(s.T(), "GetRole", 2)
+
+	// Empty batch.
+	result, err = manager.FilterAuthorized(testSubject, []string{readAction}, []Resource{}, nil)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []Resource{}, result.Authorized)
+	assert.Equal(s.T(), []*DeniedResource{}, result.Denied)
+}
+
+func (s *accessManagerFilterSuite) TestFilterAuthorized_Errors() {
+	testPolicyProvider := new(policyProviderMock)
+	testPolicyProvider.On("GetRole", mock.Anything).Return(nil, assert.AnError)
+
+	manager := NewAccessManager(testPolicyProvider)
+
+	testSubject := new(subjectMock)
+	testSubject.On("GetRoles").Return([]string{basicRoleOneName})
+
+	testResource := new(resourceMock)
+	testResource.On("GetResourceName").Return(basicResourceOneName)
+
+	result, err := manager.FilterAuthorized(testSubject, []string{readAction}, []Resource{testResource, nil}, nil)
+
+	assert.Nil(s.T(), result)
+	assert.Equal(s.T(), assert.AnError, err)
+
+	result, err = manager.FilterAuthorized(testSubject, []string{readAction}, []Resource{nil}, nil)
+
+	assert.Nil(s.T(), result)
+	assert.IsType(s.T(), new(RequestMalformedError), err)
+}
+
+func (s *accessManagerFilterSuite) TestFilterAuthorized_SinglePolicyVersion() {
+	testRole := getBasicRoleOne()
+
+	testAdapter := new(storageAdapterMock)
+	testAdapter.On("LoadPolicy").Return(&PolicyDefinition{
+		Roles: Roles{basicRoleOneName: testRole},
+	}, nil)
+
+	policyManager, _ := NewPolicyManager(testAdapter, false)
+
+	deleted := false
+
+	// Condition removes the Permission granting read Action while the batch is evaluated.
+	testCondition := new(conditionMock)
+	testCondition.On("Type").Return(basicConditionOne)
+	testCondition.On("Check").Return(nil).Run(func(args mock.Arguments) {
+		if !deleted {
+			deleted = true
+			_ = policyManager.DeletePermission(basicRoleOneName, basicResourceOneName, readAction)
+		}
+	})
+
+	assert.Nil(s.T(), policyManager.AddPermission(basicRoleOneName, basicResourceOneName, &Permission{
+		Action:     "share",
+		Conditions: Conditions{testCondition},
+	}))
+
+	manager := NewAccessManager(policyManager)
+
+	testSubject := new(subjectMock)
+	testSubject.On("GetRoles").Return([]string{basicRoleOneName})
+
+	resources := []Resource{}
+
+	for i := 0; i < 3; i++ {
+		testResource := new(resourceMock)
+		testResource.On("GetResourceName").Return(basicResourceOneName)
+
+		resources = append(resources, testResource)
+	}
+
+	result, err := manager.FilterAuthorized(testSubject, []string{"share", readAction}, resources, nil)
+
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), deleted)
+	assert.Equal(s.T(), resources, result.Authorized)
+	assert.Empty(s.T(), result.Denied)
+
+	// Next batch uses the changed policy.
+	result, err = manager.FilterAuthorized(testSubject, []string{readAction}, resources, nil)
+
+	assert.Nil(s.T(), err)
+	assert.Empty(s.T(), result.Authorized)
+	assert.Len(s.T(), result.Denied, 3)
+}
+
+func (s *accessManagerFilterSuite) TestFilterAuthorizedContext() {
+	type contextKey string
+
+	ctx := context.WithValue(context.Background(), contextKey("key"), "value")
+
+	testContextCondition := new(contextConditionMock)
+	testContextCondition.On("CheckContext", ctx).Return(nil)
+
+	testRole := getBasicRoleOne()
+	testRole.Grants[basicResourceOneName] = Permissions{
+		&Permission{Action: readAction, Conditions: Conditions{testContextCondition}},
+	}
+
+	testPolicyProvider := new(contextPolicyProviderMock)
+	testPolicyProvider.On("GetRoleContext", ctx, basicRoleOneName).Return(testRole, nil)
+
+	manager := NewAccessManager(testPolicyProvider)
+
+	testSubject := new(subjectMock)
+	testSubject.On("GetRoles").Return([]string{basicRoleOneName})
+
+	testResource := new(resourceMock)
+	testResource.On("GetResourceName").Return(basicResourceOneName)
+
+	result, err := manager.FilterAuthorizedContext(ctx, testSubject, []string{readAction}, []Resource{testResource, testResource}, nil)
+
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), result.Authorized, 2)
+	testContextCondition.AssertNumberOfCalls(s.T(), "CheckContext", 2)
+	testPolicyProvider.AssertNumberOfCalls(s.T(), "GetRoleContext", 1)
+	testPolicyProvider.AssertNotCalled(s.T(), "GetRole", mock.Anything)
+
+	// Canceled context.
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	result, err = manager.FilterAuthorizedContext(canceledCtx, testSubject, []string{readAction}, []Resource{testResource}, nil)
+
+	assert.Nil(s.T(), result)
+	assert.Equal(s.T(), context.Canceled, err)
+}
@@ -0,0 +1,595 @@
+package restrict
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/el-mike/restrict/v2/internal/utils"
+)
+
+// partialPermission - a Permission applicable to partially evaluated Action, with its Conditions
+// translated into a Predicate.
+type partialPermission struct {
+	effect    Effect
+	predicate Predicate
+}
+
+// PartialEvaluate - evaluates the policy for given Subject, Action and Resource name (a Resource type,
+// with no concrete instance), and returns a residual Predicate, describing which Resources of that type
+// the Action can be performed on. Conditions that do not reference ResourceField are checked right away,
+// the remaining ones are translated into FieldPredicates - returned Predicate can be then rendered
+// into a query (e.g. SQL WHERE clause) with a PredicateRenderer.
+// Ancestors declared in ResourceDefinitions are taken into account, but HierarchicalResource's parents
+// are not known without an instance. Returns PartialEvaluationError if any of the Conditions cannot
+// be translated, or custom CombiningAlgorithm is used.
+func (am *AccessManager) PartialEvaluate(subject Subject, action, resourceName string, requestContext Context) (Predicate, error) {
+	return am.PartialEvaluateRequest(&AccessRequest{
+		Subject: subject,
+		Actions: []string{action},
+		Context: requestContext,
+	}, resourceName)
+}
+
+// PartialEvaluateRequest - works the same way as PartialEvaluate, but takes Subject, Context and options
+// (e.g. SkipConditions) from given AccessRequest, which needs to contain exactly one Action.
+// Request's Resource is ignored - Resources are described by given Resource name instead.
+func (am *AccessManager) PartialEvaluateRequest(request *AccessRequest, resourceName string) (Predicate, error) {
+	if request == nil || request.Subject == nil {
+		return nil, newRequestMalformedError(request, fmt.Errorf("Subject not defined"))
+	}
+
+	roles := request.Subject.GetRoles()
+
+	if len(roles) == 0 || resourceName == "" {
+		return nil, newRequestMalformedError(request, fmt.Errorf("missing roles or resourceName"))
+	}
+
+	if len(request.Actions) != 1 {
+		return nil, newRequestMalformedError(request, fmt.Errorf("exactly one Action is required"))
+	}
+
+	action := request.Actions[0]
+
+	if action == "" {
+		return nil, newRequestMalformedError(request, fmt.Errorf("action cannot be empty"))
+	}
+
+	// Current time is captured once, so all Conditions are checked against the same moment.
+	request = request.withTime(am.clock.Now())
+	request.resolvedValues = map[string]interface{}{}
+
+	state := am.newPolicyState()
+	request.index = state.snapshot.index
+
+	allowedPredicates := []Predicate{}
+	appliesPredicates := []Predicate{}
+
+	hierarchy := newResourceHierarchy(nil, resourceName)
+
+	for {
+		actions := append([]string{action}, am.getImplyingActions(request, hierarchy.name, action)...)
+		permissions := []*partialPermission{}
+
+		for _, roleName := range roles {
+			var err error
+
+			if request.index != nil {
+				err = am.partialAuthorizeIndexed(request, actions, hierarchy.name, roleName, &permissions)
+			} else {
+				err = am.partialAuthorize(request, actions, hierarchy.name, roleName, []string{}, state, &permissions)
+			}
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		allowed, applies, err := am.combinePredicates(permissions)
+		if err != nil {
+			return nil, err
+		}
+
+		allowedPredicates = append(allowedPredicates, allowed)
+		appliesPredicates = append(appliesPredicates, applies)
+
+		hasParent, err := hierarchy.next(am.getResourceDefinition(state, hierarchy.name))
+		if err != nil {
+			return nil, err
+		}
+
+		if !hasParent {
+			break
+		}
+	}
+
+	// Ancestor's Permissions are checked only when none of the Permissions applies to the Resource,
+	// therefore Predicates are folded starting with the furthest ancestor.
+	var result Predicate = newConstantPredicate(false)
+
+	for i := len(allowedPredicates) - 1; i >= 0; i-- {
+		result = newOrPredicate(
+			allowedPredicates[i],
+			newAndPredicate(newNotPredicate(appliesPredicates[i]), result),
+		)
+	}
+
+	return result, nil
+}
+
+// partialAuthorize - collects Permissions granted for given Actions and Resource name by given Role
+// and its Parents (depth-first), with their Conditions translated into Predicates.
+// Inheritance cycles are skipped, as they would not bring any new Permissions.
+func (am *AccessManager) partialAuthorize(
+	request *AccessRequest,
+	actions []string,
+	resourceName string,
+	roleName string,
+	checkedRoles []string,
+	state *policyState,
+	permissions *[]*partialPermission,
+) error {
+	role, err := am.getRole(request.getContext(), roleName, state)
+	if err != nil {
+		return err
+	}
+
+	if err := addPartialPermissions(role.Grants.forActions(resourceName, actions), request, permissions); err != nil {
+		return err
+	}
+
+	checkedRoles = append(append([]string{}, checkedRoles...), roleName)
+
+	for _, parent := range role.Parents {
+		if utils.StringSliceContains(checkedRoles, parent) {
+			continue
+		}
+
+		if err := am.partialAuthorize(request, actions, resourceName, parent, checkedRoles, state, permissions); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// partialAuthorizeIndexed - collects Permissions the same way partialAuthorize does, but using request's
+// PolicyIndex - Roles are not fetched from PolicyProvider, and ancestors are visited in their precompiled order.
+func (am *AccessManager) partialAuthorizeIndexed(
+	request *AccessRequest,
+	actions []string,
+	resourceName string,
+	roleName string,
+	permissions *[]*partialPermission,
+) error {
+	if err := request.getContext().Err(); err != nil {
+		return err
+	}
+
+	for _, ancestor := range request.index.getAncestors(roleName) {
+		if ancestor.isCycle {
+			continue
+		}
+
+		if ancestor.role == nil {
+			return newRoleNotFoundError(ancestor.name)
+		}
+
+		if err := addPartialPermissions(ancestor.role.getPermissions(resourceName, actions), request, permissions); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addPartialPermissions - appends given Permissions, with their Conditions translated into Predicates,
+// to the permissions slice. When request's SkipConditions is true, all Permissions apply.
+func addPartialPermissions(granted Permissions, request *AccessRequest, permissions *[]*partialPermission) error {
+	for _, permission := range granted {
+		if err := permission.Effect.validate(); err != nil {
+			return err
+		}
+
+		var predicate Predicate = newConstantPredicate(true)
+
+		if !request.SkipConditions {
+			var err error
+
+			if predicate, err = partialConditions(permission.Conditions, request); err != nil {
+				return err
+			}
+		}
+
+		*permissions = append(*permissions, &partialPermission{
+			effect:    permission.getEffect(),
+			predicate: predicate,
+		})
+	}
+
+	return nil
+}
+
+// combinePredicates - combines Predicates of given Permissions according to AccessManager's
+// CombiningAlgorithm. Returns a Predicate describing when the Action is allowed, and a Predicate
+// describing when any of the Permissions applies.
+func (am *AccessManager) combinePredicates(permissions []*partialPermission) (Predicate, Predicate, error) {
+	allows := []Predicate{}
+	denies := []Predicate{}
+	all := []Predicate{}
+
+	// For FirstApplicable, an allowing Permission decides only when none of the previous ones applies.
+	firstApplicable := []Predicate{}
+
+	for i, permission := range permissions {
+		all = append(all, permission.predicate)
+
+		if permission.effect == DenyEffect {
+			denies = append(denies, permission.predicate)
+			continue
+		}
+
+		allows = append(allows, permission.predicate)
+
+		previous := []Predicate{permission.predicate}
+
+		for _, other := range permissions[:i] {
+			previous = append(previous, newNotPredicate(other.predicate))
+		}
+
+		firstApplicable = append(firstApplicable, newAndPredicate(previous...))
+	}
+
+	applies := newOrPredicate(all...)
+
+	algorithm, ok := am.combiningAlgorithm.(BuiltInCombiningAlgorithm)
+	if !ok {
+		return nil, nil, newPartialEvaluationError(nil, fmt.Errorf("custom CombiningAlgorithm cannot be partially evaluated"))
+	}
+
+	switch algorithm {
+	case DenyOverrides:
+		return newAndPredicate(newOrPredicate(allows...), newNotPredicate(newOrPredicate(denies...))), applies, nil
+	case AllowOverrides:
+		return newOrPredicate(allows...), applies, nil
+	case FirstApplicable:
+		return newOrPredicate(firstApplicable...), applies, nil
+	}
+
+	return nil, nil, newPartialEvaluationError(nil, fmt.Errorf("unknown CombiningAlgorithm: %d", algorithm))
+}
+
+// partialConditions - translates given Conditions into a Predicate satisfied when all of them are satisfied.
+func partialConditions(conditions Conditions, request *AccessRequest) (Predicate, error) {
+	predicates := []Predicate{}
+
+	for _, condition := range conditions {
+		predicate, err := partialCondition(condition, request)
+		if err != nil {
+			return nil, err
+		}
+
+		predicates = append(predicates, predicate)
+	}
+
+	return newAndPredicate(predicates...), nil
+}
+
+// partialCondition - translates given Condition into a Predicate. Conditions that do not reference
+// ResourceField are checked, and translated into ConstantPredicates.
+func partialCondition(condition Condition, request *AccessRequest) (Predicate, error) {
+	switch c := condition.(type) {
+	case *AndCondition:
+		return partialConditions(c.Conditions, request)
+	case *OrCondition:
+		predicates := []Predicate{}
+
+		for _, nested := range c.Conditions {
+			predicate, err := partialCondition(nested, request)
+			if err != nil {
+				return nil, err
+			}
+
+			predicates = append(predicates, predicate)
+		}
+
+		return newOrPredicate(predicates...), nil
+	case *NotCondition:
+		if c.Condition == nil {
+			return newConstantPredicate(false), nil
+		}
+
+		predicate, err := partialCondition(c.Condition, request)
+		if err != nil {
+			return nil, err
+		}
+
+		return newNotPredicate(predicate), nil
+	}
+
+	descriptors, err := partialDescriptors(condition)
+	if err != nil {
+		return nil, err
+	}
+
+	if !referencesResource(descriptors) {
+		err := checkCondition(condition, request)
+		if err == nil {
+			return newConstantPredicate(true), nil
+		}
+
+		if _, ok := err.(*ConditionNotSatisfiedError); ok {
+			return newConstantPredicate(false), nil
+		}
+
+		return nil, err
+	}
+
+	switch c := condition.(type) {
+	case *EqualCondition:
+		return partialComparison(condition, c.Left, c.Right, EqualOperator, request)
+	case *NotEqualCondition:
+		return partialComparison(condition, c.Left, c.Right, NotEqualOperator, request)
+	case *GreaterThanCondition:
+		return partialComparison(condition, c.Left, c.Right, GreaterThanOperator, request)
+	case *GreaterThanOrEqualCondition:
+		return partialComparison(condition, c.Left, c.Right, GreaterThanOrEqualOperator, request)
+	case *LessThanCondition:
+		return partialComparison(condition, c.Left, c.Right, LessThanOperator, request)
+	case *LessThanOrEqualCondition:
+		return partialComparison(condition, c.Left, c.Right, LessThanOrEqualOperator, request)
+	case *BetweenCondition:
+		return partialBetween(c, request)
+	case *InCondition:
+		return partialMembership(condition, c.Left, c.Right, InOperator, request)
+	case *NotInCondition:
+		return partialMembership(condition, c.Left, c.Right, NotInOperator, request)
+	case *EmptyCondition, *NotEmptyCondition:
+		// Zero values are empty as well, and they depend on the field's type, which is not known.
+		return nil, newPartialEvaluationError(condition, fmt.Errorf("emptiness of Resource's fields cannot be translated into a Predicate"))
+	}
+
+	return nil, newPartialEvaluationError(condition, fmt.Errorf("Condition referencing Resource's fields cannot be translated into a Predicate"))
+}
+
+// partialComparison - translates a comparison of Resource's field with another value into a FieldPredicate.
+func partialComparison(
+	condition Condition,
+	left, right *ValueDescriptor,
+	operator PredicateOperator,
+	request *AccessRequest,
+) (Predicate, error) {
+	if isResourceField(left) && isResourceField(right) {
+		return nil, newPartialEvaluationError(condition, fmt.Errorf("comparing two Resource's fields is not supported"))
+	}
+
+	field, other := left, right
+
+	// Field is always the left operand of FieldPredicate.
+	if isResourceField(right) {
+		field, other = right, left
+		operator = operator.flipped()
+	}
+
+	if err := validateResourceField(field); err != nil {
+		return nil, err
+	}
+
+	value, err := other.GetValue(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if operator == EqualOperator || operator == NotEqualOperator {
+		if !isPartialScalar(value) {
+			return nil, newPartialEvaluationError(condition, fmt.Errorf("value \"%v\" cannot be compared with Resource's field in a Predicate", value))
+		}
+	} else if !isPartialNumber(value) {
+		// Comparison Conditions are never satisfied when compared value is not a number.
+		return newConstantPredicate(false), nil
+	}
+
+	return &FieldPredicate{
+		Field:    field.Field,
+		Operator: operator,
+		Value:    value,
+	}, nil
+}
+
+// partialBetween - translates BetweenCondition checking Resource's field into a pair of FieldPredicates.
+func partialBetween(condition *BetweenCondition, request *AccessRequest) (Predicate, error) {
+	if !isResourceField(condition.Value) || isResourceField(condition.Min) || isResourceField(condition.Max) {
+		return nil, newPartialEvaluationError(condition, fmt.Errorf("only Value can reference Resource's fields"))
+	}
+
+	if err := validateResourceField(condition.Value); err != nil {
+		return nil, err
+	}
+
+	min, max, err := unpackDescriptors(condition.Min, condition.Max, request)
+	if err != nil {
+		return nil, err
+	}
+
+	// BetweenCondition is never satisfied when any of the bounds is not a number.
+	if !isPartialNumber(min) || !isPartialNumber(max) {
+		return newConstantPredicate(false), nil
+	}
+
+	return newAndPredicate(
+		&FieldPredicate{Field: condition.Value.Field, Operator: GreaterThanOrEqualOperator, Value: min},
+		&FieldPredicate{Field: condition.Value.Field, Operator: LessThanOrEqualOperator, Value: max},
+	), nil
+}
+
+// partialMembership - translates a membership check of Resource's field into a FieldPredicate.
+// Value of the FieldPredicate is always a slice of collection's elements.
+func partialMembership(
+	condition Condition,
+	left, right *ValueDescriptor,
+	operator PredicateOperator,
+	request *AccessRequest,
+) (Predicate, error) {
+	if isResourceField(right) {
+		return nil, newPartialEvaluationError(condition, fmt.Errorf("only Left can reference Resource's fields"))
+	}
+
+	if err := validateResourceField(left); err != nil {
+		return nil, err
+	}
+
+	collection, err := right.GetValue(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Membership Conditions are never satisfied when Right is not a collection.
+	if !utils.IsCollection(collection) {
+		return newConstantPredicate(false), nil
+	}
+
+	elements := utils.GetCollectionElements(collection)
+
+	for _, element := range elements {
+		if !isPartialScalar(element) {
+			return nil, newPartialEvaluationError(condition, fmt.Errorf("element \"%v\" cannot be compared with Resource's field in a Predicate", element))
+		}
+	}
+
+	return &FieldPredicate{
+		Field:    left.Field,
+		Operator: operator,
+		Value:    elements,
+	}, nil
+}
+
+// isPartialScalar - returns true if given value can be compared for equality with Resource's field
+// in a FieldPredicate, i.e. it's a string, a boolean or a number (other than NaN). Other values, including nil,
+// are compared by Conditions according to their Go types (e.g. nil pointers or time.Time's location),
+// which query languages have no equivalent of.
+func isPartialScalar(value interface{}) bool {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.String, reflect.Bool:
+		return true
+	}
+
+	return isPartialNumber(value)
+}
+
+// isPartialNumber - returns true if given value is a number that can be compared by comparison Conditions.
+func isPartialNumber(value interface{}) bool {
+	_, ok := utils.CompareNumbers(value, value)
+
+	return ok
+}
+
+// partialDescriptors - returns all ValueDescriptors given built-in Condition resolves its values with.
+// Conditions that are not known to resolve all their values with ValueDescriptors (e.g. custom Conditions)
+// could read the Resource, which is not known during partial evaluation - in such case, as well as for
+// values resolved by ValueResolvers, PartialEvaluationError is returned, so the evaluation fails closed.
+func partialDescriptors(condition Condition) ([]*ValueDescriptor, error) {
+	var descriptors []*ValueDescriptor
+
+	switch c := condition.(type) {
+	case *EqualCondition:
+		descriptors = []*ValueDescriptor{c.Left, c.Right}
+	case *NotEqualCondition:
+		descriptors = []*ValueDescriptor{c.Left, c.Right}
+	case *GreaterThanCondition:
+		descriptors = []*ValueDescriptor{c.Left, c.Right}
+	case *GreaterThanOrEqualCondition:
+		descriptors = []*ValueDescriptor{c.Left, c.Right}
+	case *LessThanCondition:
+		descriptors = []*ValueDescriptor{c.Left, c.Right}
+	case *LessThanOrEqualCondition:
+		descriptors = []*ValueDescriptor{c.Left, c.Right}
+	case *BetweenCondition:
+		descriptors = []*ValueDescriptor{c.Value, c.Min, c.Max}
+	case *BeforeCondition:
+		descriptors = []*ValueDescriptor{c.Left, c.Right}
+	case *AfterCondition:
+		descriptors = []*ValueDescriptor{c.Left, c.Right}
+	case *TimeWindowCondition:
+		descriptors = []*ValueDescriptor{c.From, c.To}
+	case *ScheduleCondition:
+		descriptors = []*ValueDescriptor{}
+	case *InCondition:
+		descriptors = []*ValueDescriptor{c.Left, c.Right}
+	case *NotInCondition:
+		descriptors = []*ValueDescriptor{c.Left, c.Right}
+	case *ContainsCondition:
+		descriptors = []*ValueDescriptor{c.Left, c.Right}
+	case *IntersectsCondition:
+		descriptors = []*ValueDescriptor{c.Left, c.Right}
+	case *StartsWithCondition:
+		descriptors = []*ValueDescriptor{c.Left, c.Right}
+	case *EndsWithCondition:
+		descriptors = []*ValueDescriptor{c.Left, c.Right}
+	case *MatchesCondition:
+		descriptors = []*ValueDescriptor{c.Left, c.Right}
+	case *GlobCondition:
+		descriptors = []*ValueDescriptor{c.Left, c.Right}
+	case *IPInCIDRCondition:
+		descriptors = []*ValueDescriptor{c.Left, c.Right}
+	case *IPNotInCIDRCondition:
+		descriptors = []*ValueDescriptor{c.Left, c.Right}
+	case *EmptyCondition:
+		descriptors = []*ValueDescriptor{c.Value}
+	case *NotEmptyCondition:
+		descriptors = []*ValueDescriptor{c.Value}
+	case *ExpressionCondition:
+		parsed := c.parsed
+
+		if parsed == nil || parsed.Source() != c.Expression {
+			var err error
+
+			if parsed, err = c.parse(); err != nil {
+				return nil, err
+			}
+		}
+
+		descriptors = []*ValueDescriptor{}
+
+		for _, path := range parsed.Paths() {
+			descriptors = append(descriptors, &ValueDescriptor{Source: expressionRoots[path.Root], Field: path.Field})
+		}
+	default:
+		return nil, newPartialEvaluationError(condition, fmt.Errorf("Condition cannot be partially evaluated"))
+	}
+
+	for _, descriptor := range descriptors {
+		if descriptor != nil && descriptor.Source == Resolver {
+			return nil, newPartialEvaluationError(condition, fmt.Errorf("values resolved by ValueResolvers cannot be partially evaluated"))
+		}
+	}
+
+	return descriptors, nil
+}
+
+// referencesResource - returns true if any of given ValueDescriptors has ResourceField Source.
+func referencesResource(descriptors []*ValueDescriptor) bool {
+	for _, descriptor := range descriptors {
+		if isResourceField(descriptor) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isResourceField - returns true if given ValueDescriptor has ResourceField Source.
+func isResourceField(descriptor *ValueDescriptor) bool {
+	return descriptor != nil && descriptor.Source == ResourceField
+}
+
+// validateResourceField - returns an error if given ValueDescriptor does not describe Resource's field.
+func validateResourceField(descriptor *ValueDescriptor) error {
+	if !isResourceField(descriptor) {
+		return newValueDescriptorMalformedError(descriptor, fmt.Errorf("expected Source: \"%s\"", ResourceField.String()))
+	}
+
+	if descriptor.Field == "" {
+		return newValueDescriptorMalformedError(descriptor, fmt.Errorf("Field cannot be empty for Source: \"%s\"", ResourceField.String()))
+	}
+
+	return nil
+}
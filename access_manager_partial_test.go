@@ -0,0 +1,791 @@
+package restrict
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/el-mike/restrict/v2/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type accessManagerPartialSuite struct {
+	suite.Suite
+}
+
+func TestAccessManagerPartialSuite(t *testing.T) {
+	suite.Run(t, new(accessManagerPartialSuite))
+}
+
+func (s *accessManagerPartialSuite) getManager(roles ...*Role) (*AccessManager, *extendedPolicyProviderMock) {
+	testPolicyProvider := new(extendedPolicyProviderMock)
+
+	for _, role := range roles {
+		testPolicyProvider.On("GetRole", role.ID).Return(role, nil)
+	}
+
+	return NewAccessManager(testPolicyProvider), testPolicyProvider
+}
+
+func (s *accessManagerPartialSuite) getSubject(roles ...string) *subjectMock {
+	testSubject := &subjectMock{ID: "user-1", FieldTwo: 3}
+	testSubject.On("GetRoles").Return(roles)
+
+	return testSubject
+}
+
+func (s *accessManagerPartialSuite) TestPartialEvaluate() {
+	testRole := &Role{
+		ID:      basicRoleOneName,
+		Parents: []string{basicParentRoleName},
+		Grants: GrantsMap{
+			basicResourceOneName: {
+				&Permission{
+					Action: readAction,
+					Conditions: Conditions{
+						&EqualCondition{
+							Left:  &ValueDescriptor{Source: ResourceField, Field: "OwnerID"},
+							Right: &ValueDescriptor{Source: SubjectField, Field: "ID"},
+						},
+					},
+				},
+				&Permission{
+					Action: updateAction,
+					Conditions: Conditions{
+						&EqualCondition{
+							Left:  &ValueDescriptor{Source: SubjectField, Field: "ID"},
+							Right: &ValueDescriptor{Source: Explicit, Value: "user-2"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testParentRole := &Role{
+		ID:      basicParentRoleName,
+		Parents: []string{basicRoleOneName},
+		Grants: GrantsMap{
+			basicResourceOneName: {
+				&Permission{
+					Action: readAction,
+					Conditions: Conditions{
+						&OrCondition{
+							Conditions: Conditions{
+								&EqualCondition{
+									Left:  &ValueDescriptor{Source: ResourceField, Field: "Public"},
+									Right: &ValueDescriptor{Source: Explicit, Value: true},
+								},
+								&LessThanCondition{
+									Left:  &ValueDescriptor{Source: SubjectField, Field: "FieldTwo"},
+									Right: &ValueDescriptor{Source: ResourceField, Field: "Level"},
+								},
+							},
+						},
+					},
+				},
+				&Permission{Action: createAction},
+			},
+		},
+	}
+
+	manager, _ := s.getManager(testRole, testParentRole)
+	testSubject := s.getSubject(basicRoleOneName)
+
+	predicate, err := manager.PartialEvaluate(testSubject, readAction, basicResourceOneName, nil)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), `Resource.OwnerID == "user-1" OR Resource.Public == true OR Resource.Level > 3`, predicate.String())
+
+	// Conditions not referencing the Resource are evaluated.
+	predicate, err = manager.PartialEvaluate(testSubject, updateAction, basicResourceOneName, nil)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), newConstantPredicate(false), predicate)
+
+	predicate, err = manager.PartialEvaluate(testSubject, createAction, basicResourceOneName, nil)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), newConstantPredicate(true), predicate)
+
+	predicate, err = manager.PartialEvaluate(testSubject, deleteAction, basicResourceOneName, nil)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), newConstantPredicate(false), predicate)
+}
+
+func (s *accessManagerPartialSuite) TestPartialEvaluate_Conditions() {
+	testRole := &Role{
+		ID: basicRoleOneName,
+		Grants: GrantsMap{
+			basicResourceOneName: {
+				&Permission{
+					Action: readAction,
+					Conditions: Conditions{
+						&BetweenCondition{
+							Value: &ValueDescriptor{Source: ResourceField, Field: "Level"},
+							Min:   &ValueDescriptor{Source: Explicit, Value: 1},
+							Max:   &ValueDescriptor{Source: ContextField, Field: "MaxLevel"},
+						},
+						&InCondition{
+							Left:  &ValueDescriptor{Source: ResourceField, Field: "Team"},
+							Right: &ValueDescriptor{Source: ContextField, Field: "Teams"},
+						},
+					},
+				},
+				&Permission{
+					Action: updateAction,
+					Conditions: Conditions{
+						&ContainsCondition{
+							Left:  &ValueDescriptor{Source: ResourceField, Field: "Editors"},
+							Right: &ValueDescriptor{Source: SubjectField, Field: "ID"},
+						},
+					},
+				},
+				&Permission{
+					Action: deleteAction,
+					Conditions: Conditions{
+						&EqualCondition{
+							Left:  &ValueDescriptor{Source: ResourceField, Field: "OwnerID"},
+							Right: &ValueDescriptor{Source: ResourceField, Field: "CreatedBy"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	manager, _ := s.getManager(testRole)
+	testSubject := s.getSubject(basicRoleOneName)
+
+	predicate, err := manager.PartialEvaluate(testSubject, readAction, basicResourceOneName, Context{
+		"MaxLevel": 5,
+		"Teams":    []string{"a", "b"},
+	})
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), &AndPredicate{
+		Predicates: []Predicate{
+			&FieldPredicate{Field: "Level", Operator: GreaterThanOrEqualOperator, Value: 1},
+			&FieldPredicate{Field: "Level", Operator: LessThanOrEqualOperator, Value: 5},
+			&FieldPredicate{Field: "Team", Operator: InOperator, Value: []interface{}{"a", "b"}},
+		},
+	}, predicate)
+
+	_, err = manager.PartialEvaluate(testSubject, updateAction, basicResourceOneName, nil)
+
+	assert.IsType(s.T(), new(PartialEvaluationError), err)
+	assert.Equal(s.T(), ContainsConditionType, err.(*PartialEvaluationError).FailedCondition().Type())
+
+	_, err = manager.PartialEvaluate(testSubject, deleteAction, basicResourceOneName, nil)
+
+	assert.IsType(s.T(), new(PartialEvaluationError), err)
+
+	// Membership in a value that is not a collection is never satisfied.
+	predicate, err = manager.PartialEvaluate(testSubject, readAction, basicResourceOneName, Context{
+		"MaxLevel": 5,
+		"Teams":    "a",
+	})
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), newConstantPredicate(false), predicate)
+}
+
+// partialDocument - Resource used for comparing Predicates with Authorize's decisions.
+type partialDocument struct {
+	OwnerID string
+	Team    string
+	Level   int
+	Public  bool
+}
+
+func (d *partialDocument) GetResourceName() string {
+	return basicResourceOneName
+}
+
+// matchesPredicate - checks given partialDocument against given Predicate the way SQL databases do,
+// i.e. comparing numbers regardless of their types (converting strings to numbers when compared
+// with a number), and other values by their text representation.
+func matchesPredicate(predicate Predicate, document *partialDocument) bool {
+	switch p := predicate.(type) {
+	case *ConstantPredicate:
+		return p.Value
+	case *AndPredicate:
+		for _, nested := range p.Predicates {
+			if !matchesPredicate(nested, document) {
+				return false
+			}
+		}
+
+		return true
+	case *OrPredicate:
+		for _, nested := range p.Predicates {
+			if matchesPredicate(nested, document) {
+				return true
+			}
+		}
+
+		return false
+	case *NotPredicate:
+		return !matchesPredicate(p.Predicate, document)
+	case *FieldPredicate:
+		field := reflect.ValueOf(document).Elem().FieldByName(p.Field).Interface()
+
+		equal := func(value interface{}) bool {
+			if result, ok := utils.CompareNumbers(field, value); ok {
+				return result == 0
+			}
+
+			return fmt.Sprintf("%v", field) == fmt.Sprintf("%v", value)
+		}
+
+		value := p.Value
+
+		if text, ok := value.(string); ok {
+			if number, err := strconv.ParseFloat(text, 64); err == nil {
+				value = number
+			}
+		}
+
+		result, comparable := utils.CompareNumbers(field, value)
+
+		switch p.Operator {
+		case EqualOperator:
+			return equal(p.Value)
+		case NotEqualOperator:
+			return !equal(p.Value)
+		case GreaterThanOperator:
+			return comparable && result > 0
+		case GreaterThanOrEqualOperator:
+			return comparable && result >= 0
+		case LessThanOperator:
+			return comparable && result < 0
+		case LessThanOrEqualOperator:
+			return comparable && result <= 0
+		case InOperator, NotInOperator:
+			contains := false
+
+			for _, element := range p.Value.([]interface{}) {
+				contains = contains || equal(element)
+			}
+
+			return contains == (p.Operator == InOperator)
+		}
+	}
+
+	panic(fmt.Sprintf("unexpected Predicate: %v", predicate))
+}
+
+func (s *accessManagerPartialSuite) TestPartialEvaluate_MatchesAuthorize() {
+	testRole := &Role{
+		ID: basicRoleOneName,
+		Grants: GrantsMap{
+			basicResourceOneName: {
+				&Permission{
+					Action: readAction,
+					Conditions: Conditions{
+						&EqualCondition{
+							Left:  &ValueDescriptor{Source: ResourceField, Field: "OwnerID"},
+							Right: &ValueDescriptor{Source: SubjectField, Field: "ID"},
+						},
+					},
+				},
+				&Permission{
+					Action: readAction,
+					Conditions: Conditions{
+						&EqualCondition{
+							Left:  &ValueDescriptor{Source: ResourceField, Field: "Public"},
+							Right: &ValueDescriptor{Source: Explicit, Value: true},
+						},
+						&GreaterThanCondition{
+							Left:  &ValueDescriptor{Source: ResourceField, Field: "Level"},
+							Right: &ValueDescriptor{Source: ContextField, Field: "MinLevel"},
+						},
+					},
+				},
+				&Permission{
+					Action: readAction,
+					Conditions: Conditions{
+						&InCondition{
+							Left:  &ValueDescriptor{Source: ResourceField, Field: "Team"},
+							Right: &ValueDescriptor{Source: Explicit, Value: []interface{}{"a", "b"}},
+						},
+						&BetweenCondition{
+							Value: &ValueDescriptor{Source: ResourceField, Field: "Level"},
+							Min:   &ValueDescriptor{Source: Explicit, Value: 1},
+							Max:   &ValueDescriptor{Source: Explicit, Value: 3},
+						},
+					},
+				},
+				&Permission{
+					Action: readAction,
+					Effect: DenyEffect,
+					Conditions: Conditions{
+						&NotEqualCondition{
+							Left:  &ValueDescriptor{Source: ResourceField, Field: "Team"},
+							Right: &ValueDescriptor{Source: Explicit, Value: "b"},
+						},
+						&EqualCondition{
+							Left:  &ValueDescriptor{Source: ResourceField, Field: "Level"},
+							Right: &ValueDescriptor{Source: Explicit, Value: 0},
+						},
+					},
+				},
+				// Never satisfied, as Right is not a number.
+				&Permission{
+					Action: readAction,
+					Conditions: Conditions{
+						&LessThanCondition{
+							Left:  &ValueDescriptor{Source: ResourceField, Field: "Level"},
+							Right: &ValueDescriptor{Source: Explicit, Value: "10"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	manager, _ := s.getManager(testRole)
+	testSubject := s.getSubject(basicRoleOneName)
+	testContext := Context{"MinLevel": 2}
+
+	predicate, err := manager.PartialEvaluate(testSubject, readAction, basicResourceOneName, testContext)
+
+	assert.Nil(s.T(), err)
+
+	testDocuments := []*partialDocument{
+		{},
+		{OwnerID: "user-1"},
+		{OwnerID: "user-1", Level: 2},
+		{OwnerID: "user-2", Level: 2},
+		{Public: true, Level: 2},
+		{Public: true, Level: 3},
+		{Public: false, Level: 5},
+		{Team: "a", Level: 1},
+		{Team: "a", Level: 4},
+		{Team: "b", Level: 3},
+		{Team: "b"},
+		{Team: "c", Level: 2},
+	}
+
+	for _, document := range testDocuments {
+		err := manager.Authorize(&AccessRequest{
+			Subject:  testSubject,
+			Resource: document,
+			Actions:  []string{readAction},
+			Context:  testContext,
+		})
+
+		assert.Equal(s.T(), err == nil, matchesPredicate(predicate, document), "%+v", document)
+	}
+}
+
+func (s *accessManagerPartialSuite) TestPartialEvaluate_TypeDependentConditions() {
+	testRole := &Role{
+		ID: basicRoleOneName,
+		Grants: GrantsMap{
+			basicResourceOneName: {
+				&Permission{
+					Action: readAction,
+					Conditions: Conditions{
+						&NotEmptyCondition{
+							Value: &ValueDescriptor{Source: ResourceField, Field: "OwnerID"},
+						},
+					},
+				},
+				&Permission{Action: updateAction},
+				&Permission{
+					Action: updateAction,
+					Effect: DenyEffect,
+					Conditions: Conditions{
+						&EmptyCondition{
+							Value: &ValueDescriptor{Source: ResourceField, Field: "Team"},
+						},
+					},
+				},
+				&Permission{
+					Action: deleteAction,
+					Conditions: Conditions{
+						&EqualCondition{
+							Left:  &ValueDescriptor{Source: ResourceField, Field: "OwnerID"},
+							Right: &ValueDescriptor{Source: Explicit, Value: nil},
+						},
+					},
+				},
+				&Permission{
+					Action: createAction,
+					Conditions: Conditions{
+						&NotInCondition{
+							Left:  &ValueDescriptor{Source: ResourceField, Field: "Team"},
+							Right: &ValueDescriptor{Source: Explicit, Value: []interface{}{"a", []string{"b"}}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	manager, _ := s.getManager(testRole)
+	testSubject := s.getSubject(basicRoleOneName)
+
+	// Zero values are empty as well, which cannot be expressed without knowing field's type.
+	for _, action := range []string{readAction, updateAction} {
+		_, err := manager.PartialEvaluate(testSubject, action, basicResourceOneName, nil)
+
+		assert.IsType(s.T(), new(PartialEvaluationError), err, action)
+	}
+
+	// Conditions compare values according to their Go types, unless they're strings, booleans or numbers.
+	for _, action := range []string{deleteAction, createAction} {
+		_, err := manager.PartialEvaluate(testSubject, action, basicResourceOneName, nil)
+
+		assert.IsType(s.T(), new(PartialEvaluationError), err, action)
+	}
+}
+
+func (s *accessManagerPartialSuite) TestPartialEvaluate_UnsupportedConditions() {
+	testCondition := new(conditionMock)
+	testCondition.On("Type").Return("CUSTOM")
+	testCondition.On("Check", mock.Anything).Return(nil)
+
+	testRole := &Role{
+		ID: basicRoleOneName,
+		Grants: GrantsMap{
+			basicResourceOneName: {
+				&Permission{
+					Action:     readAction,
+					Conditions: Conditions{testCondition},
+				},
+				&Permission{
+					Action: updateAction,
+					Conditions: Conditions{
+						&ExpressionCondition{Expression: "resource.OwnerID == subject.ID"},
+					},
+				},
+				&Permission{
+					Action: deleteAction,
+					Conditions: Conditions{
+						&EqualCondition{
+							Left:  &ValueDescriptor{Source: Resolver, Field: "owner"},
+							Right: &ValueDescriptor{Source: SubjectField, Field: "ID"},
+						},
+					},
+				},
+				&Permission{
+					Action: createAction,
+					Conditions: Conditions{
+						&ExpressionCondition{Expression: "subject.ID == context.UserID"},
+					},
+				},
+			},
+		},
+	}
+
+	manager, _ := s.getManager(testRole)
+	testSubject := s.getSubject(basicRoleOneName)
+
+	// Custom Conditions could read the Resource, therefore they are never checked.
+	_, err := manager.PartialEvaluate(testSubject, readAction, basicResourceOneName, nil)
+
+	assert.IsType(s.T(), new(PartialEvaluationError), err)
+	assert.Equal(s.T(), testCondition, err.(*PartialEvaluationError).FailedCondition())
+	testCondition.AssertNotCalled(s.T(), "Check", mock.Anything)
+
+	_, err = manager.PartialEvaluate(testSubject, updateAction, basicResourceOneName, nil)
+
+	assert.IsType(s.T(), new(PartialEvaluationError), err)
+	assert.Equal(s.T(), ExpressionConditionType, err.(*PartialEvaluationError).FailedCondition().Type())
+
+	_, err = manager.PartialEvaluate(testSubject, deleteAction, basicResourceOneName, nil)
+
+	assert.IsType(s.T(), new(PartialEvaluationError), err)
+	assert.Equal(s.T(), EqualConditionType, err.(*PartialEvaluationError).FailedCondition().Type())
+
+	predicate, err := manager.PartialEvaluate(testSubject, createAction, basicResourceOneName, Context{"UserID": "user-1"})
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), newConstantPredicate(true), predicate)
+}
+
+func (s *accessManagerPartialSuite) TestPartialEvaluate_CombiningAlgorithms() {
+	ownerCondition := &EqualCondition{
+		Left:  &ValueDescriptor{Source: ResourceField, Field: "OwnerID"},
+		Right: &ValueDescriptor{Source: SubjectField, Field: "ID"},
+	}
+
+	lockedCondition := &EqualCondition{
+		Left:  &ValueDescriptor{Source: ResourceField, Field: "Locked"},
+		Right: &ValueDescriptor{Source: Explicit, Value: true},
+	}
+
+	testRole := &Role{
+		ID: basicRoleOneName,
+		Grants: GrantsMap{
+			basicResourceOneName: {
+				&Permission{Action: updateAction, Effect: DenyEffect, Conditions: Conditions{lockedCondition}},
+				&Permission{Action: updateAction, Conditions: Conditions{ownerCondition}},
+			},
+		},
+	}
+
+	manager, _ := s.getManager(testRole)
+	testSubject := s.getSubject(basicRoleOneName)
+
+	predicate, err := manager.PartialEvaluate(testSubject, updateAction, basicResourceOneName, nil)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), `Resource.OwnerID == "user-1" AND NOT Resource.Locked == true`, predicate.String())
+
+	manager.SetCombiningAlgorithm(AllowOverrides)
+
+	predicate, err = manager.PartialEvaluate(testSubject, updateAction, basicResourceOneName, nil)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), `Resource.OwnerID == "user-1"`, predicate.String())
+
+	manager.SetCombiningAlgorithm(FirstApplicable)
+
+	predicate, err = manager.PartialEvaluate(testSubject, updateAction, basicResourceOneName, nil)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), `Resource.OwnerID == "user-1" AND NOT Resource.Locked == true`, predicate.String())
+
+	manager.SetCombiningAlgorithm(CombiningAlgorithmFunc(func(effects []Effect) (Effect, bool) {
+		return AllowEffect, true
+	}))
+
+	_, err = manager.PartialEvaluate(testSubject, updateAction, basicResourceOneName, nil)
+
+	assert.IsType(s.T(), new(PartialEvaluationError), err)
+	assert.Nil(s.T(), err.(*PartialEvaluationError).FailedCondition())
+
+	manager.SetCombiningAlgorithm(BuiltInCombiningAlgorithm(100))
+
+	_, err = manager.PartialEvaluate(testSubject, updateAction, basicResourceOneName, nil)
+
+	assert.IsType(s.T(), new(PartialEvaluationError), err)
+}
+
+func (s *accessManagerPartialSuite) TestPartialEvaluate_Hierarchies() {
+	testRole := &Role{
+		ID: basicRoleOneName,
+		Grants: GrantsMap{
+			"Document": {
+				&Permission{
+					Action: "edit",
+					Conditions: Conditions{
+						&EqualCondition{
+							Left:  &ValueDescriptor{Source: ResourceField, Field: "OwnerID"},
+							Right: &ValueDescriptor{Source: SubjectField, Field: "ID"},
+						},
+					},
+				},
+			},
+			"Project": {
+				&Permission{
+					Action: readAction,
+					Conditions: Conditions{
+						&EqualCondition{
+							Left:  &ValueDescriptor{Source: ResourceField, Field: "Public"},
+							Right: &ValueDescriptor{Source: Explicit, Value: true},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	manager, testPolicyProvider := s.getManager(testRole)
+	testPolicyProvider.definitions = ResourceDefinitions{
+		"Document": {Parent: "Project"},
+	}
+	testPolicyProvider.actions = &ActionHierarchy{
+		Global: ActionImplications{
+			"edit": {readAction},
+		},
+	}
+
+	testSubject := s.getSubject(basicRoleOneName)
+
+	predicate, err := manager.PartialEvaluate(testSubject, readAction, "Document", nil)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(
+		s.T(),
+		`Resource.OwnerID == "user-1" OR (NOT Resource.OwnerID == "user-1" AND Resource.Public == true)`,
+		predicate.String(),
+	)
+}
+
+func (s *accessManagerPartialSuite) TestPartialEvaluateRequest() {
+	testRole := &Role{
+		ID: basicRoleOneName,
+		Grants: GrantsMap{
+			basicResourceOneName: {
+				&Permission{
+					Action: readAction,
+					Conditions: Conditions{
+						&EqualCondition{
+							Left:  &ValueDescriptor{Source: ResourceField, Field: "OwnerID"},
+							Right: &ValueDescriptor{Source: SubjectField, Field: "ID"},
+						},
+					},
+				},
+				&Permission{
+					Action: updateAction,
+					Effect: DenyEffect,
+					Conditions: Conditions{
+						&EqualCondition{
+							Left:  &ValueDescriptor{Source: ResourceField, Field: "Locked"},
+							Right: &ValueDescriptor{Source: Explicit, Value: true},
+						},
+					},
+				},
+				&Permission{Action: updateAction},
+			},
+		},
+	}
+
+	manager, _ := s.getManager(testRole)
+	testSubject := s.getSubject(basicRoleOneName)
+
+	predicate, err := manager.PartialEvaluateRequest(&AccessRequest{
+		Subject: testSubject,
+		Actions: []string{readAction},
+	}, basicResourceOneName)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), `Resource.OwnerID == "user-1"`, predicate.String())
+
+	// Skipped Conditions are treated as satisfied, for both allow and deny Permissions.
+	predicate, err = manager.PartialEvaluateRequest(&AccessRequest{
+		Subject:        testSubject,
+		Actions:        []string{readAction},
+		SkipConditions: true,
+	}, basicResourceOneName)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), newConstantPredicate(true), predicate)
+
+	predicate, err = manager.PartialEvaluateRequest(&AccessRequest{
+		Subject:        testSubject,
+		Actions:        []string{updateAction},
+		SkipConditions: true,
+	}, basicResourceOneName)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), newConstantPredicate(false), predicate)
+
+	_, err = manager.PartialEvaluateRequest(nil, basicResourceOneName)
+
+	assert.IsType(s.T(), new(RequestMalformedError), err)
+
+	_, err = manager.PartialEvaluateRequest(&AccessRequest{
+		Subject: testSubject,
+		Actions: []string{readAction, updateAction},
+	}, basicResourceOneName)
+
+	assert.IsType(s.T(), new(RequestMalformedError), err)
+}
+
+func (s *accessManagerPartialSuite) TestPartialEvaluate_WithIndex() {
+	ownerCondition := &EqualCondition{
+		Left:  &ValueDescriptor{Source: ResourceField, Field: "OwnerID"},
+		Right: &ValueDescriptor{Source: SubjectField, Field: "ID"},
+	}
+
+	testPolicy := &PolicyDefinition{
+		Roles: Roles{
+			basicRoleOneName: {
+				ID:      basicRoleOneName,
+				Parents: []string{basicParentRoleName},
+				Grants: GrantsMap{
+					basicResourceOneName: {
+						&Permission{Action: readAction, Conditions: Conditions{ownerCondition}},
+					},
+				},
+			},
+			basicParentRoleName: {
+				ID:      basicParentRoleName,
+				Parents: []string{basicRoleOneName},
+				Grants: GrantsMap{
+					basicResourceOneName: {
+						&Permission{
+							Action: readAction,
+							Effect: DenyEffect,
+							Conditions: Conditions{
+								&EqualCondition{
+									Left:  &ValueDescriptor{Source: ResourceField, Field: "Locked"},
+									Right: &ValueDescriptor{Source: Explicit, Value: true},
+								},
+							},
+						},
+					},
+				},
+			},
+			basicRoleTwoName: {
+				ID:      basicRoleTwoName,
+				Parents: []string{"Missing"},
+			},
+		},
+	}
+
+	testAdapter := new(storageAdapterMock)
+	testAdapter.On("LoadPolicy").Return(testPolicy, nil)
+
+	policyManager, err := NewPolicyManager(testAdapter, false)
+
+	assert.Nil(s.T(), err)
+
+	indexedManager := NewAccessManager(policyManager)
+	manager := NewAccessManager(newNotIndexedPolicyProvider(policyManager))
+
+	for _, roles := range [][]string{{basicRoleOneName}, {basicParentRoleName}, {basicRoleTwoName}} {
+		expected, expectedErr := manager.PartialEvaluate(s.getSubject(roles...), readAction, basicResourceOneName, nil)
+		predicate, err := indexedManager.PartialEvaluate(s.getSubject(roles...), readAction, basicResourceOneName, nil)
+
+		assert.Equal(s.T(), expectedErr, err)
+		assert.Equal(s.T(), expected, predicate)
+	}
+
+	// Inheritance cycle is skipped.
+	predicate, err := indexedManager.PartialEvaluate(s.getSubject(basicRoleOneName), readAction, basicResourceOneName, nil)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), `Resource.OwnerID == "user-1" AND NOT Resource.Locked == true`, predicate.String())
+
+	_, err = indexedManager.PartialEvaluate(s.getSubject(basicRoleTwoName), readAction, basicResourceOneName, nil)
+
+	assert.IsType(s.T(), new(RoleNotFoundError), err)
+}
+
+func (s *accessManagerPartialSuite) TestPartialEvaluate_Errors() {
+	testPolicyProvider := new(policyProviderMock)
+	testPolicyProvider.On("GetRole", mock.Anything).Return(nil, assert.AnError)
+
+	manager := NewAccessManager(testPolicyProvider)
+
+	_, err := manager.PartialEvaluate(nil, readAction, basicResourceOneName, nil)
+
+	assert.IsType(s.T(), new(RequestMalformedError), err)
+
+	_, err = manager.PartialEvaluate(s.getSubject(), readAction, basicResourceOneName, nil)
+
+	assert.IsType(s.T(), new(RequestMalformedError), err)
+
+	_, err = manager.PartialEvaluate(s.getSubject(basicRoleOneName), "", basicResourceOneName, nil)
+
+	assert.IsType(s.T(), new(RequestMalformedError), err)
+
+	_, err = manager.PartialEvaluate(s.getSubject(basicRoleOneName), readAction, "", nil)
+
+	assert.IsType(s.T(), new(RequestMalformedError), err)
+
+	_, err = manager.PartialEvaluate(s.getSubject(basicRoleOneName), readAction, basicResourceOneName, nil)
+
+	assert.Equal(s.T(), assert.AnError, err)
+}
@@ -1,8 +1,10 @@
 package restrict
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -23,6 +25,49 @@ func (m *policyProviderMock) GetRole(name string) (*Role, error) {
 	return args.Get(0).(*Role), args.Error(1)
 }
 
+type extendedPolicyProviderMock struct {
+	policyProviderMock
+
+	definitions ResourceDefinitions
+	actions     *ActionHierarchy
+}
+
+func (m *extendedPolicyProviderMock) GetResourceDefinition(name string) *ResourceDefinition {
+	return m.definitions[name]
+}
+
+func (m *extendedPolicyProviderMock) GetImplyingActions(resourceName, action string) []string {
+	return m.actions.getImplyingActions(resourceName, action)
+}
+
+func (m *extendedPolicyProviderMock) GetImpliedActions(resourceName, action string) []string {
+	return m.actions.getImpliedActions(resourceName, action)
+}
+
+type contextPolicyProviderMock struct {
+	policyProviderMock
+}
+
+func (m *contextPolicyProviderMock) GetRoleContext(ctx context.Context, name string) (*Role, error) {
+	args := m.Called(ctx, name)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*Role), args.Error(1)
+}
+
+type clockMock struct {
+	mock.Mock
+}
+
+func (m *clockMock) Now() time.Time {
+	args := m.Called()
+
+	return args.Get(0).(time.Time)
+}
+
 type accessManagerSuite struct {
 	suite.Suite
 
@@ -44,6 +89,76 @@ func (s *accessManagerSuite) TestNewAccessManager() {
 
 	assert.NotNil(s.T(), manager)
 	assert.IsType(s.T(), new(AccessManager), manager)
+	assert.IsType(s.T(), systemClock{}, manager.clock)
+	assert.NotNil(s.T(), manager.combiningAlgorithm)
+}
+
+func (s *accessManagerSuite) TestSetCombiningAlgorithm() {
+	testPolicyProvider := new(policyProviderMock)
+
+	manager := NewAccessManager(testPolicyProvider)
+
+	manager.SetCombiningAlgorithm(FirstApplicable)
+
+	effect, _ := manager.combiningAlgorithm.Combine([]Effect{AllowEffect, DenyEffect})
+	assert.Equal(s.T(), AllowEffect, effect)
+
+	// Nil should restore the default.
+	manager.SetCombiningAlgorithm(nil)
+
+	effect, _ = manager.combiningAlgorithm.Combine([]Effect{AllowEffect, DenyEffect})
+	assert.Equal(s.T(), DenyEffect, effect)
+}
+
+func (s *accessManagerSuite) TestAuthorize_Clock() {
+	testPolicyProvider := new(policyProviderMock)
+	testRole := getBasicRoleOne()
+
+	testRole.Grants[basicResourceOneName] = Permissions{
+		&Permission{
+			Action: updateAction,
+			Conditions: Conditions{
+				&AfterCondition{
+					Left:  &ValueDescriptor{Source: ResourceField, Field: "FieldOne"},
+					Right: &ValueDescriptor{Source: CurrentTime},
+				},
+			},
+		},
+	}
+
+	testPolicyProvider.On("GetRole", basicRoleOneName).Return(testRole, nil)
+
+	testClock := new(clockMock)
+	testClock.On("Now").Return(time.Date(2023, time.March, 1, 12, 0, 0, 0, time.UTC))
+
+	manager := NewAccessManager(testPolicyProvider)
+	manager.SetClock(testClock)
+
+	testSubject := new(subjectMock)
+	testSubject.On("GetRoles").Return([]string{basicRoleOneName})
+
+	testResource := new(resourceMock)
+	testResource.On("GetResourceName").Return(basicResourceOneName)
+	testResource.FieldOne = "2023-03-02T00:00:00Z"
+
+	testRequest := &AccessRequest{
+		Subject:  testSubject,
+		Resource: testResource,
+		Actions:  []string{updateAction},
+	}
+
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+
+	testResource.FieldOne = "2023-03-01T00:00:00Z"
+
+	assert.IsType(s.T(), new(AccessDeniedError), manager.Authorize(testRequest))
+
+	// Caller's request is not modified.
+	assert.True(s.T(), testRequest.now.IsZero())
+
+	manager.SetClock(nil)
+
+	assert.IsType(s.T(), systemClock{}, manager.clock)
 }
 
 func (s *accessManagerSuite) TestAuthorize_MalformedRequest() {
@@ -613,3 +728,838 @@ func (s *accessManagerSuite) TestAuthorize_CompleteValidationMultipleRoles() {
 	assert.True(s.T(), len(roleTwoErrors) == 3)
 	assert.True(s.T(), roleTwoErrors[0].RoleName == basicRoleTwoName)
 }
+
+func (s *accessManagerSuite) TestAuthorize_DenyEffect() {
+	testRole := getBasicRoleOne()
+
+	testPolicyProvider := new(policyProviderMock)
+	testPolicyProvider.On("GetRole", basicRoleOneName).Return(testRole, nil)
+
+	manager := NewAccessManager(testPolicyProvider)
+
+	testSubject := new(subjectMock)
+	testResource := new(resourceMock)
+
+	testSubject.On("GetRoles").Return([]string{basicRoleOneName})
+	testResource.On("GetResourceName").Return(basicResourceOneName)
+
+	testRequest := &AccessRequest{
+		Subject:  testSubject,
+		Resource: testResource,
+		Actions:  []string{readAction},
+	}
+
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+
+	// Deny Permission with failing Condition does not apply.
+	testFailingCondition := new(conditionMock)
+	testFailingCondition.On("Check", mock.Anything).Return(NewConditionNotSatisfiedError(testFailingCondition, testRequest, s.testError))
+
+	testDenyPermission := &Permission{
+		Action:     readAction,
+		Effect:     DenyEffect,
+		Conditions: Conditions{testFailingCondition},
+	}
+
+	testRole.Grants[basicResourceOneName] = append(testRole.Grants[basicResourceOneName], testDenyPermission)
+
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+
+	// Deny Permission with satisfied Condition overrides the allowing one.
+	testWorkingCondition := new(conditionMock)
+	testWorkingCondition.On("Check", mock.Anything).Return(nil)
+
+	testDenyPermission.Conditions = Conditions{testWorkingCondition}
+
+	err := manager.Authorize(testRequest)
+	accessError := err.(*AccessDeniedError)
+
+	assert.IsType(s.T(), new(AccessDeniedError), err)
+	assert.Len(s.T(), accessError.Reasons, 1)
+	assert.True(s.T(), accessError.FirstReason().Denied)
+	assert.Equal(s.T(), readAction, accessError.FirstReason().Action)
+	assert.Equal(s.T(), basicRoleOneName, accessError.FirstReason().RoleName)
+	assert.Len(s.T(), accessError.Reasons.GetExplicitlyDenied(), 1)
+	assert.Contains(s.T(), accessError.FirstReason().Error(), "explicitly denied")
+
+	// Skipped Conditions are treated as satisfied.
+	testDenyPermission.Conditions = Conditions{testFailingCondition}
+	testRequest.SkipConditions = true
+
+	err = manager.Authorize(testRequest)
+
+	assert.IsType(s.T(), new(AccessDeniedError), err)
+	assert.True(s.T(), err.(*AccessDeniedError).FirstReason().Denied)
+
+	// Deny Permission alone does not grant the access.
+	testRequest.SkipConditions = false
+	testRequest.Actions = []string{deleteAction}
+	testDenyPermission.Action = deleteAction
+
+	err = manager.Authorize(testRequest)
+
+	assert.IsType(s.T(), new(AccessDeniedError), err)
+	assert.False(s.T(), err.(*AccessDeniedError).FirstReason().Denied)
+	assert.Len(s.T(), err.(*AccessDeniedError).Reasons.GetExplicitlyDenied(), 0)
+
+	// Unknown Effect is never treated as allow.
+	testDenyPermission.Effect = Effect("DENY")
+
+	err = manager.Authorize(testRequest)
+
+	assert.IsType(s.T(), new(UnknownEffectError), err)
+}
+
+func (s *accessManagerSuite) TestAuthorize_DenyEffectOnRolesAndParents() {
+	testRoleOne := getBasicRoleOne()
+	testRoleTwo := getBasicRoleTwo()
+	testParentRole := getBasicParentRole()
+
+	testRoleTwo.Parents = []string{testParentRole.ID}
+	testParentRole.Grants[basicResourceOneName] = append(
+		testParentRole.Grants[basicResourceOneName],
+		&Permission{Action: deleteAction, Effect: DenyEffect},
+	)
+
+	testPolicyProvider := new(policyProviderMock)
+	testPolicyProvider.On("GetRole", basicRoleOneName).Return(testRoleOne, nil)
+	testPolicyProvider.On("GetRole", basicRoleTwoName).Return(testRoleTwo, nil)
+	testPolicyProvider.On("GetRole", basicParentRoleName).Return(testParentRole, nil)
+
+	manager := NewAccessManager(testPolicyProvider)
+
+	testSubject := new(subjectMock)
+	testResource := new(resourceMock)
+
+	testSubject.On("GetRoles").Return([]string{basicRoleOneName, basicRoleTwoName})
+	testResource.On("GetResourceName").Return(basicResourceOneName)
+
+	// Delete is allowed by the second Role, but denied by its parent.
+	testRequest := &AccessRequest{
+		Subject:            testSubject,
+		Resource:           testResource,
+		Actions:            []string{readAction, deleteAction},
+		CompleteValidation: true,
+	}
+
+	err := manager.Authorize(testRequest)
+	accessError := err.(*AccessDeniedError)
+
+	assert.IsType(s.T(), new(AccessDeniedError), err)
+	assert.Len(s.T(), accessError.Reasons, 1)
+	assert.True(s.T(), accessError.FirstReason().Denied)
+	assert.Equal(s.T(), deleteAction, accessError.FirstReason().Action)
+	assert.Equal(s.T(), basicParentRoleName, accessError.FirstReason().RoleName)
+
+	// Deny on one Role overrides the allow on another.
+	testRoleTwo.Parents = nil
+	testRoleOne.Grants[basicResourceOneName] = append(
+		testRoleOne.Grants[basicResourceOneName],
+		&Permission{Action: readAction, Effect: DenyEffect},
+	)
+
+	testRequest.Actions = []string{readAction}
+
+	err = manager.Authorize(testRequest)
+
+	assert.IsType(s.T(), new(AccessDeniedError), err)
+	assert.Equal(s.T(), basicRoleOneName, err.(*AccessDeniedError).FirstReason().RoleName)
+
+	// Actions are combined across Roles - each of them can be allowed by a different Role.
+	testRequest.Actions = []string{createAction, updateAction}
+
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+}
+
+func (s *accessManagerSuite) TestAuthorize_StrictEvaluation() {
+	testRoleOne := getBasicRoleOne()
+	testRoleTwo := getBasicRoleTwo()
+
+	testCondition := new(conditionMock)
+	testCondition.On("Type").Return(basicConditionOne)
+	testCondition.On("Check").Return(assert.AnError)
+
+	testRoleTwo.Grants[basicResourceOneName] = append(
+		Permissions{&Permission{Action: readAction, Effect: DenyEffect, Conditions: Conditions{testCondition}}},
+		testRoleTwo.Grants[basicResourceOneName]...,
+	)
+
+	testPolicyProvider := new(policyProviderMock)
+	testPolicyProvider.On("GetRole", basicRoleOneName).Return(testRoleOne, nil)
+	testPolicyProvider.On("GetRole", basicRoleTwoName).Return(testRoleTwo, nil)
+	testPolicyProvider.On("GetRole", "Missing").Return(nil, newRoleNotFoundError("Missing"))
+
+	manager := NewAccessManager(testPolicyProvider)
+
+	testResource := new(resourceMock)
+	testResource.On("GetResourceName").Return(basicResourceOneName)
+
+	testRequest := &AccessRequest{
+		Subject:  UseSubject([]string{basicRoleOneName, "Missing", basicRoleTwoName}),
+		Resource: testResource,
+		Actions:  []string{readAction},
+	}
+
+	// Read is allowed by the first Role - missing Role and Condition's error are skipped.
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+
+	// Errors encountered before the Action has been allowed are returned.
+	testRequest.Subject = UseSubject([]string{"Missing", basicRoleOneName})
+
+	assert.IsType(s.T(), new(RoleNotFoundError), manager.Authorize(testRequest))
+
+	testRequest.Subject = UseSubject([]string{basicRoleTwoName, basicRoleOneName})
+
+	assert.Equal(s.T(), assert.AnError, manager.Authorize(testRequest))
+
+	// With strict evaluation, all errors are returned.
+	manager.SetStrictEvaluation(true)
+
+	testRequest.Subject = UseSubject([]string{basicRoleOneName, "Missing"})
+
+	assert.IsType(s.T(), new(RoleNotFoundError), manager.Authorize(testRequest))
+
+	testRequest.Subject = UseSubject([]string{basicRoleOneName, basicRoleTwoName})
+
+	assert.Equal(s.T(), assert.AnError, manager.Authorize(testRequest))
+
+	manager.SetStrictEvaluation(false)
+
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+}
+
+func (s *accessManagerSuite) TestAuthorize_CombiningAlgorithms() {
+	testRoleOne := getBasicRoleOne()
+	testRoleTwo := getBasicRoleTwo()
+
+	testRoleTwo.Grants[basicResourceOneName] = append(
+		Permissions{&Permission{Action: readAction, Effect: DenyEffect}},
+		testRoleTwo.Grants[basicResourceOneName]...,
+	)
+
+	testPolicyProvider := new(policyProviderMock)
+	testPolicyProvider.On("GetRole", basicRoleOneName).Return(testRoleOne, nil)
+	testPolicyProvider.On("GetRole", basicRoleTwoName).Return(testRoleTwo, nil)
+
+	manager := NewAccessManager(testPolicyProvider)
+
+	testSubject := new(subjectMock)
+	testResource := new(resourceMock)
+
+	testResource.On("GetResourceName").Return(basicResourceOneName)
+
+	testRequest := &AccessRequest{
+		Subject:  testSubject,
+		Resource: testResource,
+		Actions:  []string{readAction},
+	}
+
+	testSubject.On("GetRoles").Return([]string{basicRoleOneName, basicRoleTwoName}).Once()
+
+	// DenyOverrides.
+	assert.IsType(s.T(), new(AccessDeniedError), manager.Authorize(testRequest))
+
+	// AllowOverrides.
+	manager.SetCombiningAlgorithm(AllowOverrides)
+	testSubject.On("GetRoles").Return([]string{basicRoleTwoName, basicRoleOneName}).Once()
+
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+
+	// FirstApplicable - Roles order matters.
+	manager.SetCombiningAlgorithm(FirstApplicable)
+	testSubject.On("GetRoles").Return([]string{basicRoleOneName, basicRoleTwoName}).Once()
+
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+
+	testSubject.On("GetRoles").Return([]string{basicRoleTwoName, basicRoleOneName}).Once()
+
+	err := manager.Authorize(testRequest)
+
+	assert.IsType(s.T(), new(AccessDeniedError), err)
+	assert.True(s.T(), err.(*AccessDeniedError).FirstReason().Denied)
+}
+
+func (s *accessManagerSuite) TestAuthorize_Patterns() {
+	testSuperAdminRole := &Role{
+		ID: "SuperAdmin",
+		Grants: GrantsMap{
+			"*": {&Permission{Action: "*"}},
+		},
+	}
+
+	testBillingRole := &Role{
+		ID: "Billing",
+		Grants: GrantsMap{
+			"billing/*": {
+				&Permission{Action: "read:*"},
+			},
+			"billing/invoices": {
+				&Permission{Action: "read:summary"},
+			},
+		},
+	}
+
+	testPolicyProvider := new(policyProviderMock)
+	testPolicyProvider.On("GetRole", testSuperAdminRole.ID).Return(testSuperAdminRole, nil)
+	testPolicyProvider.On("GetRole", testBillingRole.ID).Return(testBillingRole, nil)
+
+	manager := NewAccessManager(testPolicyProvider)
+
+	testSubject := new(subjectMock)
+	testResource := new(resourceMock)
+
+	testSubject.On("GetRoles").Return([]string{testSuperAdminRole.ID}).Once()
+	testResource.On("GetResourceName").Return("users").Once()
+
+	testRequest := &AccessRequest{
+		Subject:  testSubject,
+		Resource: testResource,
+		Actions:  []string{"delete", "read:details"},
+	}
+
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+
+	testSubject.On("GetRoles").Return([]string{testBillingRole.ID})
+
+	// Resource pattern.
+	testResource.On("GetResourceName").Return("billing/payments").Once()
+	testRequest.Actions = []string{"read:details"}
+
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+
+	testResource.On("GetResourceName").Return("billing/payments").Once()
+	testRequest.Actions = []string{"delete"}
+
+	assert.IsType(s.T(), new(AccessDeniedError), manager.Authorize(testRequest))
+
+	// Exact Resource name takes precedence over the pattern, but only for Actions granted for it.
+	testResource.On("GetResourceName").Return("billing/invoices")
+	testRequest.Actions = []string{"read:summary"}
+
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+
+	testRequest.Actions = []string{"read:details"}
+
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+
+	// Exact Action takes precedence over the pattern.
+	testBillingRole.Grants["billing/invoices"] = Permissions{
+		&Permission{Action: "read:*"},
+		&Permission{Action: "read:summary", Effect: DenyEffect},
+	}
+
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+
+	testRequest.Actions = []string{"read:summary"}
+
+	err := manager.Authorize(testRequest)
+
+	assert.IsType(s.T(), new(AccessDeniedError), err)
+	assert.True(s.T(), err.(*AccessDeniedError).FirstReason().Denied)
+
+	// Deny patterns are never bypassed by the exact Action.
+	testBillingRole.Grants["billing/invoices"] = Permissions{
+		&Permission{Action: "read:summary"},
+		&Permission{Action: "*", Effect: DenyEffect},
+	}
+
+	err = manager.Authorize(testRequest)
+
+	assert.IsType(s.T(), new(AccessDeniedError), err)
+	assert.True(s.T(), err.(*AccessDeniedError).FirstReason().Denied)
+
+	// Nor by the exact Resource name.
+	testBillingRole.Grants["billing/invoices"] = Permissions{
+		&Permission{Action: "read:summary"},
+	}
+	testBillingRole.Grants["*"] = Permissions{
+		&Permission{Action: "read:*", Effect: DenyEffect},
+	}
+
+	err = manager.Authorize(testRequest)
+
+	assert.IsType(s.T(), new(AccessDeniedError), err)
+	assert.True(s.T(), err.(*AccessDeniedError).FirstReason().Denied)
+
+	// Allowing patterns are still bypassed for Actions granted for the exact Resource name.
+	testBillingRole.Grants["billing/invoices"] = Permissions{
+		&Permission{
+			Action: "read:summary",
+			Conditions: Conditions{
+				&EqualCondition{
+					Left:  &ValueDescriptor{Source: Explicit, Value: "one"},
+					Right: &ValueDescriptor{Source: Explicit, Value: "two"},
+				},
+			},
+		},
+	}
+	testBillingRole.Grants["*"] = Permissions{
+		&Permission{Action: "*"},
+	}
+
+	assert.IsType(s.T(), new(AccessDeniedError), manager.Authorize(testRequest))
+
+	testRequest.Actions = []string{"read:details", "delete"}
+
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+}
+
+func (s *accessManagerSuite) TestAuthorize_PatternsPerAction() {
+	testSuperAdminRole := &Role{
+		ID: "SuperAdmin",
+		Grants: GrantsMap{
+			"*": {
+				&Permission{Action: "*"},
+			},
+			"Document": {
+				&Permission{Action: readAction},
+			},
+		},
+	}
+
+	testPolicy := &PolicyDefinition{
+		Roles: Roles{testSuperAdminRole.ID: testSuperAdminRole},
+	}
+
+	testAdapter := new(storageAdapterMock)
+	testAdapter.On("LoadPolicy").Return(testPolicy, nil)
+
+	policyManager, _ := NewPolicyManager(testAdapter, false)
+
+	testRequest := &AccessRequest{
+		Subject:  UseSubject([]string{testSuperAdminRole.ID}),
+		Resource: UseResource("Document"),
+		Actions:  []string{readAction, deleteAction},
+	}
+
+	// Permissions granted for exact Resource name do not hide patterns for other Actions,
+	// both with and without PolicyIndex.
+	for _, provider := range []PolicyProvider{policyManager, newNotIndexedPolicyProvider(policyManager)} {
+		manager := NewAccessManager(provider)
+
+		assert.Nil(s.T(), manager.Authorize(testRequest))
+
+		predicate, err := manager.PartialEvaluate(testRequest.Subject, deleteAction, "Document", nil)
+
+		assert.Nil(s.T(), err)
+		assert.Equal(s.T(), newConstantPredicate(true), predicate)
+	}
+}
+
+func (s *accessManagerSuite) TestAuthorize_ResourceHierarchy() {
+	testRole := &Role{
+		ID: "Editor",
+		Grants: GrantsMap{
+			"Project": {
+				&Permission{
+					Action: updateAction,
+					Conditions: Conditions{
+						&EqualCondition{
+							Left:  &ValueDescriptor{Source: ResourceField, Field: "ID"},
+							Right: &ValueDescriptor{Source: Explicit, Value: "project-1"},
+						},
+					},
+				},
+			},
+			"Folder": {
+				&Permission{Action: readAction},
+			},
+		},
+	}
+
+	testPolicyProvider := new(extendedPolicyProviderMock)
+	testPolicyProvider.On("GetRole", testRole.ID).Return(testRole, nil)
+
+	manager := NewAccessManager(testPolicyProvider)
+
+	testSubject := new(subjectMock)
+	testSubject.On("GetRoles").Return([]string{testRole.ID})
+
+	testProject := &hierarchicalResourceMock{ID: "project-1", Name: "Project"}
+	testFolder := &hierarchicalResourceMock{ID: "folder-1", Name: "Folder", Parent: testProject}
+	testDocument := &hierarchicalResourceMock{ID: "document-1", Name: "Document", Parent: testFolder}
+
+	testRequest := &AccessRequest{
+		Subject:  testSubject,
+		Resource: testDocument,
+		Actions:  []string{readAction},
+	}
+
+	// Permission inherited from the parent.
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+
+	// Conditions are checked against the original Resource by default.
+	testRequest.Actions = []string{updateAction}
+
+	err := manager.Authorize(testRequest)
+	accessError := err.(*AccessDeniedError)
+
+	assert.IsType(s.T(), new(AccessDeniedError), err)
+	assert.Len(s.T(), accessError.Reasons, 1)
+	assert.Equal(s.T(), "Document", accessError.FirstReason().ResourceName)
+
+	// Conditions checked against the ancestor.
+	testPolicyProvider.definitions = ResourceDefinitions{
+		"Project": {ConditionsTarget: AncestorResource},
+	}
+
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+
+	// Permission applying to the Resource itself is not overridden by ancestors.
+	testRole.Grants["Document"] = Permissions{&Permission{Action: readAction, Effect: DenyEffect}}
+	testRequest.Actions = []string{readAction}
+
+	err = manager.Authorize(testRequest)
+
+	assert.IsType(s.T(), new(AccessDeniedError), err)
+	assert.True(s.T(), err.(*AccessDeniedError).FirstReason().Denied)
+
+	// Parent declared in ResourceDefinition.
+	testResource := new(resourceMock)
+	testResource.On("GetResourceName").Return("Comment")
+
+	testPolicyProvider.definitions["Comment"] = &ResourceDefinition{Parent: "Folder"}
+	testRequest.Resource = testResource
+
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+
+	// Resource hierarchy cycle.
+	testPolicyProvider.definitions["Folder"] = &ResourceDefinition{Parent: "Comment"}
+	testRequest.Actions = []string{deleteAction}
+
+	assert.IsType(s.T(), new(ResourceHierarchyCycleError), manager.Authorize(testRequest))
+
+	testProject.Parent = testDocument
+	testRequest.Resource = testDocument
+
+	assert.IsType(s.T(), new(ResourceHierarchyCycleError), manager.Authorize(testRequest))
+
+	// Hierarchy which cannot be checked for cycles is bound by its depth.
+	testRequest.Resource = &endlessResourceMock{Name: "Document"}
+
+	assert.IsType(s.T(), new(ResourceHierarchyTooDeepError), manager.Authorize(testRequest))
+}
+
+func (s *accessManagerSuite) TestAuthorize_ActionHierarchy() {
+	testFailingCondition := new(conditionMock)
+	testFailingCondition.On("Check", mock.Anything).Return(NewConditionNotSatisfiedError(testFailingCondition, nil, s.testError))
+
+	testRole := &Role{
+		ID: "Manager",
+		Grants: GrantsMap{
+			basicResourceOneName: {
+				&Permission{Action: "manage"},
+			},
+			basicResourceTwoName: {
+				&Permission{Action: "manage", Conditions: Conditions{testFailingCondition}},
+			},
+		},
+	}
+
+	testPolicyProvider := new(extendedPolicyProviderMock)
+	testPolicyProvider.On("GetRole", testRole.ID).Return(testRole, nil)
+
+	manager := NewAccessManager(testPolicyProvider)
+
+	testSubject := new(subjectMock)
+	testSubject.On("GetRoles").Return([]string{testRole.ID})
+
+	testResource := new(resourceMock)
+	testResource.On("GetResourceName").Return(basicResourceOneName).Once()
+
+	testRequest := &AccessRequest{
+		Subject:  testSubject,
+		Resource: testResource,
+		Actions:  []string{updateAction},
+	}
+
+	// No hierarchy.
+	assert.IsType(s.T(), new(AccessDeniedError), manager.Authorize(testRequest))
+
+	testPolicyProvider.actions = &ActionHierarchy{
+		Global: ActionImplications{
+			"manage": {"write", readAction},
+			"write":  {createAction, updateAction},
+		},
+		Resources: map[string]ActionImplications{
+			basicResourceTwoName: {
+				"manage": {"archive"},
+			},
+		},
+	}
+
+	// Action implied indirectly.
+	testResource.On("GetResourceName").Return(basicResourceOneName).Once()
+
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+
+	// Action implied only for another Resource.
+	testResource.On("GetResourceName").Return(basicResourceOneName).Once()
+	testRequest.Actions = []string{"archive"}
+
+	assert.IsType(s.T(), new(AccessDeniedError), manager.Authorize(testRequest))
+
+	// Conditions of implying Permission are checked.
+	testResource.On("GetResourceName").Return(basicResourceTwoName)
+
+	err := manager.Authorize(testRequest)
+	accessError := err.(*AccessDeniedError)
+
+	assert.IsType(s.T(), new(AccessDeniedError), err)
+	assert.Equal(s.T(), "archive", accessError.FirstReason().Action)
+	assert.True(s.T(), accessError.FirstReason().HasFailedConditions())
+}
+
+func (s *accessManagerSuite) TestAuthorizeContext() {
+	type contextKey string
+
+	ctx := context.WithValue(context.Background(), contextKey("key"), "value")
+
+	testContextCondition := new(contextConditionMock)
+	testContextCondition.On("Type").Return(basicConditionOne)
+	testContextCondition.On("CheckContext", ctx).Return(nil)
+
+	testCondition := new(conditionMock)
+	testCondition.On("Type").Return(basicConditionOne)
+	testCondition.On("Check").Return(nil)
+
+	testRole := getBasicRoleOne()
+	testRole.Grants[basicResourceOneName] = []*Permission{
+		{
+			Action: readAction,
+			Conditions: Conditions{
+				testCondition,
+				&OrCondition{Conditions: Conditions{testContextCondition}},
+			},
+		},
+	}
+
+	testPolicyProvider := new(contextPolicyProviderMock)
+	testPolicyProvider.On("GetRoleContext", ctx, basicRoleOneName).Return(testRole, nil)
+
+	manager := NewAccessManager(testPolicyProvider)
+
+	testSubject := new(subjectMock)
+	testSubject.On("GetRoles").Return([]string{basicRoleOneName})
+
+	testResource := new(resourceMock)
+	testResource.On("GetResourceName").Return(basicResourceOneName)
+
+	testRequest := &AccessRequest{
+		Subject:  testSubject,
+		Resource: testResource,
+		Actions:  []string{readAction},
+	}
+
+	err := manager.AuthorizeContext(ctx, testRequest)
+
+	assert.Nil(s.T(), err)
+	testPolicyProvider.AssertCalled(s.T(), "GetRoleContext", ctx, basicRoleOneName)
+	testPolicyProvider.AssertNotCalled(s.T(), "GetRole", mock.Anything)
+	testContextCondition.AssertCalled(s.T(), "CheckContext", ctx)
+	testContextCondition.AssertNotCalled(s.T(), "Check")
+	testCondition.AssertNumberOfCalls(s.T(), "Check", 1)
+
+	// Without context, background context is passed.
+	testPolicyProvider.On("GetRoleContext", context.Background(), basicRoleOneName).Return(testRole, nil)
+	testContextCondition.On("CheckContext", context.Background()).Return(nil)
+
+	err = manager.Authorize(testRequest)
+
+	assert.Nil(s.T(), err)
+	testContextCondition.AssertCalled(s.T(), "CheckContext", context.Background())
+
+	// Canceled context.
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	err = manager.AuthorizeContext(canceledCtx, testRequest)
+
+	assert.Equal(s.T(), context.Canceled, err)
+	testPolicyProvider.AssertNumberOfCalls(s.T(), "GetRoleContext", 2)
+
+	// Context errors returned by Conditions are returned directly.
+	deadlineCtx, cancel := context.WithTimeout(ctx, time.Hour)
+	defer cancel()
+
+	testPolicyProvider.On("GetRoleContext", deadlineCtx, basicRoleOneName).Return(testRole, nil)
+	testContextCondition.On("CheckContext", deadlineCtx).Return(context.DeadlineExceeded)
+
+	err = manager.AuthorizeContext(deadlineCtx, testRequest)
+
+	assert.Equal(s.T(), context.DeadlineExceeded, err)
+}
+
+func (s *accessManagerSuite) TestEvaluate() {
+	testRole := getBasicRoleOne()
+	testParentRole := getBasicParentRole()
+
+	testRole.Parents = []string{testParentRole.ID}
+
+	testFailingCondition := new(conditionMock)
+	testFailingCondition.On("Check", mock.Anything).Return(NewConditionNotSatisfiedError(testFailingCondition, nil, s.testError))
+
+	testConditionedPermission := &Permission{Action: deleteAction, Conditions: Conditions{testFailingCondition}}
+	testRole.Grants[basicResourceOneName] = append(testRole.Grants[basicResourceOneName], testConditionedPermission)
+
+	testPolicyProvider := new(policyProviderMock)
+	testPolicyProvider.On("GetRole", basicRoleOneName).Return(testRole, nil)
+	testPolicyProvider.On("GetRole", basicParentRoleName).Return(testParentRole, nil)
+
+	manager := NewAccessManager(testPolicyProvider)
+
+	testSubject := new(subjectMock)
+	testResource := new(resourceMock)
+
+	testSubject.On("GetRoles").Return([]string{basicRoleOneName})
+	testResource.On("GetResourceName").Return(basicResourceOneName)
+
+	// Malformed request.
+	decision, err := manager.Evaluate(&AccessRequest{Subject: testSubject})
+
+	assert.Nil(s.T(), decision)
+	assert.IsType(s.T(), new(RequestMalformedError), err)
+
+	testRequest := &AccessRequest{
+		Subject:  testSubject,
+		Resource: testResource,
+		Actions:  []string{deleteAction, readAction, updateAction},
+	}
+
+	decision, err = manager.Evaluate(testRequest)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), testRequest, decision.Request)
+	assert.False(s.T(), decision.Allowed)
+
+	// All Actions are evaluated, even in fail-early mode.
+	assert.Len(s.T(), decision.Actions, 3)
+	assert.Equal(s.T(), []string{deleteAction}, decision.GetDeniedActions())
+	assert.Nil(s.T(), decision.GetAction(createAction))
+
+	deleteDecision := decision.GetAction(deleteAction)
+
+	assert.False(s.T(), deleteDecision.Allowed)
+	assert.Equal(s.T(), Effect(""), deleteDecision.Effect)
+	assert.Len(s.T(), deleteDecision.Permissions, 0)
+	assert.Len(s.T(), deleteDecision.Reasons, 1)
+	assert.True(s.T(), deleteDecision.Reasons[0].HasFailedConditions())
+	assert.Equal(s.T(), deleteDecision.Reasons, decision.Reasons())
+
+	readDecision := decision.GetAction(readAction)
+
+	assert.True(s.T(), readDecision.Allowed)
+	assert.Equal(s.T(), AllowEffect, readDecision.Effect)
+	assert.Nil(s.T(), readDecision.Reasons)
+
+	// Permissions granted by both the Role and its parent.
+	assert.Len(s.T(), readDecision.Permissions, 2)
+	assert.Equal(s.T(), basicRoleOneName, readDecision.Permissions[0].RoleName)
+	assert.Equal(s.T(), []string{basicRoleOneName}, readDecision.Permissions[0].InheritancePath)
+	assert.Equal(s.T(), basicParentRoleName, readDecision.Permissions[1].RoleName)
+	assert.Equal(s.T(), []string{basicRoleOneName, basicParentRoleName}, readDecision.Permissions[1].InheritancePath)
+	assert.Equal(s.T(), basicResourceOneName, readDecision.Permissions[1].ResourceName)
+
+	updateDecision := decision.GetAction(updateAction)
+
+	assert.True(s.T(), updateDecision.Allowed)
+	assert.Len(s.T(), updateDecision.Permissions, 1)
+	assert.Equal(s.T(), updateAction, updateDecision.Permissions[0].Permission.Action)
+	assert.Equal(s.T(), basicParentRoleName, updateDecision.Permissions[0].RoleName)
+
+	// Explicitly denied Action.
+	testDenyPermission := &Permission{Action: readAction, Effect: DenyEffect}
+	testParentRole.Grants[basicResourceOneName] = append(testParentRole.Grants[basicResourceOneName], testDenyPermission)
+
+	decision, err = manager.Evaluate(testRequest)
+
+	assert.Nil(s.T(), err)
+
+	readDecision = decision.GetAction(readAction)
+
+	assert.False(s.T(), readDecision.Allowed)
+	assert.Equal(s.T(), DenyEffect, readDecision.Effect)
+	assert.Len(s.T(), readDecision.Permissions, 1)
+	assert.Equal(s.T(), testDenyPermission, readDecision.Permissions[0].Permission)
+	assert.True(s.T(), readDecision.Reasons[0].Denied)
+	assert.Len(s.T(), decision.Reasons(), 2)
+
+	// Other errors are still returned.
+	testPolicyProvider.On("GetRole", basicRoleTwoName).Return(nil, s.testError)
+	testSubject.ExpectedCalls = nil
+	testSubject.On("GetRoles").Return([]string{basicRoleTwoName})
+
+	decision, err = manager.Evaluate(testRequest)
+
+	assert.Nil(s.T(), decision)
+	assert.Equal(s.T(), s.testError, err)
+}
+
+func (s *accessManagerSuite) TestEvaluate_Trace() {
+	testRole := getBasicRoleOne()
+	testParentRole := getBasicParentRole()
+
+	testRole.Parents = []string{testParentRole.ID}
+	testRole.Grants[basicResourceOneName] = append(testRole.Grants[basicResourceOneName], &Permission{
+		Action: updateAction,
+		Conditions: Conditions{
+			&EqualCondition{
+				Left:  &ValueDescriptor{Source: ResourceField, Field: "CreatedBy"},
+				Right: &ValueDescriptor{Source: SubjectField, Field: "ID"},
+			},
+		},
+	})
+
+	testPolicyProvider := new(policyProviderMock)
+	testPolicyProvider.On("GetRole", basicRoleOneName).Return(testRole, nil)
+	testPolicyProvider.On("GetRole", basicParentRoleName).Return(testParentRole, nil)
+
+	manager := NewAccessManager(testPolicyProvider)
+
+	testSubject := &subjectMock{ID: "user-1"}
+	testResource := &resourceMock{CreatedBy: "user-2"}
+
+	testSubject.On("GetRoles").Return([]string{basicRoleOneName})
+	testResource.On("GetResourceName").Return(basicResourceOneName)
+
+	testRequest := &AccessRequest{
+		Subject:  testSubject,
+		Resource: testResource,
+		Actions:  []string{updateAction, deleteAction},
+	}
+
+	// Tracing is disabled by default.
+	decision, err := manager.Evaluate(testRequest)
+
+	assert.Nil(s.T(), err)
+	assert.Nil(s.T(), decision.Trace)
+
+	testRequest.Trace = true
+
+	decision, err = manager.Evaluate(testRequest)
+
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), decision.Trace)
+
+	expected := "action \"update\": allowed\n" +
+		"  resource \"BasicResourceOne\": allowed\n" +
+		"    role \"BasicRoleOne\"\n" +
+		"      permission \"update\": not applicable (effect: allow)\n" +
+		"        condition \"EQUAL\": not satisfied (values \"user-2\" and \"user-1\" are not equal)\n" +
+		"          left: ResourceField \"CreatedBy\" = \"user-2\"\n" +
+		"          right: SubjectField \"ID\" = \"user-1\"\n" +
+		"      role \"BasicParentRole\"\n" +
+		"        permission \"update\": applied (effect: allow)\n" +
+		"action \"delete\": denied\n" +
+		"  resource \"BasicResourceOne\": not applicable\n" +
+		"    role \"BasicRoleOne\"\n" +
+		"      role \"BasicParentRole\"\n"
+
+	assert.Equal(s.T(), expected, decision.Trace.String())
+
+	// Trace is available in AccessDeniedError as well.
+	err = manager.Authorize(testRequest)
+
+	assert.IsType(s.T(), new(AccessDeniedError), err)
+	assert.Len(s.T(), err.(*AccessDeniedError).Trace.Steps, 2)
+}
@@ -1,5 +1,10 @@
 package restrict
 
+import (
+	"context"
+	"time"
+)
+
 // Context - alias type for a map of any values.
 type Context map[string]interface{}
 
@@ -21,4 +26,62 @@ type AccessRequest struct {
 	// CompleteValidation - when true, validation will not return early, and all possible errors
 	// will be returned, including all Conditions checks.
 	CompleteValidation bool
+	// Trace - when true, evaluation steps are recorded and returned as a Trace in Decision
+	// or AccessDeniedError. Requests with Trace enabled bypass AccessManager's DecisionCache.
+	Trace bool
+	// CacheKey - optional key identifying all the values Conditions depend on (e.g. Subject's and
+	// Resource's IDs). When set, decisions depending on Conditions are cached in AccessManager's
+	// DecisionCache as well, under this key.
+	CacheKey string
+
+	// Current time, captured by AccessManager when the request is being authorized.
+	now time.Time
+	// Tracer recording evaluation steps, nil if Trace is false.
+	tracer *tracer
+	// Context of the authorization, as passed to AccessManager's AuthorizeContext.
+	ctx context.Context
+	// Values returned by ValueResolvers, memoized for the duration of a single authorization.
+	resolvedValues map[string]interface{}
+	// PolicyIndex used for the authorization, nil if PolicyProvider does not provide one.
+	index *PolicyIndex
+}
+
+// Now - returns current time for the AccessRequest, as provided by AccessManager's Clock.
+// Falls back to system time if the request is not being authorized by AccessManager.
+func (ar *AccessRequest) Now() time.Time {
+	if ar.now.IsZero() {
+		return time.Now()
+	}
+
+	return ar.now
+}
+
+// getContext - returns the context of the authorization, or background context if none has been set.
+func (ar *AccessRequest) getContext() context.Context {
+	if ar.ctx == nil {
+		return context.Background()
+	}
+
+	return ar.ctx
+}
+
+// withTime - returns a shallow copy of the AccessRequest with current time set to given value.
+func (ar *AccessRequest) withTime(now time.Time) *AccessRequest {
+	request := *ar
+	request.now = now
+
+	return &request
+}
+
+// withResource - returns a shallow copy of the AccessRequest with Resource set to given value.
+// Values memoized for the original Resource are not shared with the copy.
+func (ar *AccessRequest) withResource(resource Resource) *AccessRequest {
+	request := *ar
+	request.Resource = resource
+
+	if ar.resolvedValues != nil {
+		request.resolvedValues = map[string]interface{}{}
+	}
+
+	return &request
 }
@@ -0,0 +1,269 @@
+package restrict
+
+import (
+	"sort"
+
+	"github.com/el-mike/restrict/v2/internal/utils"
+)
+
+// ActionImplications - alias type for a map of Actions and the Actions they imply,
+// e.g. "manage" implying "create", "read", "update" and "delete".
+type ActionImplications map[string][]string
+
+// copy - returns a deep copy of the ActionImplications.
+func (ai ActionImplications) copy() ActionImplications {
+	if ai == nil {
+		return nil
+	}
+
+	result := make(ActionImplications, len(ai))
+
+	for action, implied := range ai {
+		result[action] = append([]string{}, implied...)
+	}
+
+	return result
+}
+
+// ActionHierarchy - describes which Actions imply other Actions. Permission granted for an Action
+// (along with its Conditions) is also a Permission for all the Actions it implies, directly or not.
+type ActionHierarchy struct {
+	// Global - implications applied to all Resources.
+	Global ActionImplications `json:"global,omitempty" yaml:"global,omitempty"`
+	// Resources - implications applied to given Resources only, extending the global ones.
+	Resources map[string]ActionImplications `json:"resources,omitempty" yaml:"resources,omitempty"`
+}
+
+// copy - returns a deep copy of the ActionHierarchy.
+func (ah *ActionHierarchy) copy() *ActionHierarchy {
+	if ah == nil {
+		return nil
+	}
+
+	hierarchy := &ActionHierarchy{
+		Global: ah.Global.copy(),
+	}
+
+	if ah.Resources != nil {
+		hierarchy.Resources = make(map[string]ActionImplications, len(ah.Resources))
+
+		for resourceName, implications := range ah.Resources {
+			hierarchy.Resources[resourceName] = implications.copy()
+		}
+	}
+
+	return hierarchy
+}
+
+// getImplyingActions - returns all Actions implying given Action for given Resource,
+// directly or not, in order of their distance to the Action.
+func (ah *ActionHierarchy) getImplyingActions(resourceName, action string) []string {
+	if ah == nil {
+		return nil
+	}
+
+	return findImplyingActions(reverseImplications(ah.Global, ah.Resources[resourceName]), action)
+}
+
+// compile - returns compiledImplications of the ActionHierarchy, with Actions implying every Action
+// precomputed globally, and for every Resource with its own implications.
+func (ah *ActionHierarchy) compile() *compiledImplications {
+	compiled := &compiledImplications{
+		global:    map[string][]string{},
+		resources: map[string]map[string][]string{},
+	}
+
+	if ah == nil {
+		return compiled
+	}
+
+	compiled.global = compileImplyingActions(reverseImplications(ah.Global))
+
+	for resourceName, implications := range ah.Resources {
+		compiled.resources[resourceName] = compileImplyingActions(reverseImplications(ah.Global, implications))
+	}
+
+	return compiled
+}
+
+// compiledImplications - precomputed Actions implying other Actions, directly or not, so the graph
+// of implications does not have to be traversed on every evaluation.
+type compiledImplications struct {
+	// global - Actions implying every Action, for Resources without their own implications.
+	global map[string][]string
+	// resources - Actions implying every Action, per Resource with its own implications.
+	resources map[string]map[string][]string
+}
+
+// getImplyingActions - returns all Actions implying given Action for given Resource (see
+// ActionHierarchy's getImplyingActions). Returned slice is shared, and cannot be modified.
+func (c *compiledImplications) getImplyingActions(resourceName, action string) []string {
+	if c == nil {
+		return nil
+	}
+
+	if implyingActions, ok := c.resources[resourceName]; ok {
+		return implyingActions[action]
+	}
+
+	return c.global[action]
+}
+
+// reverseImplications - returns reversed graph of given implications - for every Action, Actions implying it.
+func reverseImplications(sources ...ActionImplications) map[string][]string {
+	impliedBy := map[string][]string{}
+
+	for _, implications := range sources {
+		for _, implying := range sortedActions(implications) {
+			for _, implied := range implications[implying] {
+				impliedBy[implied] = append(impliedBy[implied], implying)
+			}
+		}
+	}
+
+	return impliedBy
+}
+
+// compileImplyingActions - returns Actions implying every Action present in given reversed graph.
+func compileImplyingActions(impliedBy map[string][]string) map[string][]string {
+	result := make(map[string][]string, len(impliedBy))
+
+	for action := range impliedBy {
+		result[action] = findImplyingActions(impliedBy, action)
+	}
+
+	return result
+}
+
+// findImplyingActions - returns all Actions implying given Action in given reversed graph,
+// in order of their distance to the Action.
+func findImplyingActions(impliedBy map[string][]string, action string) []string {
+	result := []string{}
+	queue := []string{action}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, implying := range impliedBy[current] {
+			if implying == action || utils.StringSliceContains(result, implying) {
+				continue
+			}
+
+			result = append(result, implying)
+			queue = append(queue, implying)
+		}
+	}
+
+	return result
+}
+
+// getImpliedActions - returns all Actions implied by given Action for given Resource,
+// directly or not, in order of their distance to the Action.
+func (ah *ActionHierarchy) getImpliedActions(resourceName, action string) []string {
+	if ah == nil {
+		return nil
+	}
+
+	result := []string{}
+	queue := []string{action}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		implied := append(append([]string{}, ah.Global[current]...), ah.Resources[resourceName][current]...)
+
+		for _, impliedAction := range implied {
+			if impliedAction == action || utils.StringSliceContains(result, impliedAction) {
+				continue
+			}
+
+			result = append(result, impliedAction)
+			queue = append(queue, impliedAction)
+		}
+	}
+
+	return result
+}
+
+// validate - returns an error if global implications, or implications for any of the Resources, form a cycle.
+func (ah *ActionHierarchy) validate() error {
+	if ah == nil {
+		return nil
+	}
+
+	if err := validateImplications(ah.Global); err != nil {
+		return err
+	}
+
+	for _, resourceImplications := range ah.Resources {
+		implications := ActionImplications{}
+
+		for _, source := range []ActionImplications{ah.Global, resourceImplications} {
+			for implying, implied := range source {
+				implications[implying] = append(implications[implying], implied...)
+			}
+		}
+
+		if err := validateImplications(implications); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateImplications - returns ActionImplicationCycleError if given implications form a cycle.
+func validateImplications(implications ActionImplications) error {
+	// Actions which implications have been fully checked.
+	checked := map[string]bool{}
+
+	var visit func(action string, path []string) error
+
+	visit = func(action string, path []string) error {
+		for i, pathAction := range path {
+			if pathAction == action {
+				return newActionImplicationCycleError(path[i:])
+			}
+		}
+
+		if checked[action] {
+			return nil
+		}
+
+		path = append(path, action)
+
+		for _, implied := range implications[action] {
+			if err := visit(implied, path); err != nil {
+				return err
+			}
+		}
+
+		checked[action] = true
+
+		return nil
+	}
+
+	for _, action := range sortedActions(implications) {
+		if err := visit(action, []string{}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sortedActions - returns implying Actions of given implications in lexical order,
+// so results do not depend on map iteration order.
+func sortedActions(implications ActionImplications) []string {
+	actions := make([]string, 0, len(implications))
+
+	for action := range implications {
+		actions = append(actions, action)
+	}
+
+	sort.Strings(actions)
+
+	return actions
+}
@@ -0,0 +1,173 @@
+package restrict
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+type actionHierarchySuite struct {
+	suite.Suite
+}
+
+func TestActionHierarchySuite(t *testing.T) {
+	suite.Run(t, new(actionHierarchySuite))
+}
+
+func (s *actionHierarchySuite) TestGetImplyingActions() {
+	var nilHierarchy *ActionHierarchy
+
+	assert.Nil(s.T(), nilHierarchy.getImplyingActions(basicResourceOneName, readAction))
+
+	testHierarchy := &ActionHierarchy{
+		Global: ActionImplications{
+			"manage": {"write", readAction},
+			"write":  {createAction, updateAction},
+			"edit":   {updateAction},
+		},
+		Resources: map[string]ActionImplications{
+			basicResourceOneName: {
+				"own": {"manage"},
+			},
+		},
+	}
+
+	assert.Equal(s.T(), []string{"edit", "write", "manage"}, testHierarchy.getImplyingActions(basicResourceTwoName, updateAction))
+	assert.Equal(s.T(), []string{"edit", "write", "manage", "own"}, testHierarchy.getImplyingActions(basicResourceOneName, updateAction))
+	assert.Equal(s.T(), []string{"manage"}, testHierarchy.getImplyingActions(basicResourceTwoName, readAction))
+	assert.Equal(s.T(), []string{}, testHierarchy.getImplyingActions(basicResourceTwoName, "manage"))
+}
+
+func (s *actionHierarchySuite) TestCompile() {
+	var nilHierarchy *ActionHierarchy
+
+	assert.Nil(s.T(), nilHierarchy.compile().getImplyingActions(basicResourceOneName, readAction))
+
+	testHierarchy := &ActionHierarchy{
+		Global: ActionImplications{
+			"manage": {"write", readAction},
+			"write":  {createAction, updateAction},
+			"edit":   {updateAction},
+		},
+		Resources: map[string]ActionImplications{
+			basicResourceOneName: {
+				"own":  {"manage"},
+				"edit": {deleteAction},
+			},
+		},
+	}
+
+	compiled := testHierarchy.compile()
+
+	for _, resourceName := range []string{basicResourceOneName, basicResourceTwoName} {
+		for _, action := range []string{readAction, createAction, updateAction, deleteAction, "write", "manage", "own", "edit"} {
+			expected := testHierarchy.getImplyingActions(resourceName, action)
+
+			// Actions not implied by any other are not compiled.
+			if len(expected) == 0 {
+				assert.Nil(s.T(), compiled.getImplyingActions(resourceName, action), resourceName+" "+action)
+
+				continue
+			}
+
+			assert.Equal(s.T(), expected, compiled.getImplyingActions(resourceName, action), resourceName+" "+action)
+		}
+	}
+}
+
+func (s *actionHierarchySuite) TestGetImpliedActions() {
+	var nilHierarchy *ActionHierarchy
+
+	assert.Nil(s.T(), nilHierarchy.getImpliedActions(basicResourceOneName, readAction))
+
+	testHierarchy := &ActionHierarchy{
+		Global: ActionImplications{
+			"manage": {"write", readAction},
+			"write":  {createAction, updateAction},
+			"edit":   {updateAction},
+		},
+		Resources: map[string]ActionImplications{
+			basicResourceOneName: {
+				"own": {"manage"},
+			},
+		},
+	}
+
+	assert.Equal(s.T(), []string{"write", readAction, createAction, updateAction}, testHierarchy.getImpliedActions(basicResourceTwoName, "manage"))
+	assert.Equal(s.T(), []string{"manage", "write", readAction, createAction, updateAction}, testHierarchy.getImpliedActions(basicResourceOneName, "own"))
+	assert.Equal(s.T(), []string{}, testHierarchy.getImpliedActions(basicResourceTwoName, "own"))
+	assert.Equal(s.T(), []string{}, testHierarchy.getImpliedActions(basicResourceTwoName, readAction))
+}
+
+func (s *actionHierarchySuite) TestValidate() {
+	var nilHierarchy *ActionHierarchy
+
+	assert.Nil(s.T(), nilHierarchy.validate())
+
+	testHierarchy := &ActionHierarchy{
+		Global: ActionImplications{
+			"manage": {"write", readAction},
+			"write":  {createAction, updateAction},
+			"admin":  {"manage", "write"},
+		},
+	}
+
+	assert.Nil(s.T(), testHierarchy.validate())
+
+	// Global cycle.
+	testHierarchy.Global[updateAction] = []string{"admin"}
+
+	err := testHierarchy.validate()
+
+	assert.IsType(s.T(), new(ActionImplicationCycleError), err)
+	assert.Equal(s.T(), "Action implication cycle has been detected: \"admin\" -> \"manage\" -> \"write\" -> \"update\" -> \"admin\"", err.Error())
+
+	// Cycle formed by global and Resource's implications.
+	delete(testHierarchy.Global, updateAction)
+
+	testHierarchy.Resources = map[string]ActionImplications{
+		basicResourceOneName: {
+			readAction: {"admin"},
+		},
+	}
+
+	assert.IsType(s.T(), new(ActionImplicationCycleError), testHierarchy.validate())
+
+	// Self implication.
+	testHierarchy.Resources = nil
+	testHierarchy.Global[readAction] = []string{readAction}
+
+	assert.IsType(s.T(), new(ActionImplicationCycleError), testHierarchy.validate())
+}
+
+func (s *actionHierarchySuite) TestMarshaling() {
+	testHierarchy := &ActionHierarchy{
+		Global: ActionImplications{
+			"manage": {readAction, updateAction},
+		},
+		Resources: map[string]ActionImplications{
+			basicResourceOneName: {
+				"manage": {"archive"},
+			},
+		},
+	}
+
+	hierarchyJSON, err := json.Marshal(testHierarchy)
+	assert.Nil(s.T(), err)
+
+	jsonHierarchy := &ActionHierarchy{}
+
+	assert.Nil(s.T(), json.Unmarshal(hierarchyJSON, jsonHierarchy))
+	assert.Equal(s.T(), testHierarchy, jsonHierarchy)
+
+	hierarchyYAML, err := yaml.Marshal(testHierarchy)
+	assert.Nil(s.T(), err)
+
+	yamlHierarchy := &ActionHierarchy{}
+
+	assert.Nil(s.T(), yaml.Unmarshal(hierarchyYAML, yamlHierarchy))
+	assert.Equal(s.T(), testHierarchy, yamlHierarchy)
+}
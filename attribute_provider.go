@@ -0,0 +1,16 @@
+package restrict
+
+import "github.com/el-mike/restrict/v2/internal/utils"
+
+// AttributeProvider - optional interface that can be implemented by Subjects, Resources
+// or any values nested in them. When a value implements AttributeProvider, ValueDescriptor
+// asks it for attributes instead of using reflection, which allows to compute attributes
+// lazily, or to use proxy objects (e.g. ORM models).
+type AttributeProvider interface {
+	// GetAttribute - returns an attribute with given name. Second return value
+	// should be false if the attribute is not provided.
+	GetAttribute(name string) (interface{}, bool)
+}
+
+// Makes sure AttributeProvider matches the interface used when resolving values.
+var _ utils.AttributeProvider = AttributeProvider(nil)
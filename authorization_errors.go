@@ -43,6 +43,23 @@ func (ae PermissionErrors) GetByAction(action string) PermissionErrors {
 	return result
 }
 
+// GetExplicitlyDenied - returns PermissionError structs for Actions explicitly denied by a Permission.
+func (ae PermissionErrors) GetExplicitlyDenied() PermissionErrors {
+	if ae == nil {
+		return nil
+	}
+
+	result := PermissionErrors{}
+
+	for _, e := range ae {
+		if e.Denied {
+			result = append(result, e)
+		}
+	}
+
+	return result
+}
+
 // GetFailedActions - returns all Actions for which access was denied.
 func (ae PermissionErrors) GetFailedActions() []string {
 	actions := []string{}
@@ -61,6 +78,8 @@ func (ae PermissionErrors) GetFailedActions() []string {
 type AccessDeniedError struct {
 	Request *AccessRequest
 	Reasons PermissionErrors
+	// Trace - recorded evaluation steps, nil unless Request's Trace is set to true.
+	Trace *Trace
 }
 
 // newAccessDeniedError - returns new AccessDeniedError instance.
@@ -103,14 +122,29 @@ func (e *AccessDeniedError) FirstReason() *PermissionError {
 }
 
 // ConditionErrors - an alias type for a slice of ConditionNotSatisfiedError.
+// ConditionErrors implements error interface, so it can be used as a Reason
+// of ConditionNotSatisfiedError returned by composite Conditions.
 type ConditionErrors []*ConditionNotSatisfiedError
 
+// Error - error interface implementation.
+func (ce ConditionErrors) Error() string {
+	messages := []string{}
+
+	for _, e := range ce {
+		messages = append(messages, e.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
 // PermissionError - thrown when Permission is not granted for a given Action.
 type PermissionError struct {
 	Action          string
 	RoleName        string
 	ResourceName    string
 	ConditionErrors ConditionErrors
+	// Denied - true if the Action has been explicitly denied by a Permission with DenyEffect.
+	Denied bool
 }
 
 // newPermissionError - returns new PermissionError instance.
@@ -125,6 +159,10 @@ func newPermissionError(action, roleName, resourceName string, conditionErrors C
 
 // Error - error interface implementation.
 func (e *PermissionError) Error() string {
+	if e.Denied {
+		return fmt.Sprintf("Permission for Action: \"%v\" is explicitly denied for Resource: \"%v\"", e.Action, e.ResourceName)
+	}
+
 	if len(e.ConditionErrors) == 0 {
 		return fmt.Sprintf("Permission for Action: \"%v\" is not granted for Resource: \"%v\"", e.Action, e.ResourceName)
 	}
@@ -0,0 +1,98 @@
+package restrict
+
+// CombiningAlgorithm - decides whether an Action is allowed, based on Effects of all applicable
+// Permissions (i.e. the ones with satisfied Conditions) found for the Action, in order of evaluation.
+// Permissions are evaluated for every Subject's Role in order, starting with Role's own Permissions,
+// followed by its Parents (depth-first).
+type CombiningAlgorithm interface {
+	// Combine - returns the resulting Effect (or empty Effect, if none of the Permissions applies),
+	// and true if the result is final - in such case, remaining Permissions are not evaluated.
+	Combine(effects []Effect) (Effect, bool)
+}
+
+// CombiningAlgorithmFunc - adapter allowing to use a function as a custom CombiningAlgorithm.
+type CombiningAlgorithmFunc func(effects []Effect) (Effect, bool)
+
+// Combine - CombiningAlgorithm implementation.
+func (f CombiningAlgorithmFunc) Combine(effects []Effect) (Effect, bool) {
+	return f(effects)
+}
+
+// BuiltInCombiningAlgorithm - enum type for CombiningAlgorithms provided by the package.
+// Unlike custom ones, built-in CombiningAlgorithms can be used for partial evaluation.
+type BuiltInCombiningAlgorithm int
+
+const (
+	// DenyOverrides - CombiningAlgorithm denying the Action if any of the applicable Permissions
+	// denies it, and allowing it if at least one allows it otherwise. Used by default.
+	DenyOverrides BuiltInCombiningAlgorithm = iota
+	// AllowOverrides - CombiningAlgorithm allowing the Action if any of the applicable Permissions
+	// allows it, and denying it if at least one denies it otherwise.
+	AllowOverrides
+	// FirstApplicable - CombiningAlgorithm using the Effect of the first applicable Permission.
+	FirstApplicable
+)
+
+var combiningAlgorithmNames = map[BuiltInCombiningAlgorithm]string{
+	DenyOverrides:   "DenyOverrides",
+	AllowOverrides:  "AllowOverrides",
+	FirstApplicable: "FirstApplicable",
+}
+
+// String - Stringer implementation.
+func (a BuiltInCombiningAlgorithm) String() string {
+	return combiningAlgorithmNames[a]
+}
+
+// Combine - CombiningAlgorithm implementation. Unknown algorithms never allow the Action.
+func (a BuiltInCombiningAlgorithm) Combine(effects []Effect) (Effect, bool) {
+	switch a {
+	case DenyOverrides:
+		return denyOverrides(effects)
+	case AllowOverrides:
+		return allowOverrides(effects)
+	case FirstApplicable:
+		return firstApplicable(effects)
+	}
+
+	return DenyEffect, true
+}
+
+// denyOverrides - combines Effects according to DenyOverrides algorithm.
+func denyOverrides(effects []Effect) (Effect, bool) {
+	result := Effect("")
+
+	for _, effect := range effects {
+		if effect == DenyEffect {
+			return DenyEffect, true
+		}
+
+		result = AllowEffect
+	}
+
+	return result, false
+}
+
+// allowOverrides - combines Effects according to AllowOverrides algorithm.
+func allowOverrides(effects []Effect) (Effect, bool) {
+	result := Effect("")
+
+	for _, effect := range effects {
+		if effect == AllowEffect {
+			return AllowEffect, true
+		}
+
+		result = DenyEffect
+	}
+
+	return result, false
+}
+
+// firstApplicable - combines Effects according to FirstApplicable algorithm.
+func firstApplicable(effects []Effect) (Effect, bool) {
+	if len(effects) == 0 {
+		return "", false
+	}
+
+	return effects[0], true
+}
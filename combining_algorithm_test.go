@@ -0,0 +1,85 @@
+package restrict
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type combiningAlgorithmSuite struct {
+	suite.Suite
+}
+
+func TestCombiningAlgorithmSuite(t *testing.T) {
+	suite.Run(t, new(combiningAlgorithmSuite))
+}
+
+func (s *combiningAlgorithmSuite) TestDenyOverrides() {
+	effect, isFinal := DenyOverrides.Combine([]Effect{})
+
+	assert.Equal(s.T(), Effect(""), effect)
+	assert.False(s.T(), isFinal)
+
+	effect, isFinal = DenyOverrides.Combine([]Effect{AllowEffect, AllowEffect})
+
+	assert.Equal(s.T(), AllowEffect, effect)
+	assert.False(s.T(), isFinal)
+
+	effect, isFinal = DenyOverrides.Combine([]Effect{AllowEffect, DenyEffect})
+
+	assert.Equal(s.T(), DenyEffect, effect)
+	assert.True(s.T(), isFinal)
+}
+
+func (s *combiningAlgorithmSuite) TestAllowOverrides() {
+	effect, isFinal := AllowOverrides.Combine([]Effect{})
+
+	assert.Equal(s.T(), Effect(""), effect)
+	assert.False(s.T(), isFinal)
+
+	effect, isFinal = AllowOverrides.Combine([]Effect{DenyEffect, DenyEffect})
+
+	assert.Equal(s.T(), DenyEffect, effect)
+	assert.False(s.T(), isFinal)
+
+	effect, isFinal = AllowOverrides.Combine([]Effect{DenyEffect, AllowEffect})
+
+	assert.Equal(s.T(), AllowEffect, effect)
+	assert.True(s.T(), isFinal)
+}
+
+func (s *combiningAlgorithmSuite) TestFirstApplicable() {
+	effect, isFinal := FirstApplicable.Combine([]Effect{})
+
+	assert.Equal(s.T(), Effect(""), effect)
+	assert.False(s.T(), isFinal)
+
+	effect, isFinal = FirstApplicable.Combine([]Effect{DenyEffect, AllowEffect})
+
+	assert.Equal(s.T(), DenyEffect, effect)
+	assert.True(s.T(), isFinal)
+
+	effect, isFinal = FirstApplicable.Combine([]Effect{AllowEffect, DenyEffect})
+
+	assert.Equal(s.T(), AllowEffect, effect)
+	assert.True(s.T(), isFinal)
+}
+
+func (s *combiningAlgorithmSuite) TestUnknownBuiltInAlgorithm() {
+	effect, isFinal := BuiltInCombiningAlgorithm(100).Combine([]Effect{AllowEffect})
+
+	assert.Equal(s.T(), DenyEffect, effect)
+	assert.True(s.T(), isFinal)
+}
+
+func (s *combiningAlgorithmSuite) TestCombiningAlgorithmFunc() {
+	algorithm := CombiningAlgorithmFunc(func(effects []Effect) (Effect, bool) {
+		return AllowEffect, len(effects) > 0
+	})
+
+	effect, isFinal := algorithm.Combine([]Effect{DenyEffect})
+
+	assert.Equal(s.T(), AllowEffect, effect)
+	assert.True(s.T(), isFinal)
+}
@@ -143,6 +143,7 @@ func (e *ConditionFactoryNotFoundError) Error() string {
 type ValueDescriptorMalformedError struct {
 	descriptor *ValueDescriptor
 	reason     error
+	segment    string
 }
 
 // newValueDescriptorMalformedError - returns new ValueDescriptorMalformedError instance.
@@ -168,6 +169,12 @@ func (e *ValueDescriptorMalformedError) FailedDescriptor() *ValueDescriptor {
 	return e.descriptor
 }
 
+// FailedSegment - returns the segment of ValueDescriptor's Field path that could not
+// be resolved, or empty string if the failure is not related to a specific segment.
+func (e *ValueDescriptorMalformedError) FailedSegment() string {
+	return e.segment
+}
+
 // RoleInheritanceCycleError - thrown when circular Role inheritance is detected.
 type RoleInheritanceCycleError struct {
 	roles []string
@@ -197,3 +204,254 @@ func (e *RoleInheritanceCycleError) Error() string {
 
 	return message
 }
+
+// ConditionMalformedError - thrown when Condition's definition is not valid,
+// for example when its expression or pattern cannot be parsed.
+type ConditionMalformedError struct {
+	condition Condition
+	reason    error
+}
+
+// newConditionMalformedError - returns new ConditionMalformedError instance.
+func newConditionMalformedError(condition Condition, reason error) *ConditionMalformedError {
+	return &ConditionMalformedError{
+		condition: condition,
+		reason:    reason,
+	}
+}
+
+// Error - error interface implementation.
+func (e *ConditionMalformedError) Error() string {
+	return fmt.Sprintf("Condition: \"%v\" is malformed. Reason: %s", e.condition.Type(), e.reason.Error())
+}
+
+// Reason - returns underlying reason (an error) of malformed Condition.
+func (e *ConditionMalformedError) Reason() error {
+	return e.reason
+}
+
+// FailedCondition - returns malformed Condition.
+func (e *ConditionMalformedError) FailedCondition() Condition {
+	return e.condition
+}
+
+// UnknownEffectError - thrown when Permission's Effect is not one of the known Effects.
+type UnknownEffectError struct {
+	effect string
+}
+
+// newUnknownEffectError - returns new UnknownEffectError instance.
+func newUnknownEffectError(effect string) *UnknownEffectError {
+	return &UnknownEffectError{
+		effect: effect,
+	}
+}
+
+// Error - error interface implementation.
+func (e *UnknownEffectError) Error() string {
+	return fmt.Sprintf("Effect: \"%s\" is not known - expected \"%s\" or \"%s\"", e.effect, AllowEffect, DenyEffect)
+}
+
+// ResourceHierarchyCycleError - thrown when circular Resource hierarchy is detected.
+type ResourceHierarchyCycleError struct {
+	resources []string
+}
+
+// newResourceHierarchyCycleError - returns new ResourceHierarchyCycleError instance.
+func newResourceHierarchyCycleError(resources []string) *ResourceHierarchyCycleError {
+	return &ResourceHierarchyCycleError{
+		resources: resources,
+	}
+}
+
+// Error - error interface implementation.
+func (e *ResourceHierarchyCycleError) Error() string {
+	message := "Resource hierarchy cycle has been detected: "
+
+	for i, resource := range e.resources {
+		if i > 0 {
+			message += " -> "
+		}
+
+		message += fmt.Sprintf("\"%s\"", resource)
+	}
+
+	return message
+}
+
+// ResourceHierarchyTooDeepError - thrown when Resource has more ancestors than allowed.
+type ResourceHierarchyTooDeepError struct {
+	resourceName string
+	maxDepth     int
+}
+
+// newResourceHierarchyTooDeepError - returns new ResourceHierarchyTooDeepError instance.
+func newResourceHierarchyTooDeepError(resourceName string, maxDepth int) *ResourceHierarchyTooDeepError {
+	return &ResourceHierarchyTooDeepError{
+		resourceName: resourceName,
+		maxDepth:     maxDepth,
+	}
+}
+
+// Error - error interface implementation.
+func (e *ResourceHierarchyTooDeepError) Error() string {
+	return fmt.Sprintf("Resource hierarchy of \"%s\" exceeds maximum depth of %d ancestors", e.resourceName, e.maxDepth)
+}
+
+// ResourceDefinitionMalformedError - thrown when ResourceDefinition is not valid.
+type ResourceDefinitionMalformedError struct {
+	resourceName string
+	reason       error
+}
+
+// newResourceDefinitionMalformedError - returns new ResourceDefinitionMalformedError instance.
+func newResourceDefinitionMalformedError(resourceName string, reason error) *ResourceDefinitionMalformedError {
+	return &ResourceDefinitionMalformedError{
+		resourceName: resourceName,
+		reason:       reason,
+	}
+}
+
+// Error - error interface implementation.
+func (e *ResourceDefinitionMalformedError) Error() string {
+	return fmt.Sprintf("Definition of Resource: \"%s\" is malformed. Reason: %s", e.resourceName, e.reason.Error())
+}
+
+// Reason - returns underlying reason (an error) of malformed ResourceDefinition.
+func (e *ResourceDefinitionMalformedError) Reason() error {
+	return e.reason
+}
+
+// ActionImplicationCycleError - thrown when circular Action implication is detected.
+type ActionImplicationCycleError struct {
+	actions []string
+}
+
+// newActionImplicationCycleError - returns new ActionImplicationCycleError instance.
+func newActionImplicationCycleError(actions []string) *ActionImplicationCycleError {
+	return &ActionImplicationCycleError{
+		actions: actions,
+	}
+}
+
+// Error - error interface implementation.
+func (e *ActionImplicationCycleError) Error() string {
+	message := "Action implication cycle has been detected: "
+
+	for i, action := range e.actions {
+		if i > 0 {
+			message += " -> "
+		}
+
+		message += fmt.Sprintf("\"%s\"", action)
+	}
+
+	// We want to add the first action at the end, to indicate the cycle.
+	message += fmt.Sprintf(" -> \"%s\"", e.actions[0])
+
+	return message
+}
+
+// PartialEvaluationError - thrown when the policy cannot be partially evaluated, for example
+// when a Condition referencing Resource's fields cannot be translated into a Predicate.
+type PartialEvaluationError struct {
+	condition Condition
+	reason    error
+}
+
+// newPartialEvaluationError - returns new PartialEvaluationError instance.
+func newPartialEvaluationError(condition Condition, reason error) *PartialEvaluationError {
+	return &PartialEvaluationError{
+		condition: condition,
+		reason:    reason,
+	}
+}
+
+// Error - error interface implementation.
+func (e *PartialEvaluationError) Error() string {
+	if e.condition == nil {
+		return fmt.Sprintf("Policy cannot be partially evaluated. Reason: %s", e.reason.Error())
+	}
+
+	return fmt.Sprintf("Condition: \"%v\" cannot be partially evaluated. Reason: %s", e.condition.Type(), e.reason.Error())
+}
+
+// Reason - returns underlying reason (an error) of failed partial evaluation.
+func (e *PartialEvaluationError) Reason() error {
+	return e.reason
+}
+
+// FailedCondition - returns the Condition that could not be partially evaluated, if any.
+func (e *PartialEvaluationError) FailedCondition() Condition {
+	return e.condition
+}
+
+// ValueResolverAlreadyExistsError - thrown when ValueResolver is being added under a name
+// that's already set in ValueResolvers map.
+type ValueResolverAlreadyExistsError struct {
+	name string
+}
+
+// newValueResolverAlreadyExistsError - returns new ValueResolverAlreadyExistsError instance.
+func newValueResolverAlreadyExistsError(name string) *ValueResolverAlreadyExistsError {
+	return &ValueResolverAlreadyExistsError{
+		name: name,
+	}
+}
+
+// Error - error interface implementation.
+func (e *ValueResolverAlreadyExistsError) Error() string {
+	return fmt.Sprintf("ValueResolver: \"%s\" already exists", e.name)
+}
+
+// ValueResolverNotFoundError - thrown when ValueResolver referenced by ValueDescriptor is not registered.
+type ValueResolverNotFoundError struct {
+	name string
+}
+
+// newValueResolverNotFoundError - returns new ValueResolverNotFoundError instance.
+func newValueResolverNotFoundError(name string) *ValueResolverNotFoundError {
+	return &ValueResolverNotFoundError{
+		name: name,
+	}
+}
+
+// Error - error interface implementation.
+func (e *ValueResolverNotFoundError) Error() string {
+	return fmt.Sprintf("ValueResolver: \"%s\" not found", e.name)
+}
+
+// ValueResolverError - thrown when ValueResolver fails to resolve the value.
+type ValueResolverError struct {
+	name   string
+	reason error
+}
+
+// newValueResolverError - returns new ValueResolverError instance.
+func newValueResolverError(name string, reason error) *ValueResolverError {
+	return &ValueResolverError{
+		name:   name,
+		reason: reason,
+	}
+}
+
+// Error - error interface implementation.
+func (e *ValueResolverError) Error() string {
+	return fmt.Sprintf("ValueResolver: \"%s\" failed. Reason: %s", e.name, e.reason.Error())
+}
+
+// Reason - returns underlying reason (an error) of ValueResolver's failure.
+func (e *ValueResolverError) Reason() error {
+	return e.reason
+}
+
+// Unwrap - returns underlying reason, allowing to use errors.Is and errors.As
+// (e.g. to check for context.DeadlineExceeded).
+func (e *ValueResolverError) Unwrap() error {
+	return e.reason
+}
+
+// ResolverName - returns the name of failed ValueResolver.
+func (e *ValueResolverError) ResolverName() string {
+	return e.name
+}
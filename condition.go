@@ -1,6 +1,7 @@
 package restrict
 
 import (
+	"context"
 	"encoding/json"
 
 	"gopkg.in/yaml.v3"
@@ -17,9 +18,68 @@ type Condition interface {
 	Check(request *AccessRequest) error
 }
 
+// PreparableCondition - optional interface for Conditions that need to be prepared
+// (e.g. parsed or compiled) before being checked. Prepare is called by PolicyManager
+// whenever the policy is loaded or changed, so malformed Conditions are reported early.
+// The same Condition can be prepared many times - Prepare should not modify the Condition
+// if it's already prepared, as Conditions not implementing CopyableCondition are shared
+// between policy snapshots, and can be checked concurrently.
+type PreparableCondition interface {
+	Condition
+
+	// Prepare - prepares the Condition, returns an error if Condition is malformed.
+	Prepare() error
+}
+
+// ContextCondition - optional interface for Conditions that need context.Context while being checked,
+// e.g. the ones querying a database or a cache. When implemented, CheckContext is called instead of Check,
+// receiving the context passed to AccessManager's AuthorizeContext (or background context otherwise).
+type ContextCondition interface {
+	Condition
+
+	// CheckContext - works as Check, honoring given context's cancellation and deadline.
+	CheckContext(ctx context.Context, request *AccessRequest) error
+}
+
+// CopyableCondition - optional interface for Conditions that can be deeply copied. All built-in Conditions
+// implement it. PolicyManager copies Conditions whenever it returns or stores a copy of the policy,
+// so modifying a returned Condition never affects the policy in use - custom Conditions that do not
+// implement CopyableCondition are shared between the copies, and should not be modified.
+type CopyableCondition interface {
+	Condition
+
+	// Copy - returns a deep copy of the Condition.
+	Copy() Condition
+}
+
+// copyCondition - returns a deep copy of given Condition, if it implements CopyableCondition.
+// Returns the Condition itself otherwise.
+func copyCondition(condition Condition) Condition {
+	if copyable, ok := condition.(CopyableCondition); ok {
+		return copyable.Copy()
+	}
+
+	return condition
+}
+
 // Conditions - alias type for Conditions array.
 type Conditions []Condition
 
+// copy - returns a deep copy of the Conditions (see copyCondition).
+func (cs Conditions) copy() Conditions {
+	if cs == nil {
+		return nil
+	}
+
+	result := make(Conditions, len(cs))
+
+	for i, condition := range cs {
+		result[i] = copyCondition(condition)
+	}
+
+	return result
+}
+
 // AppendCondition - adds new Condition to Conditions slice.
 func (cs *Conditions) appendCondition(condition Condition) {
 	if cs == nil {
@@ -29,6 +89,65 @@ func (cs *Conditions) appendCondition(condition Condition) {
 	*cs = append(*cs, condition)
 }
 
+// prepare - prepares all Conditions implementing PreparableCondition interface.
+func (cs Conditions) prepare() error {
+	for _, condition := range cs {
+		if preparable, ok := condition.(PreparableCondition); ok {
+			if err := preparable.Prepare(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkConditionContext - checks given Condition against given AccessRequest, passing request's context
+// to Conditions implementing ContextCondition. Returns context's error if it's already done.
+func checkConditionContext(condition Condition, request *AccessRequest) error {
+	ctx := request.getContext()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if contextCondition, ok := condition.(ContextCondition); ok {
+		return contextCondition.CheckContext(ctx, request)
+	}
+
+	return condition.Check(request)
+}
+
+// check - checks all Conditions against given AccessRequest. Returns nil if all of them are
+// satisfied, ConditionErrors otherwise. If any Condition returns an error other than
+// ConditionNotSatisfiedError, it is returned directly.
+func (cs Conditions) check(request *AccessRequest) (ConditionErrors, error) {
+	conditionErrors := ConditionErrors{}
+
+	for _, condition := range cs {
+		if err := checkCondition(condition, request); err != nil {
+			// If error returned is ConditionNotSatisfiedError, we add it to the result slice.
+			// Otherwise, we want to abort immediately and return it directly.
+			if conditionError, ok := err.(*ConditionNotSatisfiedError); ok {
+				conditionErrors = append(conditionErrors, conditionError)
+
+				// If CompleteValidation is not enabled, we return first encountered error.
+				if !request.CompleteValidation {
+					break
+				}
+			} else {
+				return nil, err
+			}
+		}
+	}
+
+	if len(conditionErrors) > 0 {
+		return conditionErrors, nil
+	}
+
+	return nil, nil
+}
+
 // jsonMarshalableCondition - helper type for handling marshaling/unmarshaling
 // of JSON structures.
 type jsonMarshalableCondition struct {
@@ -36,6 +155,40 @@ type jsonMarshalableCondition struct {
 	Options json.RawMessage `json:"options,omitempty"`
 }
 
+// newJSONMarshalableCondition - returns new jsonMarshalableCondition instance, with
+// Condition's marshaled options.
+func newJSONMarshalableCondition(condition Condition) (*jsonMarshalableCondition, error) {
+	options, err := json.Marshal(condition)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jsonMarshalableCondition{
+		Type:    condition.Type(),
+		Options: json.RawMessage(options),
+	}, nil
+}
+
+// toCondition - creates a Condition using ConditionFactory registered for given type,
+// and unmarshals options into it.
+func (jc *jsonMarshalableCondition) toCondition() (Condition, error) {
+	factory := ConditionFactories[jc.Type]
+
+	if factory == nil {
+		return nil, newConditionFactoryNotFoundError(jc.Type)
+	}
+
+	condition := factory()
+
+	if len(jc.Options) > 0 {
+		if err := json.Unmarshal(jc.Options, condition); err != nil {
+			return nil, err
+		}
+	}
+
+	return condition, nil
+}
+
 // yamlMarshalableCondition - helper type for handling marshaling/unmarshaling
 // of YAML structures.
 type yamlMarshalableCondition struct {
@@ -43,20 +196,53 @@ type yamlMarshalableCondition struct {
 	Options yaml.Node `yaml:"options,omitempty"`
 }
 
+// newYAMLMarshalableCondition - returns new yamlMarshalableCondition instance, with
+// Condition's encoded options.
+func newYAMLMarshalableCondition(condition Condition) (*yamlMarshalableCondition, error) {
+	options := yaml.Node{}
+
+	if err := options.Encode(condition); err != nil {
+		return nil, err
+	}
+
+	return &yamlMarshalableCondition{
+		Type:    condition.Type(),
+		Options: options,
+	}, nil
+}
+
+// toCondition - creates a Condition using ConditionFactory registered for given type,
+// and decodes options into it.
+func (yc *yamlMarshalableCondition) toCondition() (Condition, error) {
+	factory := ConditionFactories[yc.Type]
+
+	if factory == nil {
+		return nil, newConditionFactoryNotFoundError(yc.Type)
+	}
+
+	condition := factory()
+
+	// Options can also be a scalar value, for Conditions that accept a shorthand form.
+	if len(yc.Options.Content) > 0 || (yc.Options.Kind == yaml.ScalarNode && yc.Options.Tag != "!!null") {
+		if err := yc.Options.Decode(condition); err != nil {
+			return nil, err
+		}
+	}
+
+	return condition, nil
+}
+
 // MarshalJSON - marshals a map of Conditions to JSON data.
 func (cs Conditions) MarshalJSON() ([]byte, error) {
 	result := []*jsonMarshalableCondition{}
 
 	for _, condition := range cs {
-		options, err := json.Marshal(condition)
+		jsonCondition, err := newJSONMarshalableCondition(condition)
 		if err != nil {
 			return nil, err
 		}
 
-		result = append(result, &jsonMarshalableCondition{
-			Type:    condition.Type(),
-			Options: json.RawMessage(options),
-		})
+		result = append(result, jsonCondition)
 	}
 
 	return json.Marshal(result)
@@ -67,16 +253,12 @@ func (cs Conditions) MarshalYAML() (interface{}, error) {
 	result := []*yamlMarshalableCondition{}
 
 	for _, condition := range cs {
-		options := yaml.Node{}
-
-		if err := options.Encode(condition); err != nil {
+		yamlCondition, err := newYAMLMarshalableCondition(condition)
+		if err != nil {
 			return nil, err
 		}
 
-		result = append(result, &yamlMarshalableCondition{
-			Type:    condition.Type(),
-			Options: options,
-		})
+		result = append(result, yamlCondition)
 	}
 
 	output := yaml.Node{}
@@ -97,18 +279,9 @@ func (cs *Conditions) UnmarshalJSON(jsonData []byte) error {
 	}
 
 	for _, jsonCondition := range jsonValue {
-		factory := ConditionFactories[jsonCondition.Type]
-
-		if factory == nil {
-			return newConditionFactoryNotFoundError(jsonCondition.Type)
-		}
-
-		condition := factory()
-
-		if len(jsonCondition.Options) > 0 {
-			if err := json.Unmarshal(jsonCondition.Options, condition); err != nil {
-				return err
-			}
+		condition, err := jsonCondition.toCondition()
+		if err != nil {
+			return err
 		}
 
 		cs.appendCondition(condition)
@@ -132,18 +305,9 @@ func (cs *Conditions) UnmarshalYAML(value *yaml.Node) error {
 			continue
 		}
 
-		factory := ConditionFactories[yamlCondition.Type]
-
-		if factory == nil {
-			return newConditionFactoryNotFoundError(yamlCondition.Type)
-		}
-
-		condition := factory()
-
-		if len(yamlCondition.Options.Content) > 0 {
-			if err := yamlCondition.Options.Decode(condition); err != nil {
-				return err
-			}
+		condition, err := yamlCondition.toCondition()
+		if err != nil {
+			return err
 		}
 
 		cs.appendCondition(condition)
@@ -173,15 +337,96 @@ var ConditionFactories = ConditionFactoriesMap{
 	NotEmptyConditionType: func() Condition {
 		return new(NotEmptyCondition)
 	},
+	GreaterThanConditionType: func() Condition {
+		return new(GreaterThanCondition)
+	},
+	GreaterThanAliasConditionType: func() Condition {
+		return new(GreaterThanCondition)
+	},
+	GreaterThanOrEqualConditionType: func() Condition {
+		return new(GreaterThanOrEqualCondition)
+	},
+	LessThanConditionType: func() Condition {
+		return new(LessThanCondition)
+	},
+	LessThanOrEqualConditionType: func() Condition {
+		return new(LessThanOrEqualCondition)
+	},
+	BetweenConditionType: func() Condition {
+		return new(BetweenCondition)
+	},
+	InConditionType: func() Condition {
+		return new(InCondition)
+	},
+	NotInConditionType: func() Condition {
+		return new(NotInCondition)
+	},
+	ContainsConditionType: func() Condition {
+		return new(ContainsCondition)
+	},
+	IntersectsConditionType: func() Condition {
+		return new(IntersectsCondition)
+	},
+	MatchesConditionType: func() Condition {
+		return new(MatchesCondition)
+	},
+	GlobConditionType: func() Condition {
+		return new(GlobCondition)
+	},
+	StartsWithConditionType: func() Condition {
+		return new(StartsWithCondition)
+	},
+	EndsWithConditionType: func() Condition {
+		return new(EndsWithCondition)
+	},
+	BeforeConditionType: func() Condition {
+		return new(BeforeCondition)
+	},
+	AfterConditionType: func() Condition {
+		return new(AfterCondition)
+	},
+	TimeWindowConditionType: func() Condition {
+		return new(TimeWindowCondition)
+	},
+	ScheduleConditionType: func() Condition {
+		return new(ScheduleCondition)
+	},
+	IPInCIDRConditionType: func() Condition {
+		return new(IPInCIDRCondition)
+	},
+	IPNotInCIDRConditionType: func() Condition {
+		return new(IPNotInCIDRCondition)
+	},
+	AndConditionType: func() Condition {
+		return new(AndCondition)
+	},
+	OrConditionType: func() Condition {
+		return new(OrCondition)
+	},
+	NotConditionType: func() Condition {
+		return new(NotCondition)
+	},
+	ExpressionConditionType: func() Condition {
+		return new(ExpressionCondition)
+	},
+}
+
+// replaceableConditionTypes - deprecated aliases of built-in Conditions' types, which can be
+// replaced with custom Conditions once.
+var replaceableConditionTypes = map[string]bool{
+	GreaterThanAliasConditionType: true,
 }
 
 // RegisterConditionFactory - adds a new ConditionFactory under given name. If given name
-// is already taken, an error is returned.
+// is already taken, an error is returned - unless it's a deprecated alias of a built-in
+// Condition's type (e.g. GREATER_THAN), which is replaced.
 func RegisterConditionFactory(name string, factory ConditionFactory) error {
-	if ConditionFactories[name] != nil {
+	if ConditionFactories[name] != nil && !replaceableConditionTypes[name] {
 		return newConditionFactoryAlreadyExistsError(name)
 	}
 
+	delete(replaceableConditionTypes, name)
+
 	ConditionFactories[name] = factory
 	return nil
 }
@@ -0,0 +1,219 @@
+package restrict
+
+import (
+	"fmt"
+
+	"github.com/el-mike/restrict/v2/internal/utils"
+)
+
+const (
+	// GreaterThanConditionType - GreaterThanCondition's type identifier.
+	GreaterThanConditionType = "GT"
+	// GreaterThanOrEqualConditionType - GreaterThanOrEqualCondition's type identifier.
+	GreaterThanOrEqualConditionType = "GTE"
+	// LessThanConditionType - LessThanCondition's type identifier.
+	LessThanConditionType = "LT"
+	// LessThanOrEqualConditionType - LessThanOrEqualCondition's type identifier.
+	LessThanOrEqualConditionType = "LTE"
+	// BetweenConditionType - BetweenCondition's type identifier.
+	BetweenConditionType = "BETWEEN"
+
+	// GreaterThanAliasConditionType - deprecated alias of GreaterThanConditionType. Custom Conditions
+	// were commonly registered under this name, so registering one replaces the alias.
+	//
+	// Deprecated: use GreaterThanConditionType instead.
+	GreaterThanAliasConditionType = "GREATER_THAN"
+)
+
+// baseComparisonCondition - describes fields needed by numeric and time comparison Conditions.
+type baseComparisonCondition struct {
+	// ID - Condition's id, useful when there is a need to identify failing Condition.
+	ID string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Left - ValueDescriptor for left operand of the comparison.
+	Left *ValueDescriptor `json:"left" yaml:"left"`
+	// Right - ValueDescriptor for right operand of the comparison.
+	Right *ValueDescriptor `json:"right" yaml:"right"`
+}
+
+// copy - returns a deep copy of the baseComparisonCondition.
+func (c *baseComparisonCondition) copy() *baseComparisonCondition {
+	if c == nil {
+		return nil
+	}
+
+	result := *c
+	result.Left = c.Left.copy()
+	result.Right = c.Right.copy()
+
+	return &result
+}
+
+// GreaterThanCondition - checks whether given value (Left) is greater than some other value (Right).
+type GreaterThanCondition baseComparisonCondition
+
+// Type - returns Condition's type.
+func (c *GreaterThanCondition) Type() string {
+	return GreaterThanConditionType
+}
+
+// Copy - returns a deep copy of the Condition.
+func (c *GreaterThanCondition) Copy() Condition {
+	return (*GreaterThanCondition)((*baseComparisonCondition)(c).copy())
+}
+
+// Check - returns true if Left is greater than Right, false otherwise.
+func (c *GreaterThanCondition) Check(request *AccessRequest) error {
+	return checkComparison(c, c.Left, c.Right, request, func(result int) bool {
+		return result > 0
+	}, "is not greater than")
+}
+
+// GreaterThanOrEqualCondition - checks whether given value (Left) is greater than
+// or equal to some other value (Right).
+type GreaterThanOrEqualCondition baseComparisonCondition
+
+// Type - returns Condition's type.
+func (c *GreaterThanOrEqualCondition) Type() string {
+	return GreaterThanOrEqualConditionType
+}
+
+// Copy - returns a deep copy of the Condition.
+func (c *GreaterThanOrEqualCondition) Copy() Condition {
+	return (*GreaterThanOrEqualCondition)((*baseComparisonCondition)(c).copy())
+}
+
+// Check - returns true if Left is greater than or equal to Right, false otherwise.
+func (c *GreaterThanOrEqualCondition) Check(request *AccessRequest) error {
+	return checkComparison(c, c.Left, c.Right, request, func(result int) bool {
+		return result >= 0
+	}, "is less than")
+}
+
+// LessThanCondition - checks whether given value (Left) is less than some other value (Right).
+type LessThanCondition baseComparisonCondition
+
+// Type - returns Condition's type.
+func (c *LessThanCondition) Type() string {
+	return LessThanConditionType
+}
+
+// Copy - returns a deep copy of the Condition.
+func (c *LessThanCondition) Copy() Condition {
+	return (*LessThanCondition)((*baseComparisonCondition)(c).copy())
+}
+
+// Check - returns true if Left is less than Right, false otherwise.
+func (c *LessThanCondition) Check(request *AccessRequest) error {
+	return checkComparison(c, c.Left, c.Right, request, func(result int) bool {
+		return result < 0
+	}, "is not less than")
+}
+
+// LessThanOrEqualCondition - checks whether given value (Left) is less than
+// or equal to some other value (Right).
+type LessThanOrEqualCondition baseComparisonCondition
+
+// Type - returns Condition's type.
+func (c *LessThanOrEqualCondition) Type() string {
+	return LessThanOrEqualConditionType
+}
+
+// Copy - returns a deep copy of the Condition.
+func (c *LessThanOrEqualCondition) Copy() Condition {
+	return (*LessThanOrEqualCondition)((*baseComparisonCondition)(c).copy())
+}
+
+// Check - returns true if Left is less than or equal to Right, false otherwise.
+func (c *LessThanOrEqualCondition) Check(request *AccessRequest) error {
+	return checkComparison(c, c.Left, c.Right, request, func(result int) bool {
+		return result <= 0
+	}, "is greater than")
+}
+
+// BetweenCondition - checks whether given value (Value) lies within a range
+// described by Min and Max values, inclusive.
+type BetweenCondition struct {
+	// ID - Condition's id, useful when there is a need to identify failing Condition.
+	ID string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Value - ValueDescriptor for the value being checked.
+	Value *ValueDescriptor `json:"value" yaml:"value"`
+	// Min - ValueDescriptor for the lower bound of the range.
+	Min *ValueDescriptor `json:"min" yaml:"min"`
+	// Max - ValueDescriptor for the upper bound of the range.
+	Max *ValueDescriptor `json:"max" yaml:"max"`
+}
+
+// Type - returns Condition's type.
+func (c *BetweenCondition) Type() string {
+	return BetweenConditionType
+}
+
+// Copy - returns a deep copy of the Condition.
+func (c *BetweenCondition) Copy() Condition {
+	if c == nil {
+		return c
+	}
+
+	result := *c
+	result.Value = c.Value.copy()
+	result.Min = c.Min.copy()
+	result.Max = c.Max.copy()
+
+	return &result
+}
+
+// Check - returns true if Value is between Min and Max (inclusive), false otherwise.
+func (c *BetweenCondition) Check(request *AccessRequest) error {
+	value, err := c.Value.GetValue(request)
+	if err != nil {
+		return err
+	}
+
+	min, max, err := unpackDescriptors(c.Min, c.Max, request)
+	if err != nil {
+		return err
+	}
+
+	lowerResult, ok := utils.CompareNumbers(value, min)
+	if !ok {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("values \"%v\" and \"%v\" are not comparable numbers", value, min))
+	}
+
+	upperResult, ok := utils.CompareNumbers(value, max)
+	if !ok {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("values \"%v\" and \"%v\" are not comparable numbers", value, max))
+	}
+
+	if lowerResult < 0 || upperResult > 0 {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("value \"%v\" is not between \"%v\" and \"%v\"", value, min, max))
+	}
+
+	return nil
+}
+
+// checkComparison - helper function for resolving and comparing operands of numeric comparison
+// Conditions. Returns ConditionNotSatisfiedError if values are not numbers, or if passed
+// predicate is not satisfied by the comparison result.
+func checkComparison(
+	condition Condition,
+	left, right *ValueDescriptor,
+	request *AccessRequest,
+	predicate func(result int) bool,
+	failureDescription string,
+) error {
+	leftValue, rightValue, err := unpackDescriptors(left, right, request)
+	if err != nil {
+		return err
+	}
+
+	result, ok := utils.CompareNumbers(leftValue, rightValue)
+	if !ok {
+		return NewConditionNotSatisfiedError(condition, request, fmt.Errorf("values \"%v\" and \"%v\" are not comparable numbers", leftValue, rightValue))
+	}
+
+	if !predicate(result) {
+		return NewConditionNotSatisfiedError(condition, request, fmt.Errorf("value \"%v\" %s \"%v\"", leftValue, failureDescription, rightValue))
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package restrict
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+type comparisonConditionSuite struct {
+	suite.Suite
+}
+
+func TestComparisonConditionSuite(t *testing.T) {
+	suite.Run(t, new(comparisonConditionSuite))
+}
+
+func (s *comparisonConditionSuite) TestType() {
+	assert.Equal(s.T(), GreaterThanConditionType, (&GreaterThanCondition{}).Type())
+	assert.Equal(s.T(), GreaterThanOrEqualConditionType, (&GreaterThanOrEqualCondition{}).Type())
+	assert.Equal(s.T(), LessThanConditionType, (&LessThanCondition{}).Type())
+	assert.Equal(s.T(), LessThanOrEqualConditionType, (&LessThanOrEqualCondition{}).Type())
+	assert.Equal(s.T(), BetweenConditionType, (&BetweenCondition{}).Type())
+}
+
+func (s *comparisonConditionSuite) TestCheck_Comparison() {
+	testResource := new(resourceMock)
+	testResource.FieldTwo = 10
+
+	testRequest := &AccessRequest{
+		Resource: testResource,
+		Context: Context{
+			"Limit": uint8(10),
+			"Name":  "test",
+		},
+	}
+
+	left := &ValueDescriptor{Source: ResourceField, Field: "FieldTwo"}
+
+	testCases := []struct {
+		condition Condition
+		right     interface{}
+		satisfied bool
+	}{
+		{&GreaterThanCondition{Left: left}, 9, true},
+		{&GreaterThanCondition{Left: left}, 10, false},
+		{&GreaterThanCondition{Left: left}, 9.5, true},
+		{&GreaterThanOrEqualCondition{Left: left}, uint(10), true},
+		{&GreaterThanOrEqualCondition{Left: left}, 10.5, false},
+		{&LessThanCondition{Left: left}, 11, true},
+		{&LessThanCondition{Left: left}, float32(10), false},
+		{&LessThanOrEqualCondition{Left: left}, int64(10), true},
+		{&LessThanOrEqualCondition{Left: left}, -1, false},
+	}
+
+	for _, testCase := range testCases {
+		right := &ValueDescriptor{Source: Explicit, Value: testCase.right}
+
+		switch condition := testCase.condition.(type) {
+		case *GreaterThanCondition:
+			condition.Right = right
+		case *GreaterThanOrEqualCondition:
+			condition.Right = right
+		case *LessThanCondition:
+			condition.Right = right
+		case *LessThanOrEqualCondition:
+			condition.Right = right
+		}
+
+		err := testCase.condition.Check(testRequest)
+
+		if testCase.satisfied {
+			assert.Nil(s.T(), err, "%s %v", testCase.condition.Type(), testCase.right)
+		} else {
+			assert.IsType(s.T(), new(ConditionNotSatisfiedError), err, "%s %v", testCase.condition.Type(), testCase.right)
+		}
+	}
+
+	// Mixed kinds taken from Context.
+	testCondition := &LessThanOrEqualCondition{
+		Left:  left,
+		Right: &ValueDescriptor{Source: ContextField, Field: "Limit"},
+	}
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	// Not a number.
+	testCondition.Right = &ValueDescriptor{Source: ContextField, Field: "Name"}
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+
+	// Malformed descriptor.
+	testCondition.Right = &ValueDescriptor{Source: ContextField}
+
+	assert.IsType(s.T(), new(ValueDescriptorMalformedError), testCondition.Check(testRequest))
+}
+
+func (s *comparisonConditionSuite) TestCheck_Between() {
+	testResource := new(resourceMock)
+	testResource.FieldTwo = 10
+
+	testRequest := &AccessRequest{
+		Resource: testResource,
+	}
+
+	testCondition := &BetweenCondition{
+		Value: &ValueDescriptor{Source: ResourceField, Field: "FieldTwo"},
+		Min:   &ValueDescriptor{Source: Explicit, Value: 10},
+		Max:   &ValueDescriptor{Source: Explicit, Value: 20.0},
+	}
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	testResource.FieldTwo = 20
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	testResource.FieldTwo = 21
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+
+	testResource.FieldTwo = 9
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+
+	testCondition.Min = &ValueDescriptor{Source: Explicit, Value: "1"}
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+
+	testCondition.Max = nil
+
+	assert.IsType(s.T(), new(ValueDescriptorMalformedError), testCondition.Check(testRequest))
+}
+
+func (s *comparisonConditionSuite) TestMarshaling() {
+	testConditions := Conditions{
+		&LessThanCondition{
+			ID:    "belowLimit",
+			Left:  &ValueDescriptor{Source: ResourceField, Field: "Amount"},
+			Right: &ValueDescriptor{Source: Explicit, Value: 100},
+		},
+		&BetweenCondition{
+			Value: &ValueDescriptor{Source: ResourceField, Field: "Amount"},
+			Min:   &ValueDescriptor{Source: Explicit, Value: 1},
+			Max:   &ValueDescriptor{Source: ContextField, Field: "Max"},
+		},
+	}
+
+	conditionsJSON, err := json.Marshal(testConditions)
+	assert.Nil(s.T(), err)
+
+	jsonConditions := Conditions{}
+	err = json.Unmarshal(conditionsJSON, &jsonConditions)
+
+	assert.Nil(s.T(), err)
+	assert.IsType(s.T(), new(LessThanCondition), jsonConditions[0])
+	assert.IsType(s.T(), new(BetweenCondition), jsonConditions[1])
+	assert.Equal(s.T(), "belowLimit", jsonConditions[0].(*LessThanCondition).ID)
+	assert.Equal(s.T(), "Max", jsonConditions[1].(*BetweenCondition).Max.Field)
+
+	conditionsYAML, err := yaml.Marshal(testConditions)
+	assert.Nil(s.T(), err)
+
+	yamlConditions := Conditions{}
+	err = yaml.Unmarshal(conditionsYAML, &yamlConditions)
+
+	assert.Nil(s.T(), err)
+	assert.IsType(s.T(), new(LessThanCondition), yamlConditions[0])
+	assert.IsType(s.T(), new(BetweenCondition), yamlConditions[1])
+	assert.Equal(s.T(), 100, yamlConditions[0].(*LessThanCondition).Right.Value)
+}
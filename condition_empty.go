@@ -20,6 +20,18 @@ type baseEmptyCondition struct {
 	Value *ValueDescriptor `json:"value" yaml:"value"`
 }
 
+// copy - returns a deep copy of the baseEmptyCondition.
+func (c *baseEmptyCondition) copy() *baseEmptyCondition {
+	if c == nil {
+		return nil
+	}
+
+	result := *c
+	result.Value = c.Value.copy()
+
+	return &result
+}
+
 // EmptyCondition - Condition for testing whether given value is empty.
 type EmptyCondition baseEmptyCondition
 
@@ -28,6 +40,11 @@ func (c *EmptyCondition) Type() string {
 	return EmptyConditionType
 }
 
+// Copy - returns a deep copy of the Condition.
+func (c *EmptyCondition) Copy() Condition {
+	return (*EmptyCondition)((*baseEmptyCondition)(c).copy())
+}
+
 // Check - returns true if value is empty (zero-like), false otherwise.
 func (c *EmptyCondition) Check(request *AccessRequest) error {
 	value, err := c.Value.GetValue(request)
@@ -55,6 +72,11 @@ func (c *NotEmptyCondition) Type() string {
 	return NotEmptyConditionType
 }
 
+// Copy - returns a deep copy of the Condition.
+func (c *NotEmptyCondition) Copy() Condition {
+	return (*NotEmptyCondition)((*baseEmptyCondition)(c).copy())
+}
+
 // Check - returns true if value is not empty (zero-like), false otherwise.
 func (c *NotEmptyCondition) Check(request *AccessRequest) error {
 	value, err := c.Value.GetValue(request)
@@ -22,6 +22,19 @@ type baseEqualCondition struct {
 	Right *ValueDescriptor `json:"right" yaml:"right"`
 }
 
+// copy - returns a deep copy of the baseEqualCondition.
+func (c *baseEqualCondition) copy() *baseEqualCondition {
+	if c == nil {
+		return nil
+	}
+
+	result := *c
+	result.Left = c.Left.copy()
+	result.Right = c.Right.copy()
+
+	return &result
+}
+
 // EqualCondition - checks whether given value (Left) is equal to some other value (Right).
 type EqualCondition baseEqualCondition
 
@@ -30,9 +43,14 @@ func (c *EqualCondition) Type() string {
 	return EqualConditionType
 }
 
+// Copy - returns a deep copy of the Condition.
+func (c *EqualCondition) Copy() Condition {
+	return (*EqualCondition)((*baseEqualCondition)(c).copy())
+}
+
 // Check - returns true if values are equal, false otherwise.
 func (c *EqualCondition) Check(request *AccessRequest) error {
-	left, right, err := unpackEqualDescriptors(c.Left, c.Right, request)
+	left, right, err := unpackDescriptors(c.Left, c.Right, request)
 	if err != nil {
 		return err
 	}
@@ -52,9 +70,14 @@ func (c *NotEqualCondition) Type() string {
 	return NotEqualConditionType
 }
 
+// Copy - returns a deep copy of the Condition.
+func (c *NotEqualCondition) Copy() Condition {
+	return (*NotEqualCondition)((*baseEqualCondition)(c).copy())
+}
+
 // Check - returns true if values are not equal, false otherwise.
 func (c *NotEqualCondition) Check(request *AccessRequest) error {
-	left, right, err := unpackEqualDescriptors(c.Left, c.Right, request)
+	left, right, err := unpackDescriptors(c.Left, c.Right, request)
 	if err != nil {
 		return err
 	}
@@ -67,7 +90,7 @@ func (c *NotEqualCondition) Check(request *AccessRequest) error {
 }
 
 // unpackDescriptors - helper function for unpacking ValueDescriptors' values.
-func unpackEqualDescriptors(left, right *ValueDescriptor, request *AccessRequest) (interface{}, interface{}, error) {
+func unpackDescriptors(left, right *ValueDescriptor, request *AccessRequest) (interface{}, interface{}, error) {
 	leftValue, err := left.GetValue(request)
 	if err != nil {
 		return nil, nil, err
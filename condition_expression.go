@@ -0,0 +1,183 @@
+package restrict
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/el-mike/restrict/v2/internal/expression"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ExpressionConditionType - ExpressionCondition's type identifier.
+	ExpressionConditionType = "EXPRESSION"
+)
+
+// expressionRoots - maps roots allowed in expressions to ValueSources used for
+// resolving their values.
+var expressionRoots = map[string]ValueSource{
+	"subject":  SubjectField,
+	"resource": ResourceField,
+	"context":  ContextField,
+}
+
+// ExpressionCondition - checks whether a boolean expression is satisfied.
+// Expression can reference Subject's, Resource's and Context's fields via "subject",
+// "resource" and "context" roots (e.g. "subject.TenantID == resource.TenantID"), which
+// are resolved with the same rules as ValueDescriptor's fields.
+//
+// Supported operators are: "==", "!=", "<", "<=", ">", ">=", "in", "&&", "||" and "!".
+// Supported literals are strings, numbers, booleans, null and lists (e.g. ["a", "b"]).
+type ExpressionCondition struct {
+	// ID - Condition's id, useful when there is a need to identify failing Condition.
+	ID string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Expression - source of the boolean expression.
+	Expression string `json:"expression" yaml:"expression"`
+
+	// Parsed Expression, set when the Condition is prepared.
+	parsed *expression.Expression
+}
+
+// expressionConditionOptions - helper type for unmarshaling ExpressionCondition
+// without recursion.
+type expressionConditionOptions ExpressionCondition
+
+// NewExpressionCondition - returns new ExpressionCondition instance with already
+// parsed expression, or ConditionMalformedError if expression is not valid.
+func NewExpressionCondition(id, source string) (*ExpressionCondition, error) {
+	condition := &ExpressionCondition{
+		ID:         id,
+		Expression: source,
+	}
+
+	if err := condition.Prepare(); err != nil {
+		return nil, err
+	}
+
+	return condition, nil
+}
+
+// Type - returns Condition's type.
+func (c *ExpressionCondition) Type() string {
+	return ExpressionConditionType
+}
+
+// Copy - returns a deep copy of the Condition.
+func (c *ExpressionCondition) Copy() Condition {
+	if c == nil {
+		return c
+	}
+
+	// Parsed expression is never modified, so it can be shared.
+	result := *c
+
+	return &result
+}
+
+// Prepare - parses the expression, returns ConditionMalformedError if it's not valid.
+func (c *ExpressionCondition) Prepare() error {
+	if c.parsed != nil && c.parsed.Source() == c.Expression {
+		return nil
+	}
+
+	parsed, err := c.parse()
+	if err != nil {
+		return err
+	}
+
+	c.parsed = parsed
+
+	return nil
+}
+
+// Check - returns true if expression evaluates to true, false otherwise.
+func (c *ExpressionCondition) Check(request *AccessRequest) error {
+	parsed := c.parsed
+
+	// If the Condition has not been prepared (or the expression has changed since),
+	// the expression is parsed for this check only.
+	if parsed == nil || parsed.Source() != c.Expression {
+		var err error
+
+		if parsed, err = c.parse(); err != nil {
+			return err
+		}
+	}
+
+	result, err := parsed.Evaluate(func(path *expression.Path) (interface{}, error) {
+		descriptor := &ValueDescriptor{
+			Source: expressionRoots[path.Root],
+			Field:  path.Field,
+		}
+
+		return descriptor.GetValue(request)
+	})
+	if err != nil {
+		if evaluationError, ok := err.(*expression.EvaluationError); ok {
+			return NewConditionNotSatisfiedError(c, request, evaluationError)
+		}
+
+		return err
+	}
+
+	if !result {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("expression \"%s\" evaluated to false", c.Expression))
+	}
+
+	return nil
+}
+
+// UnmarshalJSON - unmarshals ExpressionCondition and parses its expression.
+// Options can be either an object, or just the expression string.
+func (c *ExpressionCondition) UnmarshalJSON(jsonData []byte) error {
+	var source string
+
+	if err := json.Unmarshal(jsonData, &source); err == nil {
+		c.Expression = source
+		return c.Prepare()
+	}
+
+	if err := json.Unmarshal(jsonData, (*expressionConditionOptions)(c)); err != nil {
+		return err
+	}
+
+	return c.Prepare()
+}
+
+// UnmarshalYAML - unmarshals ExpressionCondition and parses its expression.
+// Options can be either a map, or just the expression string.
+func (c *ExpressionCondition) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		if err := value.Decode(&c.Expression); err != nil {
+			return err
+		}
+
+		return c.Prepare()
+	}
+
+	if err := value.Decode((*expressionConditionOptions)(c)); err != nil {
+		return err
+	}
+
+	return c.Prepare()
+}
+
+// parse - parses the expression and validates used roots.
+func (c *ExpressionCondition) parse() (*expression.Expression, error) {
+	parsed, err := expression.Parse(c.Expression)
+	if err != nil {
+		return nil, newConditionMalformedError(c, err)
+	}
+
+	for _, path := range parsed.Paths() {
+		if _, ok := expressionRoots[path.Root]; !ok {
+			return nil, newConditionMalformedError(c, fmt.Errorf("unknown root \"%s\" - expected one of: subject, resource, context", path.Root))
+		}
+
+		if path.Field == "" {
+			return nil, newConditionMalformedError(c, fmt.Errorf("missing field for root \"%s\"", path.Root))
+		}
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,140 @@
+package restrict
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+type expressionConditionSuite struct {
+	suite.Suite
+}
+
+func TestExpressionConditionSuite(t *testing.T) {
+	suite.Run(t, new(expressionConditionSuite))
+}
+
+func (s *expressionConditionSuite) TestType() {
+	testCondition := &ExpressionCondition{}
+
+	assert.Equal(s.T(), ExpressionConditionType, testCondition.Type())
+}
+
+func (s *expressionConditionSuite) TestNewExpressionCondition() {
+	testCondition, err := NewExpressionCondition("testId", "subject.ID == resource.CreatedBy")
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "testId", testCondition.ID)
+	assert.NotNil(s.T(), testCondition.parsed)
+
+	invalidSources := []string{
+		"subject.ID ==",
+		"owner.ID == 1",
+		"subject == 1",
+	}
+
+	for _, source := range invalidSources {
+		testCondition, err = NewExpressionCondition("", source)
+
+		assert.Nil(s.T(), testCondition)
+		assert.IsType(s.T(), new(ConditionMalformedError), err, source)
+	}
+}
+
+func (s *expressionConditionSuite) TestCheck() {
+	testSubject := new(subjectMock)
+	testSubject.ID = "user-one"
+	testSubject.FieldOne = "tenant-one"
+
+	testResource := new(resourceMock)
+	testResource.CreatedBy = "user-two"
+	testResource.FieldOne = "tenant-one"
+
+	testRequest := &AccessRequest{
+		Subject:  testSubject,
+		Resource: testResource,
+		Context: Context{
+			"ip": "10.0.0.1",
+		},
+	}
+
+	testCondition, err := NewExpressionCondition("", `subject.FieldOne == resource.FieldOne && context.ip in ["10.0.0.1"]`)
+	assert.Nil(s.T(), err)
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	testRequest.Context["ip"] = "10.0.0.2"
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+
+	// Not prepared Condition.
+	testCondition = &ExpressionCondition{Expression: "subject.ID == resource.CreatedBy || resource.FieldTwo < 1"}
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	// Changed expression.
+	testCondition.Expression = "subject.ID == resource.CreatedBy"
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+
+	// Malformed expression.
+	testCondition.Expression = "subject.ID == "
+
+	assert.IsType(s.T(), new(ConditionMalformedError), testCondition.Check(testRequest))
+
+	// Types mismatch.
+	testCondition.Expression = "subject.ID > 1"
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+
+	// Field does not exist.
+	testCondition.Expression = "subject.MissingField == 1"
+
+	assert.IsType(s.T(), new(ValueDescriptorMalformedError), testCondition.Check(testRequest))
+}
+
+func (s *expressionConditionSuite) TestMarshaling() {
+	testCondition, err := NewExpressionCondition("sameTenant", "subject.TenantID == resource.TenantID")
+	assert.Nil(s.T(), err)
+
+	conditionsJSON, err := json.Marshal(Conditions{testCondition})
+	assert.Nil(s.T(), err)
+
+	jsonConditions := Conditions{}
+	err = json.Unmarshal(conditionsJSON, &jsonConditions)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), testCondition, jsonConditions[0])
+
+	conditionsYAML, err := yaml.Marshal(Conditions{testCondition})
+	assert.Nil(s.T(), err)
+
+	yamlConditions := Conditions{}
+	err = yaml.Unmarshal(conditionsYAML, &yamlConditions)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), testCondition, yamlConditions[0])
+
+	// Shorthand form.
+	err = json.Unmarshal([]byte(`[{"type": "EXPRESSION", "options": "subject.ID == 1"}]`), &jsonConditions)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "subject.ID == 1", jsonConditions[1].(*ExpressionCondition).Expression)
+
+	err = yaml.Unmarshal([]byte("- type: EXPRESSION\n  options: subject.ID == 1\n"), &yamlConditions)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "subject.ID == 1", yamlConditions[1].(*ExpressionCondition).Expression)
+
+	// Malformed expression.
+	err = json.Unmarshal([]byte(`[{"type": "EXPRESSION", "options": {"expression": "subject.ID =="}}]`), &Conditions{})
+
+	assert.IsType(s.T(), new(ConditionMalformedError), err)
+
+	err = yaml.Unmarshal([]byte("- type: EXPRESSION\n  options:\n    expression: subject.ID ==\n"), &Conditions{})
+
+	assert.IsType(s.T(), new(ConditionMalformedError), err)
+}
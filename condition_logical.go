@@ -0,0 +1,262 @@
+package restrict
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// AndConditionType - AndCondition's type identifier.
+	AndConditionType = "AND"
+	// OrConditionType - OrCondition's type identifier.
+	OrConditionType = "OR"
+	// NotConditionType - NotCondition's type identifier.
+	NotConditionType = "NOT"
+)
+
+// baseCompositeCondition - describes fields needed by And/Or Conditions.
+type baseCompositeCondition struct {
+	// ID - Condition's id, useful when there is a need to identify failing Condition.
+	ID string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Conditions - nested Conditions, that can be composite Conditions themselves.
+	Conditions Conditions `json:"conditions" yaml:"conditions"`
+}
+
+// copy - returns a deep copy of the baseCompositeCondition.
+func (c *baseCompositeCondition) copy() *baseCompositeCondition {
+	if c == nil {
+		return nil
+	}
+
+	result := *c
+	result.Conditions = c.Conditions.copy()
+
+	return &result
+}
+
+// AndCondition - checks whether all of the nested Conditions are satisfied.
+// When AccessRequest.CompleteValidation is set to true, all nested Conditions are checked,
+// otherwise it returns on the first failing one.
+type AndCondition baseCompositeCondition
+
+// Type - returns Condition's type.
+func (c *AndCondition) Type() string {
+	return AndConditionType
+}
+
+// Copy - returns a deep copy of the Condition.
+func (c *AndCondition) Copy() Condition {
+	return (*AndCondition)((*baseCompositeCondition)(c).copy())
+}
+
+// Check - returns true if all nested Conditions are satisfied, false otherwise.
+// Failed nested Conditions are returned as ConditionErrors in ConditionNotSatisfiedError's Reason.
+func (c *AndCondition) Check(request *AccessRequest) error {
+	conditionErrors, err := c.Conditions.check(request)
+	if err != nil {
+		return err
+	}
+
+	if conditionErrors != nil {
+		return NewConditionNotSatisfiedError(c, request, conditionErrors)
+	}
+
+	return nil
+}
+
+// Prepare - prepares nested Conditions.
+func (c *AndCondition) Prepare() error {
+	return c.Conditions.prepare()
+}
+
+// OrCondition - checks whether at least one of the nested Conditions is satisfied.
+type OrCondition baseCompositeCondition
+
+// Type - returns Condition's type.
+func (c *OrCondition) Type() string {
+	return OrConditionType
+}
+
+// Copy - returns a deep copy of the Condition.
+func (c *OrCondition) Copy() Condition {
+	return (*OrCondition)((*baseCompositeCondition)(c).copy())
+}
+
+// Check - returns true if any of nested Conditions is satisfied, false otherwise.
+// Failed nested Conditions are returned as ConditionErrors in ConditionNotSatisfiedError's Reason.
+func (c *OrCondition) Check(request *AccessRequest) error {
+	if len(c.Conditions) == 0 {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("no Conditions to satisfy"))
+	}
+
+	conditionErrors := ConditionErrors{}
+
+	for _, condition := range c.Conditions {
+		err := checkCondition(condition, request)
+		if err == nil {
+			return nil
+		}
+
+		conditionError, ok := err.(*ConditionNotSatisfiedError)
+		if !ok {
+			return err
+		}
+
+		conditionErrors = append(conditionErrors, conditionError)
+	}
+
+	return NewConditionNotSatisfiedError(c, request, conditionErrors)
+}
+
+// Prepare - prepares nested Conditions.
+func (c *OrCondition) Prepare() error {
+	return c.Conditions.prepare()
+}
+
+// NotCondition - checks whether nested Condition is not satisfied.
+type NotCondition struct {
+	// ID - Condition's id, useful when there is a need to identify failing Condition.
+	ID string
+	// Condition - nested Condition to negate.
+	Condition Condition
+}
+
+// jsonMarshalableNotCondition - helper type for handling marshaling/unmarshaling
+// of NotCondition's JSON options.
+type jsonMarshalableNotCondition struct {
+	ID        string                    `json:"name,omitempty"`
+	Condition *jsonMarshalableCondition `json:"condition"`
+}
+
+// yamlMarshalableNotCondition - helper type for handling marshaling/unmarshaling
+// of NotCondition's YAML options.
+type yamlMarshalableNotCondition struct {
+	ID        string                    `yaml:"name,omitempty"`
+	Condition *yamlMarshalableCondition `yaml:"condition"`
+}
+
+// Type - returns Condition's type.
+func (c *NotCondition) Type() string {
+	return NotConditionType
+}
+
+// Copy - returns a deep copy of the Condition.
+func (c *NotCondition) Copy() Condition {
+	if c == nil {
+		return c
+	}
+
+	result := *c
+	result.Condition = copyCondition(c.Condition)
+
+	return &result
+}
+
+// Check - returns true if nested Condition is not satisfied, false otherwise.
+func (c *NotCondition) Check(request *AccessRequest) error {
+	if c.Condition == nil {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("no Condition to negate"))
+	}
+
+	err := checkCondition(c.Condition, request)
+	if err == nil {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("Condition: \"%v\" was satisfied", c.Condition.Type()))
+	}
+
+	if _, ok := err.(*ConditionNotSatisfiedError); ok {
+		return nil
+	}
+
+	return err
+}
+
+// Prepare - prepares nested Condition.
+func (c *NotCondition) Prepare() error {
+	return Conditions{c.Condition}.prepare()
+}
+
+// MarshalJSON - marshals NotCondition with its nested Condition.
+func (c *NotCondition) MarshalJSON() ([]byte, error) {
+	result := &jsonMarshalableNotCondition{
+		ID: c.ID,
+	}
+
+	if c.Condition != nil {
+		condition, err := newJSONMarshalableCondition(c.Condition)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Condition = condition
+	}
+
+	return json.Marshal(result)
+}
+
+// MarshalYAML - marshals NotCondition with its nested Condition.
+func (c *NotCondition) MarshalYAML() (interface{}, error) {
+	result := &yamlMarshalableNotCondition{
+		ID: c.ID,
+	}
+
+	if c.Condition != nil {
+		condition, err := newYAMLMarshalableCondition(c.Condition)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Condition = condition
+	}
+
+	return result, nil
+}
+
+// UnmarshalJSON - unmarshals NotCondition with its nested Condition.
+func (c *NotCondition) UnmarshalJSON(jsonData []byte) error {
+	var jsonValue jsonMarshalableNotCondition
+
+	if err := json.Unmarshal(jsonData, &jsonValue); err != nil {
+		return err
+	}
+
+	c.ID = jsonValue.ID
+
+	if jsonValue.Condition == nil {
+		return nil
+	}
+
+	condition, err := jsonValue.Condition.toCondition()
+	if err != nil {
+		return err
+	}
+
+	c.Condition = condition
+
+	return nil
+}
+
+// UnmarshalYAML - unmarshals NotCondition with its nested Condition.
+func (c *NotCondition) UnmarshalYAML(value *yaml.Node) error {
+	var yamlValue yamlMarshalableNotCondition
+
+	if err := value.Decode(&yamlValue); err != nil {
+		return err
+	}
+
+	c.ID = yamlValue.ID
+
+	if yamlValue.Condition == nil || yamlValue.Condition.Type == "" {
+		return nil
+	}
+
+	condition, err := yamlValue.Condition.toCondition()
+	if err != nil {
+		return err
+	}
+
+	c.Condition = condition
+
+	return nil
+}
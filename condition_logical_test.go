@@ -0,0 +1,239 @@
+package restrict
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+type logicalConditionSuite struct {
+	suite.Suite
+
+	testError error
+}
+
+func (s *logicalConditionSuite) SetupSuite() {
+	s.testError = errors.New("testError")
+}
+
+func TestLogicalConditionSuite(t *testing.T) {
+	suite.Run(t, new(logicalConditionSuite))
+}
+
+func (s *logicalConditionSuite) getConditions(request *AccessRequest) (*conditionMock, *conditionMock) {
+	workingCondition := new(conditionMock)
+	workingCondition.On("Check", mock.Anything).Return(nil)
+	workingCondition.On("Type").Return(nil)
+
+	failingCondition := new(conditionMock)
+	failingCondition.On("Check", mock.Anything).Return(NewConditionNotSatisfiedError(failingCondition, request, s.testError))
+
+	return workingCondition, failingCondition
+}
+
+func (s *logicalConditionSuite) TestType() {
+	assert.Equal(s.T(), AndConditionType, (&AndCondition{}).Type())
+	assert.Equal(s.T(), OrConditionType, (&OrCondition{}).Type())
+	assert.Equal(s.T(), NotConditionType, (&NotCondition{}).Type())
+}
+
+func (s *logicalConditionSuite) TestCheck_And() {
+	testRequest := &AccessRequest{}
+	workingCondition, failingCondition := s.getConditions(testRequest)
+
+	testCondition := &AndCondition{}
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	testCondition.Conditions = Conditions{workingCondition, workingCondition}
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	testCondition.Conditions = Conditions{workingCondition, failingCondition, failingCondition}
+
+	err := testCondition.Check(testRequest)
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), err)
+
+	// Fail-early - only first nested error is collected.
+	reason := err.(*ConditionNotSatisfiedError).Reason
+
+	assert.IsType(s.T(), ConditionErrors{}, reason)
+	assert.Equal(s.T(), 1, len(reason.(ConditionErrors)))
+
+	// Complete validation - all nested errors are collected.
+	testRequest.CompleteValidation = true
+
+	err = testCondition.Check(testRequest)
+	reason = err.(*ConditionNotSatisfiedError).Reason
+
+	assert.Equal(s.T(), 2, len(reason.(ConditionErrors)))
+
+	// Unknown errors are returned directly.
+	unknownCondition := new(conditionMock)
+	unknownCondition.On("Check", mock.Anything).Return(s.testError)
+
+	testCondition.Conditions = Conditions{unknownCondition}
+
+	assert.Equal(s.T(), s.testError, testCondition.Check(testRequest))
+}
+
+func (s *logicalConditionSuite) TestCheck_Or() {
+	testRequest := &AccessRequest{}
+	workingCondition, failingCondition := s.getConditions(testRequest)
+
+	testCondition := &OrCondition{}
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+
+	testCondition.Conditions = Conditions{failingCondition, workingCondition}
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	testCondition.Conditions = Conditions{failingCondition, failingCondition}
+
+	err := testCondition.Check(testRequest)
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), err)
+	assert.Equal(s.T(), 2, len(err.(*ConditionNotSatisfiedError).Reason.(ConditionErrors)))
+
+	unknownCondition := new(conditionMock)
+	unknownCondition.On("Check", mock.Anything).Return(s.testError)
+
+	testCondition.Conditions = Conditions{failingCondition, unknownCondition, workingCondition}
+
+	assert.Equal(s.T(), s.testError, testCondition.Check(testRequest))
+}
+
+func (s *logicalConditionSuite) TestCheck_Not() {
+	testRequest := &AccessRequest{}
+	workingCondition, failingCondition := s.getConditions(testRequest)
+
+	testCondition := &NotCondition{}
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+
+	testCondition.Condition = failingCondition
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	testCondition.Condition = workingCondition
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+
+	unknownCondition := new(conditionMock)
+	unknownCondition.On("Check", mock.Anything).Return(s.testError)
+
+	testCondition.Condition = unknownCondition
+
+	assert.Equal(s.T(), s.testError, testCondition.Check(testRequest))
+}
+
+func (s *logicalConditionSuite) TestCheck_Nested() {
+	testSubject := new(subjectMock)
+	testSubject.ID = "user-one"
+
+	testResource := new(resourceMock)
+	testResource.CreatedBy = "user-two"
+
+	testRequest := &AccessRequest{
+		Subject:  testSubject,
+		Resource: testResource,
+		Context: Context{
+			"Admins":   []string{"user-one"},
+			"Archived": false,
+		},
+	}
+
+	// (owner OR admin) AND NOT archived
+	testCondition := getNestedLogicalCondition()
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	testRequest.Context["Archived"] = true
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+
+	testRequest.Context["Archived"] = false
+	testRequest.Context["Admins"] = []string{}
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+
+	testResource.CreatedBy = "user-one"
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+}
+
+func (s *logicalConditionSuite) TestMarshaling() {
+	testConditions := Conditions{getNestedLogicalCondition()}
+
+	conditionsJSON, err := json.Marshal(testConditions)
+	assert.Nil(s.T(), err)
+
+	jsonConditions := Conditions{}
+	err = json.Unmarshal(conditionsJSON, &jsonConditions)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), testConditions, jsonConditions)
+
+	conditionsYAML, err := yaml.Marshal(testConditions)
+	assert.Nil(s.T(), err)
+
+	yamlConditions := Conditions{}
+	err = yaml.Unmarshal(conditionsYAML, &yamlConditions)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), testConditions, yamlConditions)
+
+	// Unknown nested Condition type.
+	invalidData := []byte(`[{"type": "NOT", "options": {"condition": {"type": "UNKNOWN"}}}]`)
+
+	err = json.Unmarshal(invalidData, &Conditions{})
+
+	assert.IsType(s.T(), new(ConditionFactoryNotFoundError), err)
+
+	invalidData = []byte(`
+- type: AND
+  options:
+    conditions:
+      - type: UNKNOWN
+`)
+
+	err = yaml.Unmarshal(invalidData, &Conditions{})
+
+	assert.IsType(s.T(), new(ConditionFactoryNotFoundError), err)
+}
+
+func getNestedLogicalCondition() *AndCondition {
+	return &AndCondition{
+		ID: "ownerOrAdminNotArchived",
+		Conditions: Conditions{
+			&OrCondition{
+				Conditions: Conditions{
+					&EqualCondition{
+						ID:    "isOwner",
+						Left:  &ValueDescriptor{Source: ResourceField, Field: "CreatedBy"},
+						Right: &ValueDescriptor{Source: SubjectField, Field: "ID"},
+					},
+					&InCondition{
+						ID:    "isAdmin",
+						Left:  &ValueDescriptor{Source: SubjectField, Field: "ID"},
+						Right: &ValueDescriptor{Source: ContextField, Field: "Admins"},
+					},
+				},
+			},
+			&NotCondition{
+				ID: "notArchived",
+				Condition: &EqualCondition{
+					Left:  &ValueDescriptor{Source: ContextField, Field: "Archived"},
+					Right: &ValueDescriptor{Source: Explicit, Value: true},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,173 @@
+package restrict
+
+import (
+	"fmt"
+
+	"github.com/el-mike/restrict/v2/internal/utils"
+)
+
+const (
+	// InConditionType - InCondition's type identifier.
+	InConditionType = "IN"
+	// NotInConditionType - NotInCondition's type identifier.
+	NotInConditionType = "NOT_IN"
+	// ContainsConditionType - ContainsCondition's type identifier.
+	ContainsConditionType = "CONTAINS"
+	// IntersectsConditionType - IntersectsCondition's type identifier.
+	IntersectsConditionType = "INTERSECTS"
+)
+
+// baseMembershipCondition - describes fields needed by collection membership Conditions.
+type baseMembershipCondition struct {
+	// ID - Condition's id, useful when there is a need to identify failing Condition.
+	ID string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Left - ValueDescriptor for left operand of membership check.
+	Left *ValueDescriptor `json:"left" yaml:"left"`
+	// Right - ValueDescriptor for right operand of membership check.
+	Right *ValueDescriptor `json:"right" yaml:"right"`
+}
+
+// copy - returns a deep copy of the baseMembershipCondition.
+func (c *baseMembershipCondition) copy() *baseMembershipCondition {
+	if c == nil {
+		return nil
+	}
+
+	result := *c
+	result.Left = c.Left.copy()
+	result.Right = c.Right.copy()
+
+	return &result
+}
+
+// InCondition - checks whether given value (Left) is an element of some collection (Right).
+// Collection can be a slice, an array or a map - in case of a map, its keys are checked.
+type InCondition baseMembershipCondition
+
+// Type - returns Condition's type.
+func (c *InCondition) Type() string {
+	return InConditionType
+}
+
+// Copy - returns a deep copy of the Condition.
+func (c *InCondition) Copy() Condition {
+	return (*InCondition)((*baseMembershipCondition)(c).copy())
+}
+
+// Check - returns true if Left is an element of Right, false otherwise.
+func (c *InCondition) Check(request *AccessRequest) error {
+	left, right, err := unpackDescriptors(c.Left, c.Right, request)
+	if err != nil {
+		return err
+	}
+
+	if !utils.IsCollection(right) {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("value \"%v\" is not a collection", right))
+	}
+
+	if !utils.CollectionContains(right, left) {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("value \"%v\" is not an element of \"%v\"", left, right))
+	}
+
+	return nil
+}
+
+// NotInCondition - checks whether given value (Left) is not an element of some collection (Right).
+// Collection can be a slice, an array or a map - in case of a map, its keys are checked.
+type NotInCondition baseMembershipCondition
+
+// Type - returns Condition's type.
+func (c *NotInCondition) Type() string {
+	return NotInConditionType
+}
+
+// Copy - returns a deep copy of the Condition.
+func (c *NotInCondition) Copy() Condition {
+	return (*NotInCondition)((*baseMembershipCondition)(c).copy())
+}
+
+// Check - returns true if Left is not an element of Right, false otherwise.
+func (c *NotInCondition) Check(request *AccessRequest) error {
+	left, right, err := unpackDescriptors(c.Left, c.Right, request)
+	if err != nil {
+		return err
+	}
+
+	if !utils.IsCollection(right) {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("value \"%v\" is not a collection", right))
+	}
+
+	if utils.CollectionContains(right, left) {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("value \"%v\" is an element of \"%v\"", left, right))
+	}
+
+	return nil
+}
+
+// ContainsCondition - checks whether given collection (Left) contains some value (Right).
+// Collection can be a slice, an array or a map - in case of a map, its keys are checked.
+type ContainsCondition baseMembershipCondition
+
+// Type - returns Condition's type.
+func (c *ContainsCondition) Type() string {
+	return ContainsConditionType
+}
+
+// Copy - returns a deep copy of the Condition.
+func (c *ContainsCondition) Copy() Condition {
+	return (*ContainsCondition)((*baseMembershipCondition)(c).copy())
+}
+
+// Check - returns true if Left contains Right, false otherwise.
+func (c *ContainsCondition) Check(request *AccessRequest) error {
+	left, right, err := unpackDescriptors(c.Left, c.Right, request)
+	if err != nil {
+		return err
+	}
+
+	if !utils.IsCollection(left) {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("value \"%v\" is not a collection", left))
+	}
+
+	if !utils.CollectionContains(left, right) {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("collection \"%v\" does not contain \"%v\"", left, right))
+	}
+
+	return nil
+}
+
+// IntersectsCondition - checks whether two collections (Left and Right) have at least one
+// common element. Collections can be slices, arrays or maps - in case of a map, its keys are checked.
+type IntersectsCondition baseMembershipCondition
+
+// Type - returns Condition's type.
+func (c *IntersectsCondition) Type() string {
+	return IntersectsConditionType
+}
+
+// Copy - returns a deep copy of the Condition.
+func (c *IntersectsCondition) Copy() Condition {
+	return (*IntersectsCondition)((*baseMembershipCondition)(c).copy())
+}
+
+// Check - returns true if Left and Right have a common element, false otherwise.
+func (c *IntersectsCondition) Check(request *AccessRequest) error {
+	left, right, err := unpackDescriptors(c.Left, c.Right, request)
+	if err != nil {
+		return err
+	}
+
+	if !utils.IsCollection(left) {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("value \"%v\" is not a collection", left))
+	}
+
+	if !utils.IsCollection(right) {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("value \"%v\" is not a collection", right))
+	}
+
+	if !utils.CollectionsIntersect(left, right) {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("collections \"%v\" and \"%v\" have no common elements", left, right))
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package restrict
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type membershipConditionSuite struct {
+	suite.Suite
+}
+
+func TestMembershipConditionSuite(t *testing.T) {
+	suite.Run(t, new(membershipConditionSuite))
+}
+
+func (s *membershipConditionSuite) TestType() {
+	assert.Equal(s.T(), InConditionType, (&InCondition{}).Type())
+	assert.Equal(s.T(), NotInConditionType, (&NotInCondition{}).Type())
+	assert.Equal(s.T(), ContainsConditionType, (&ContainsCondition{}).Type())
+	assert.Equal(s.T(), IntersectsConditionType, (&IntersectsCondition{}).Type())
+}
+
+func (s *membershipConditionSuite) TestCheck_In() {
+	testSubject := new(subjectMock)
+	testSubject.ID = "user-one"
+
+	testResource := new(resourceMock)
+	testResource.FieldThree = []int{1, 2}
+
+	testRequest := &AccessRequest{
+		Subject:  testSubject,
+		Resource: testResource,
+		Context: Context{
+			"Participants": []string{"user-one", "user-two"},
+			"Owners":       map[string]bool{"user-three": true},
+		},
+	}
+
+	testCondition := &InCondition{
+		Left:  &ValueDescriptor{Source: SubjectField, Field: "ID"},
+		Right: &ValueDescriptor{Source: ContextField, Field: "Participants"},
+	}
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	// Map keys are treated as elements.
+	testCondition.Right = &ValueDescriptor{Source: ContextField, Field: "Owners"}
+
+	err := testCondition.Check(testRequest)
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), err)
+	assert.Contains(s.T(), err.Error(), "is not an element of")
+
+	testSubject.ID = "user-three"
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	// Right is not a collection.
+	testCondition.Right = &ValueDescriptor{Source: SubjectField, Field: "ID"}
+
+	err = testCondition.Check(testRequest)
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), err)
+	assert.Contains(s.T(), err.Error(), "is not a collection")
+
+	// Malformed descriptor.
+	testCondition.Right = &ValueDescriptor{Source: ContextField}
+
+	assert.IsType(s.T(), new(ValueDescriptorMalformedError), testCondition.Check(testRequest))
+}
+
+func (s *membershipConditionSuite) TestCheck_NotIn() {
+	testSubject := new(subjectMock)
+	testSubject.ID = "user-one"
+
+	testRequest := &AccessRequest{
+		Subject: testSubject,
+		Context: Context{
+			"Banned": [2]string{"user-two", "user-three"},
+		},
+	}
+
+	testCondition := &NotInCondition{
+		Left:  &ValueDescriptor{Source: SubjectField, Field: "ID"},
+		Right: &ValueDescriptor{Source: ContextField, Field: "Banned"},
+	}
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	testSubject.ID = "user-two"
+
+	err := testCondition.Check(testRequest)
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), err)
+	assert.Contains(s.T(), err.Error(), "is an element of")
+
+	testCondition.Right = &ValueDescriptor{Source: Explicit, Value: "user-two"}
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+}
+
+func (s *membershipConditionSuite) TestCheck_Contains() {
+	testResource := new(resourceMock)
+	testResource.FieldThree = []int{1, 2}
+
+	testRequest := &AccessRequest{
+		Resource: testResource,
+	}
+
+	testCondition := &ContainsCondition{
+		Left:  &ValueDescriptor{Source: ResourceField, Field: "FieldThree"},
+		Right: &ValueDescriptor{Source: Explicit, Value: 2},
+	}
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	testCondition.Right.Value = 3
+
+	err := testCondition.Check(testRequest)
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), err)
+	assert.Contains(s.T(), err.Error(), "does not contain")
+
+	// Same DeepEqual semantics as EqualCondition - different types are not equal.
+	testCondition.Right.Value = int64(2)
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+
+	testCondition.Left = &ValueDescriptor{Source: Explicit, Value: 1}
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+}
+
+func (s *membershipConditionSuite) TestCheck_Intersects() {
+	testRequest := &AccessRequest{
+		Context: Context{
+			"Groups":   []string{"one", "two"},
+			"Required": map[string]int{"two": 1, "three": 2},
+			"Other":    []string{"four"},
+		},
+	}
+
+	testCondition := &IntersectsCondition{
+		Left:  &ValueDescriptor{Source: ContextField, Field: "Groups"},
+		Right: &ValueDescriptor{Source: ContextField, Field: "Required"},
+	}
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	testCondition.Right = &ValueDescriptor{Source: ContextField, Field: "Other"}
+
+	err := testCondition.Check(testRequest)
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), err)
+	assert.Contains(s.T(), err.Error(), "have no common elements")
+
+	testCondition.Right = &ValueDescriptor{Source: Explicit, Value: "one"}
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+
+	testCondition.Left = &ValueDescriptor{Source: Explicit, Value: "one"}
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+}
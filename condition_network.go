@@ -0,0 +1,250 @@
+package restrict
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"reflect"
+
+	"github.com/el-mike/restrict/v2/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// IPInCIDRConditionType - IPInCIDRCondition's type identifier.
+	IPInCIDRConditionType = "IP_IN_CIDR"
+	// IPNotInCIDRConditionType - IPNotInCIDRCondition's type identifier.
+	IPNotInCIDRConditionType = "IP_NOT_IN_CIDR"
+)
+
+// baseNetworkCondition - describes fields needed by network Conditions.
+type baseNetworkCondition struct {
+	// ID - Condition's id, useful when there is a need to identify failing Condition.
+	ID string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Left - ValueDescriptor for the IP address.
+	Left *ValueDescriptor `json:"left" yaml:"left"`
+	// Right - ValueDescriptor for a CIDR block or a list of CIDR blocks.
+	Right *ValueDescriptor `json:"right" yaml:"right"`
+
+	// Networks parsed when the Condition is prepared. Only explicit networks are
+	// parsed ahead of time - networks taken from other sources are parsed on every check.
+	parsed *parsedNetworks
+}
+
+// copy - returns a deep copy of the baseNetworkCondition.
+// Parsed networks are never modified, so they can be shared.
+func (c *baseNetworkCondition) copy() *baseNetworkCondition {
+	if c == nil {
+		return nil
+	}
+
+	result := *c
+	result.Left = c.Left.copy()
+	result.Right = c.Right.copy()
+
+	return &result
+}
+
+// parsedNetworks - networks parsed from given value.
+type parsedNetworks struct {
+	value    interface{}
+	networks []*net.IPNet
+}
+
+// IPInCIDRCondition - checks whether given IP address (Left) belongs to any of the networks (Right).
+// Networks can be a single CIDR block or a list of them, both IPv4 and IPv6 are supported.
+type IPInCIDRCondition baseNetworkCondition
+
+// ipInCIDRConditionOptions - helper type for unmarshaling IPInCIDRCondition
+// without recursion.
+type ipInCIDRConditionOptions IPInCIDRCondition
+
+// Type - returns Condition's type.
+func (c *IPInCIDRCondition) Type() string {
+	return IPInCIDRConditionType
+}
+
+// Copy - returns a deep copy of the Condition.
+func (c *IPInCIDRCondition) Copy() Condition {
+	return (*IPInCIDRCondition)((*baseNetworkCondition)(c).copy())
+}
+
+// Prepare - parses explicit networks, returns ConditionMalformedError if they're not valid.
+func (c *IPInCIDRCondition) Prepare() error {
+	return (*baseNetworkCondition)(c).prepare(c)
+}
+
+// Check - returns true if Left belongs to any of the networks, false otherwise.
+func (c *IPInCIDRCondition) Check(request *AccessRequest) error {
+	ip, networks, err := unpackNetworkDescriptors(c, (*baseNetworkCondition)(c), request)
+	if err != nil {
+		return err
+	}
+
+	if findNetwork(ip, networks) == nil {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("address \"%s\" does not belong to any of the networks", ip))
+	}
+
+	return nil
+}
+
+// UnmarshalJSON - unmarshals IPInCIDRCondition and parses its networks.
+func (c *IPInCIDRCondition) UnmarshalJSON(jsonData []byte) error {
+	if err := json.Unmarshal(jsonData, (*ipInCIDRConditionOptions)(c)); err != nil {
+		return err
+	}
+
+	return c.Prepare()
+}
+
+// UnmarshalYAML - unmarshals IPInCIDRCondition and parses its networks.
+func (c *IPInCIDRCondition) UnmarshalYAML(value *yaml.Node) error {
+	if err := value.Decode((*ipInCIDRConditionOptions)(c)); err != nil {
+		return err
+	}
+
+	return c.Prepare()
+}
+
+// IPNotInCIDRCondition - checks whether given IP address (Left) does not belong to any of the networks (Right).
+// Networks can be a single CIDR block or a list of them, both IPv4 and IPv6 are supported.
+type IPNotInCIDRCondition baseNetworkCondition
+
+// ipNotInCIDRConditionOptions - helper type for unmarshaling IPNotInCIDRCondition
+// without recursion.
+type ipNotInCIDRConditionOptions IPNotInCIDRCondition
+
+// Type - returns Condition's type.
+func (c *IPNotInCIDRCondition) Type() string {
+	return IPNotInCIDRConditionType
+}
+
+// Copy - returns a deep copy of the Condition.
+func (c *IPNotInCIDRCondition) Copy() Condition {
+	return (*IPNotInCIDRCondition)((*baseNetworkCondition)(c).copy())
+}
+
+// Prepare - parses explicit networks, returns ConditionMalformedError if they're not valid.
+func (c *IPNotInCIDRCondition) Prepare() error {
+	return (*baseNetworkCondition)(c).prepare(c)
+}
+
+// Check - returns true if Left does not belong to any of the networks, false otherwise.
+func (c *IPNotInCIDRCondition) Check(request *AccessRequest) error {
+	ip, networks, err := unpackNetworkDescriptors(c, (*baseNetworkCondition)(c), request)
+	if err != nil {
+		return err
+	}
+
+	if network := findNetwork(ip, networks); network != nil {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("address \"%s\" belongs to network \"%s\"", ip, network))
+	}
+
+	return nil
+}
+
+// UnmarshalJSON - unmarshals IPNotInCIDRCondition and parses its networks.
+func (c *IPNotInCIDRCondition) UnmarshalJSON(jsonData []byte) error {
+	if err := json.Unmarshal(jsonData, (*ipNotInCIDRConditionOptions)(c)); err != nil {
+		return err
+	}
+
+	return c.Prepare()
+}
+
+// UnmarshalYAML - unmarshals IPNotInCIDRCondition and parses its networks.
+func (c *IPNotInCIDRCondition) UnmarshalYAML(value *yaml.Node) error {
+	if err := value.Decode((*ipNotInCIDRConditionOptions)(c)); err != nil {
+		return err
+	}
+
+	return c.Prepare()
+}
+
+// prepare - parses explicit networks of the Condition, unless they're already up to date or there is
+// nothing to parse - in both cases the Condition is not modified (see PreparableCondition).
+func (c *baseNetworkCondition) prepare(condition Condition) error {
+	if isNetworksParsed(c.parsed, c.Right) {
+		return nil
+	}
+
+	parsed, err := prepareNetworks(condition, c.Right)
+	if err != nil {
+		return err
+	}
+
+	if parsed != nil || c.parsed != nil {
+		c.parsed = parsed
+	}
+
+	return nil
+}
+
+// isNetworksParsed - returns true if given parsed networks are up to date with explicit networks
+// described by given descriptor.
+func isNetworksParsed(parsed *parsedNetworks, descriptor *ValueDescriptor) bool {
+	return parsed != nil &&
+		descriptor != nil &&
+		descriptor.Source == Explicit &&
+		reflect.DeepEqual(parsed.value, descriptor.Value)
+}
+
+// prepareNetworks - parses the networks if they're defined explicitly. Returns nil if networks
+// come from other source, or ConditionMalformedError if they're not valid.
+func prepareNetworks(condition Condition, descriptor *ValueDescriptor) (*parsedNetworks, error) {
+	if descriptor == nil || descriptor.Source != Explicit {
+		return nil, nil
+	}
+
+	networks, err := utils.GetNetworks(descriptor.Value)
+	if err != nil {
+		return nil, newConditionMalformedError(condition, err)
+	}
+
+	return &parsedNetworks{
+		value:    descriptor.Value,
+		networks: networks,
+	}, nil
+}
+
+// unpackNetworkDescriptors - helper function for unpacking ValueDescriptors' values as an IP address
+// and a list of networks. Uses networks parsed ahead of time when possible.
+func unpackNetworkDescriptors(condition Condition, base *baseNetworkCondition, request *AccessRequest) (net.IP, []*net.IPNet, error) {
+	left, right, err := unpackDescriptors(base.Left, base.Right, request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ip, ok := utils.GetIP(left)
+	if !ok {
+		return nil, nil, NewConditionNotSatisfiedError(condition, request, fmt.Errorf("value \"%v\" is not a valid IP address", left))
+	}
+
+	if base.parsed != nil && reflect.DeepEqual(base.parsed.value, right) {
+		return ip, base.parsed.networks, nil
+	}
+
+	networks, err := utils.GetNetworks(right)
+	if err != nil {
+		// Explicit networks are a part of the policy, therefore the Condition itself is malformed.
+		// Otherwise, the networks come from the request, and the Condition is just not satisfied.
+		if base.Right.Source == Explicit {
+			return nil, nil, newConditionMalformedError(condition, err)
+		}
+
+		return nil, nil, NewConditionNotSatisfiedError(condition, request, err)
+	}
+
+	return ip, networks, nil
+}
+
+// findNetwork - returns the first network containing given IP address, or nil if there is none.
+func findNetwork(ip net.IP, networks []*net.IPNet) *net.IPNet {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return network
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package restrict
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+type networkConditionSuite struct {
+	suite.Suite
+}
+
+func TestNetworkConditionSuite(t *testing.T) {
+	suite.Run(t, new(networkConditionSuite))
+}
+
+func (s *networkConditionSuite) TestType() {
+	assert.Equal(s.T(), IPInCIDRConditionType, (&IPInCIDRCondition{}).Type())
+	assert.Equal(s.T(), IPNotInCIDRConditionType, (&IPNotInCIDRCondition{}).Type())
+}
+
+func (s *networkConditionSuite) TestCheck_IPInCIDR() {
+	testRequest := &AccessRequest{
+		Context: Context{
+			"IP":       "10.1.2.3",
+			"Networks": []string{"192.168.0.0/16"},
+		},
+	}
+
+	testCondition := &IPInCIDRCondition{
+		Left:  &ValueDescriptor{Source: ContextField, Field: "IP"},
+		Right: &ValueDescriptor{Source: Explicit, Value: []interface{}{"10.0.0.0/8", "2001:db8::/32"}},
+	}
+
+	assert.Nil(s.T(), testCondition.Prepare())
+	assert.NotNil(s.T(), testCondition.parsed)
+
+	// Already prepared Condition is not modified.
+	parsed := testCondition.parsed
+
+	assert.Nil(s.T(), testCondition.Prepare())
+	assert.Same(s.T(), parsed, testCondition.parsed)
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	testRequest.Context["IP"] = "2001:db8:1::1"
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	testRequest.Context["IP"] = net.ParseIP("2001:db9::1")
+
+	err := testCondition.Check(testRequest)
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), err)
+	assert.Contains(s.T(), err.Error(), "does not belong to any of the networks")
+
+	// IPv4-mapped IPv6 address.
+	testRequest.Context["IP"] = "::ffff:10.0.0.1"
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	// Single network.
+	testCondition.Right = &ValueDescriptor{Source: Explicit, Value: "10.0.0.0/24"}
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	// Networks taken from the request.
+	testCondition.Right = &ValueDescriptor{Source: ContextField, Field: "Networks"}
+
+	assert.Nil(s.T(), testCondition.Prepare())
+	assert.Nil(s.T(), testCondition.parsed)
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+
+	testRequest.Context["IP"] = "192.168.1.1"
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	// Invalid networks taken from the request.
+	testRequest.Context["Networks"] = []string{"192.168.0.0/33"}
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+
+	// Invalid explicit networks.
+	testCondition.Right = &ValueDescriptor{Source: Explicit, Value: []string{"192.168.0.0/33"}}
+
+	assert.IsType(s.T(), new(ConditionMalformedError), testCondition.Prepare())
+	assert.IsType(s.T(), new(ConditionMalformedError), testCondition.Check(testRequest))
+
+	// Invalid address.
+	testCondition.Right = &ValueDescriptor{Source: Explicit, Value: "10.0.0.0/8"}
+	testRequest.Context["IP"] = "10.0.0"
+
+	err = testCondition.Check(testRequest)
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), err)
+	assert.Contains(s.T(), err.Error(), "is not a valid IP address")
+
+	// Malformed descriptor.
+	testCondition.Left = &ValueDescriptor{Source: ContextField}
+
+	assert.IsType(s.T(), new(ValueDescriptorMalformedError), testCondition.Check(testRequest))
+}
+
+func (s *networkConditionSuite) TestCheck_IPNotInCIDR() {
+	testRequest := &AccessRequest{
+		Context: Context{
+			"IP": "10.1.2.3",
+		},
+	}
+
+	testCondition := &IPNotInCIDRCondition{
+		Left:  &ValueDescriptor{Source: ContextField, Field: "IP"},
+		Right: &ValueDescriptor{Source: Explicit, Value: []string{"192.168.0.0/16", "::1"}},
+	}
+
+	assert.Nil(s.T(), testCondition.Prepare())
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	testRequest.Context["IP"] = "::1"
+
+	err := testCondition.Check(testRequest)
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), err)
+	assert.Contains(s.T(), err.Error(), "belongs to network \"::1/128\"")
+
+	testRequest.Context["IP"] = "192.168.10.1"
+
+	err = testCondition.Check(testRequest)
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), err)
+	assert.Contains(s.T(), err.Error(), "belongs to network \"192.168.0.0/16\"")
+}
+
+func (s *networkConditionSuite) TestMarshaling() {
+	testConditions := Conditions{
+		&IPInCIDRCondition{
+			ID:    "officeNetwork",
+			Left:  &ValueDescriptor{Source: ContextField, Field: "IP"},
+			Right: &ValueDescriptor{Source: Explicit, Value: []interface{}{"10.0.0.0/8", "2001:db8::/32"}},
+		},
+		&IPNotInCIDRCondition{
+			Left:  &ValueDescriptor{Source: ContextField, Field: "IP"},
+			Right: &ValueDescriptor{Source: SubjectField, Field: "BlockedNetworks"},
+		},
+	}
+
+	conditionsJSON, err := json.Marshal(testConditions)
+	assert.Nil(s.T(), err)
+
+	jsonConditions := Conditions{}
+	err = json.Unmarshal(conditionsJSON, &jsonConditions)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), testConditions[0].(*IPInCIDRCondition).Right, jsonConditions[0].(*IPInCIDRCondition).Right)
+	assert.Len(s.T(), jsonConditions[0].(*IPInCIDRCondition).parsed.networks, 2)
+	assert.Equal(s.T(), testConditions[1], jsonConditions[1])
+
+	conditionsYAML, err := yaml.Marshal(testConditions)
+	assert.Nil(s.T(), err)
+
+	yamlConditions := Conditions{}
+	err = yaml.Unmarshal(conditionsYAML, &yamlConditions)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), testConditions[0].(*IPInCIDRCondition).Right, yamlConditions[0].(*IPInCIDRCondition).Right)
+	assert.Len(s.T(), yamlConditions[0].(*IPInCIDRCondition).parsed.networks, 2)
+	assert.Equal(s.T(), testConditions[1], yamlConditions[1])
+
+	// Invalid network.
+	err = json.Unmarshal([]byte(`[{"type": "IP_IN_CIDR", "options": {"left": {"source": "ContextField", "field": "IP"}, "right": {"source": "Explicit", "value": ["10.0.0.0/40"]}}}]`), &Conditions{})
+
+	assert.IsType(s.T(), new(ConditionMalformedError), err)
+
+	err = yaml.Unmarshal([]byte("- type: IP_NOT_IN_CIDR\n  options:\n    left:\n      source: ContextField\n      field: IP\n    right:\n      source: Explicit\n      value: office\n"), &Conditions{})
+
+	assert.IsType(s.T(), new(ConditionMalformedError), err)
+}
@@ -0,0 +1,371 @@
+package restrict
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/el-mike/restrict/v2/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// MatchesConditionType - MatchesCondition's type identifier.
+	MatchesConditionType = "MATCHES"
+	// GlobConditionType - GlobCondition's type identifier.
+	GlobConditionType = "GLOB"
+	// StartsWithConditionType - StartsWithCondition's type identifier.
+	StartsWithConditionType = "STARTS_WITH"
+	// EndsWithConditionType - EndsWithCondition's type identifier.
+	EndsWithConditionType = "ENDS_WITH"
+)
+
+// baseStringCondition - describes fields needed by StartsWith/EndsWith Conditions.
+type baseStringCondition struct {
+	// ID - Condition's id, useful when there is a need to identify failing Condition.
+	ID string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Left - ValueDescriptor for the string being checked.
+	Left *ValueDescriptor `json:"left" yaml:"left"`
+	// Right - ValueDescriptor for the prefix or suffix.
+	Right *ValueDescriptor `json:"right" yaml:"right"`
+	// CaseInsensitive - when true, letter case is ignored.
+	CaseInsensitive bool `json:"caseInsensitive,omitempty" yaml:"caseInsensitive,omitempty"`
+}
+
+// copy - returns a deep copy of the baseStringCondition.
+func (c *baseStringCondition) copy() *baseStringCondition {
+	if c == nil {
+		return nil
+	}
+
+	result := *c
+	result.Left = c.Left.copy()
+	result.Right = c.Right.copy()
+
+	return &result
+}
+
+// basePatternCondition - describes fields needed by Matches/Glob Conditions.
+type basePatternCondition struct {
+	// ID - Condition's id, useful when there is a need to identify failing Condition.
+	ID string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Left - ValueDescriptor for the string being checked.
+	Left *ValueDescriptor `json:"left" yaml:"left"`
+	// Right - ValueDescriptor for the pattern.
+	Right *ValueDescriptor `json:"right" yaml:"right"`
+	// CaseInsensitive - when true, letter case is ignored.
+	CaseInsensitive bool `json:"caseInsensitive,omitempty" yaml:"caseInsensitive,omitempty"`
+
+	// Pattern compiled when the Condition is prepared. Only explicit patterns are
+	// compiled ahead of time - patterns taken from other sources are compiled on every check.
+	compiled *compiledPattern
+}
+
+// copy - returns a deep copy of the basePatternCondition.
+// Compiled pattern is never modified, so it can be shared.
+func (c *basePatternCondition) copy() *basePatternCondition {
+	if c == nil {
+		return nil
+	}
+
+	result := *c
+	result.Left = c.Left.copy()
+	result.Right = c.Right.copy()
+
+	return &result
+}
+
+// compiledPattern - regular expression compiled from given pattern.
+type compiledPattern struct {
+	pattern         string
+	caseInsensitive bool
+	regexp          *regexp.Regexp
+}
+
+// MatchesCondition - checks whether given string (Left) matches a regular expression (Right).
+// Regular expression uses RE2 syntax, and is not anchored by default.
+type MatchesCondition basePatternCondition
+
+// matchesConditionOptions - helper type for unmarshaling MatchesCondition
+// without recursion.
+type matchesConditionOptions MatchesCondition
+
+// Type - returns Condition's type.
+func (c *MatchesCondition) Type() string {
+	return MatchesConditionType
+}
+
+// Copy - returns a deep copy of the Condition.
+func (c *MatchesCondition) Copy() Condition {
+	return (*MatchesCondition)((*basePatternCondition)(c).copy())
+}
+
+// Prepare - compiles explicit pattern, returns ConditionMalformedError if it's not valid.
+func (c *MatchesCondition) Prepare() error {
+	return (*basePatternCondition)(c).prepare(c, translateRegexp)
+}
+
+// Check - returns true if Left matches the regular expression, false otherwise.
+func (c *MatchesCondition) Check(request *AccessRequest) error {
+	return checkPattern(c, (*basePatternCondition)(c), request, translateRegexp)
+}
+
+// UnmarshalJSON - unmarshals MatchesCondition and compiles its pattern.
+func (c *MatchesCondition) UnmarshalJSON(jsonData []byte) error {
+	if err := json.Unmarshal(jsonData, (*matchesConditionOptions)(c)); err != nil {
+		return err
+	}
+
+	return c.Prepare()
+}
+
+// UnmarshalYAML - unmarshals MatchesCondition and compiles its pattern.
+func (c *MatchesCondition) UnmarshalYAML(value *yaml.Node) error {
+	if err := value.Decode((*matchesConditionOptions)(c)); err != nil {
+		return err
+	}
+
+	return c.Prepare()
+}
+
+// GlobCondition - checks whether given string (Left) matches a glob pattern (Right).
+// "**" matches any sequence of characters, "*" matches any sequence of characters except "/",
+// "?" matches any single character except "/", and "[...]" matches a character class.
+type GlobCondition basePatternCondition
+
+// globConditionOptions - helper type for unmarshaling GlobCondition
+// without recursion.
+type globConditionOptions GlobCondition
+
+// Type - returns Condition's type.
+func (c *GlobCondition) Type() string {
+	return GlobConditionType
+}
+
+// Copy - returns a deep copy of the Condition.
+func (c *GlobCondition) Copy() Condition {
+	return (*GlobCondition)((*basePatternCondition)(c).copy())
+}
+
+// Prepare - compiles explicit pattern, returns ConditionMalformedError if it's not valid.
+func (c *GlobCondition) Prepare() error {
+	return (*basePatternCondition)(c).prepare(c, utils.GlobToRegexp)
+}
+
+// Check - returns true if Left matches the glob pattern, false otherwise.
+func (c *GlobCondition) Check(request *AccessRequest) error {
+	return checkPattern(c, (*basePatternCondition)(c), request, utils.GlobToRegexp)
+}
+
+// UnmarshalJSON - unmarshals GlobCondition and compiles its pattern.
+func (c *GlobCondition) UnmarshalJSON(jsonData []byte) error {
+	if err := json.Unmarshal(jsonData, (*globConditionOptions)(c)); err != nil {
+		return err
+	}
+
+	return c.Prepare()
+}
+
+// UnmarshalYAML - unmarshals GlobCondition and compiles its pattern.
+func (c *GlobCondition) UnmarshalYAML(value *yaml.Node) error {
+	if err := value.Decode((*globConditionOptions)(c)); err != nil {
+		return err
+	}
+
+	return c.Prepare()
+}
+
+// StartsWithCondition - checks whether given string (Left) starts with some prefix (Right).
+type StartsWithCondition baseStringCondition
+
+// Type - returns Condition's type.
+func (c *StartsWithCondition) Type() string {
+	return StartsWithConditionType
+}
+
+// Copy - returns a deep copy of the Condition.
+func (c *StartsWithCondition) Copy() Condition {
+	return (*StartsWithCondition)((*baseStringCondition)(c).copy())
+}
+
+// Check - returns true if Left starts with Right, false otherwise.
+func (c *StartsWithCondition) Check(request *AccessRequest) error {
+	value, prefix, err := unpackStringDescriptors(c, (*baseStringCondition)(c), request)
+	if err != nil {
+		return err
+	}
+
+	if !strings.HasPrefix(value, prefix) {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("value \"%v\" does not start with \"%v\"", value, prefix))
+	}
+
+	return nil
+}
+
+// EndsWithCondition - checks whether given string (Left) ends with some suffix (Right).
+type EndsWithCondition baseStringCondition
+
+// Type - returns Condition's type.
+func (c *EndsWithCondition) Type() string {
+	return EndsWithConditionType
+}
+
+// Copy - returns a deep copy of the Condition.
+func (c *EndsWithCondition) Copy() Condition {
+	return (*EndsWithCondition)((*baseStringCondition)(c).copy())
+}
+
+// Check - returns true if Left ends with Right, false otherwise.
+func (c *EndsWithCondition) Check(request *AccessRequest) error {
+	value, suffix, err := unpackStringDescriptors(c, (*baseStringCondition)(c), request)
+	if err != nil {
+		return err
+	}
+
+	if !strings.HasSuffix(value, suffix) {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("value \"%v\" does not end with \"%v\"", value, suffix))
+	}
+
+	return nil
+}
+
+// unpackStringDescriptors - helper function for unpacking ValueDescriptors' values as strings.
+// If CaseInsensitive is set, returned values are lower-cased.
+func unpackStringDescriptors(condition Condition, base *baseStringCondition, request *AccessRequest) (string, string, error) {
+	left, right, err := unpackDescriptors(base.Left, base.Right, request)
+	if err != nil {
+		return "", "", err
+	}
+
+	leftString, ok := utils.GetStringValue(left)
+	if !ok {
+		return "", "", NewConditionNotSatisfiedError(condition, request, fmt.Errorf("value \"%v\" is not a string", left))
+	}
+
+	rightString, ok := utils.GetStringValue(right)
+	if !ok {
+		return "", "", NewConditionNotSatisfiedError(condition, request, fmt.Errorf("value \"%v\" is not a string", right))
+	}
+
+	if base.CaseInsensitive {
+		return strings.ToLower(leftString), strings.ToLower(rightString), nil
+	}
+
+	return leftString, rightString, nil
+}
+
+// translateRegexp - identity translation for patterns that already are regular expressions.
+func translateRegexp(pattern string) string {
+	return pattern
+}
+
+// compilePattern - translates and compiles given pattern.
+func compilePattern(pattern string, caseInsensitive bool, translate func(string) string) (*compiledPattern, error) {
+	expression := translate(pattern)
+
+	if caseInsensitive {
+		expression = "(?i)" + expression
+	}
+
+	compiled, err := regexp.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	return &compiledPattern{
+		pattern:         pattern,
+		caseInsensitive: caseInsensitive,
+		regexp:          compiled,
+	}, nil
+}
+
+// prepare - compiles explicit pattern of the Condition, unless it's already up to date or there is
+// nothing to compile - in both cases the Condition is not modified (see PreparableCondition).
+func (c *basePatternCondition) prepare(condition Condition, translate func(string) string) error {
+	if isPatternCompiled(c.compiled, c.Right, c.CaseInsensitive) {
+		return nil
+	}
+
+	compiled, err := preparePattern(condition, c.Right, c.CaseInsensitive, translate)
+	if err != nil {
+		return err
+	}
+
+	if compiled != nil || c.compiled != nil {
+		c.compiled = compiled
+	}
+
+	return nil
+}
+
+// preparePattern - compiles the pattern if it's defined explicitly. Returns nil if pattern
+// comes from other source, or ConditionMalformedError if it's not a valid pattern.
+func preparePattern(condition Condition, descriptor *ValueDescriptor, caseInsensitive bool, translate func(string) string) (*compiledPattern, error) {
+	if descriptor == nil || descriptor.Source != Explicit {
+		return nil, nil
+	}
+
+	pattern, ok := utils.GetStringValue(descriptor.Value)
+	if !ok {
+		return nil, newConditionMalformedError(condition, fmt.Errorf("pattern \"%v\" is not a string", descriptor.Value))
+	}
+
+	compiled, err := compilePattern(pattern, caseInsensitive, translate)
+	if err != nil {
+		return nil, newConditionMalformedError(condition, err)
+	}
+
+	return compiled, nil
+}
+
+// isPatternCompiled - returns true if given compiled pattern is up to date with explicit pattern
+// described by given descriptor.
+func isPatternCompiled(compiled *compiledPattern, descriptor *ValueDescriptor, caseInsensitive bool) bool {
+	if compiled == nil || descriptor == nil || descriptor.Source != Explicit {
+		return false
+	}
+
+	pattern, ok := utils.GetStringValue(descriptor.Value)
+
+	return ok && compiled.pattern == pattern && compiled.caseInsensitive == caseInsensitive
+}
+
+// checkPattern - helper function for checking whether Left matches the pattern described by Right.
+// Uses pattern compiled ahead of time when possible.
+func checkPattern(condition Condition, base *basePatternCondition, request *AccessRequest, translate func(string) string) error {
+	left, right, err := unpackDescriptors(base.Left, base.Right, request)
+	if err != nil {
+		return err
+	}
+
+	value, ok := utils.GetStringValue(left)
+	if !ok {
+		return NewConditionNotSatisfiedError(condition, request, fmt.Errorf("value \"%v\" is not a string", left))
+	}
+
+	pattern, ok := utils.GetStringValue(right)
+	if !ok {
+		return NewConditionNotSatisfiedError(condition, request, fmt.Errorf("pattern \"%v\" is not a string", right))
+	}
+
+	compiled := base.compiled
+
+	if compiled == nil || compiled.pattern != pattern || compiled.caseInsensitive != base.CaseInsensitive {
+		if compiled, err = compilePattern(pattern, base.CaseInsensitive, translate); err != nil {
+			// Explicit pattern is a part of the policy, therefore the Condition itself is malformed.
+			// Otherwise, the pattern comes from the request, and the Condition is just not satisfied.
+			if base.Right.Source == Explicit {
+				return newConditionMalformedError(condition, err)
+			}
+
+			return NewConditionNotSatisfiedError(condition, request, fmt.Errorf("pattern \"%v\" is not valid: %s", pattern, err.Error()))
+		}
+	}
+
+	if !compiled.regexp.MatchString(value) {
+		return NewConditionNotSatisfiedError(condition, request, fmt.Errorf("value \"%v\" does not match pattern \"%v\"", value, pattern))
+	}
+
+	return nil
+}
@@ -0,0 +1,262 @@
+package restrict
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+type stringConditionSuite struct {
+	suite.Suite
+}
+
+func TestStringConditionSuite(t *testing.T) {
+	suite.Run(t, new(stringConditionSuite))
+}
+
+func (s *stringConditionSuite) TestType() {
+	assert.Equal(s.T(), MatchesConditionType, (&MatchesCondition{}).Type())
+	assert.Equal(s.T(), GlobConditionType, (&GlobCondition{}).Type())
+	assert.Equal(s.T(), StartsWithConditionType, (&StartsWithCondition{}).Type())
+	assert.Equal(s.T(), EndsWithConditionType, (&EndsWithCondition{}).Type())
+}
+
+func (s *stringConditionSuite) TestCheck_Matches() {
+	testSubject := new(subjectMock)
+	testSubject.ID = "user-one"
+	testSubject.FieldTwo = 1
+
+	testRequest := &AccessRequest{
+		Subject: testSubject,
+		Context: Context{
+			"Pattern": "^user-[0-9]+$",
+		},
+	}
+
+	testCondition := &MatchesCondition{
+		Left:  &ValueDescriptor{Source: SubjectField, Field: "ID"},
+		Right: &ValueDescriptor{Source: Explicit, Value: "^user-[a-z]+$"},
+	}
+
+	assert.Nil(s.T(), testCondition.Prepare())
+	assert.NotNil(s.T(), testCondition.compiled)
+
+	// Already prepared Condition is not modified.
+	compiled := testCondition.compiled
+
+	assert.Nil(s.T(), testCondition.Prepare())
+	assert.Same(s.T(), compiled, testCondition.compiled)
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	testSubject.ID = "USER-ONE"
+
+	err := testCondition.Check(testRequest)
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), err)
+	assert.Contains(s.T(), err.Error(), "does not match pattern")
+
+	testCondition.CaseInsensitive = true
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	// Pattern taken from the request.
+	testCondition.Right = &ValueDescriptor{Source: ContextField, Field: "Pattern"}
+
+	assert.Nil(s.T(), testCondition.Prepare())
+	assert.Nil(s.T(), testCondition.compiled)
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+
+	testSubject.ID = "user-1"
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	// Invalid pattern taken from the request.
+	testRequest.Context["Pattern"] = "(user"
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+
+	// Invalid explicit pattern.
+	testCondition.Right = &ValueDescriptor{Source: Explicit, Value: "(user"}
+
+	assert.IsType(s.T(), new(ConditionMalformedError), testCondition.Prepare())
+	assert.IsType(s.T(), new(ConditionMalformedError), testCondition.Check(testRequest))
+
+	// Explicit pattern is not a string.
+	testCondition.Right = &ValueDescriptor{Source: Explicit, Value: 1}
+
+	assert.IsType(s.T(), new(ConditionMalformedError), testCondition.Prepare())
+
+	// Value is not a string.
+	testCondition.Left = &ValueDescriptor{Source: SubjectField, Field: "FieldTwo"}
+	testCondition.Right = &ValueDescriptor{Source: Explicit, Value: "1"}
+
+	err = testCondition.Check(testRequest)
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), err)
+	assert.Contains(s.T(), err.Error(), "is not a string")
+}
+
+func (s *stringConditionSuite) TestCheck_Glob() {
+	testResource := new(resourceMock)
+	testResource.FieldOne = "/projects/restrict/docs/README.md"
+
+	testRequest := &AccessRequest{
+		Resource: testResource,
+	}
+
+	testCondition := &GlobCondition{
+		Left:  &ValueDescriptor{Source: ResourceField, Field: "FieldOne"},
+		Right: &ValueDescriptor{Source: Explicit, Value: "/projects/*/docs/*.md"},
+	}
+
+	assert.Nil(s.T(), testCondition.Prepare())
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	testResource.FieldOne = "/projects/restrict/docs/internal/README.md"
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+
+	testCondition.Right = &ValueDescriptor{Source: Explicit, Value: "/projects/**/*.md"}
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	testResource.FieldOne = "/PROJECTS/restrict/README.MD"
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+
+	testCondition.CaseInsensitive = true
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+}
+
+func (s *stringConditionSuite) TestCheck_StartsWith() {
+	testResource := new(resourceMock)
+	testResource.FieldOne = "billing/invoices"
+
+	testRequest := &AccessRequest{
+		Resource: testResource,
+		Context: Context{
+			"Prefix": "billing/",
+		},
+	}
+
+	testCondition := &StartsWithCondition{
+		Left:  &ValueDescriptor{Source: ResourceField, Field: "FieldOne"},
+		Right: &ValueDescriptor{Source: ContextField, Field: "Prefix"},
+	}
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	testResource.FieldOne = "Billing/invoices"
+
+	err := testCondition.Check(testRequest)
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), err)
+	assert.Contains(s.T(), err.Error(), "does not start with")
+
+	testCondition.CaseInsensitive = true
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	// Prefix is not a string.
+	testRequest.Context["Prefix"] = 1
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+}
+
+func (s *stringConditionSuite) TestCheck_EndsWith() {
+	testSubject := new(subjectMock)
+	testSubject.FieldOne = "john@example.com"
+
+	testRequest := &AccessRequest{
+		Subject: testSubject,
+	}
+
+	testCondition := &EndsWithCondition{
+		Left:  &ValueDescriptor{Source: SubjectField, Field: "FieldOne"},
+		Right: &ValueDescriptor{Source: Explicit, Value: "@example.com"},
+	}
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	testSubject.FieldOne = "john@EXAMPLE.com"
+
+	err := testCondition.Check(testRequest)
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), err)
+	assert.Contains(s.T(), err.Error(), "does not end with")
+
+	testCondition.CaseInsensitive = true
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	// Malformed descriptor.
+	testCondition.Left = &ValueDescriptor{Source: SubjectField}
+
+	assert.IsType(s.T(), new(ValueDescriptorMalformedError), testCondition.Check(testRequest))
+}
+
+func (s *stringConditionSuite) TestMarshaling() {
+	testConditions := Conditions{
+		&MatchesCondition{
+			ID:              "matches",
+			Left:            &ValueDescriptor{Source: SubjectField, Field: "ID"},
+			Right:           &ValueDescriptor{Source: Explicit, Value: "^user-.*$"},
+			CaseInsensitive: true,
+		},
+		&GlobCondition{
+			Left:  &ValueDescriptor{Source: ResourceField, Field: "Path"},
+			Right: &ValueDescriptor{Source: Explicit, Value: "/projects/**"},
+		},
+		&StartsWithCondition{
+			Left:  &ValueDescriptor{Source: ResourceField, Field: "Path"},
+			Right: &ValueDescriptor{Source: Explicit, Value: "/projects"},
+		},
+		&EndsWithCondition{
+			Left:  &ValueDescriptor{Source: SubjectField, Field: "Email"},
+			Right: &ValueDescriptor{Source: Explicit, Value: "@example.com"},
+		},
+	}
+
+	conditionsJSON, err := json.Marshal(testConditions)
+	assert.Nil(s.T(), err)
+
+	jsonConditions := Conditions{}
+	err = json.Unmarshal(conditionsJSON, &jsonConditions)
+
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), jsonConditions, len(testConditions))
+	assert.True(s.T(), jsonConditions[0].(*MatchesCondition).CaseInsensitive)
+	assert.NotNil(s.T(), jsonConditions[0].(*MatchesCondition).compiled)
+	assert.NotNil(s.T(), jsonConditions[1].(*GlobCondition).compiled)
+	assert.Equal(s.T(), testConditions[2], jsonConditions[2])
+	assert.Equal(s.T(), testConditions[3], jsonConditions[3])
+
+	conditionsYAML, err := yaml.Marshal(testConditions)
+	assert.Nil(s.T(), err)
+
+	yamlConditions := Conditions{}
+	err = yaml.Unmarshal(conditionsYAML, &yamlConditions)
+
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), yamlConditions, len(testConditions))
+	assert.Equal(s.T(), "matches", yamlConditions[0].(*MatchesCondition).ID)
+	assert.NotNil(s.T(), yamlConditions[0].(*MatchesCondition).compiled)
+	assert.NotNil(s.T(), yamlConditions[1].(*GlobCondition).compiled)
+	assert.Equal(s.T(), testConditions[2], yamlConditions[2])
+	assert.Equal(s.T(), testConditions[3], yamlConditions[3])
+
+	// Invalid pattern.
+	err = json.Unmarshal([]byte(`[{"type": "MATCHES", "options": {"left": {"source": "SubjectField", "field": "ID"}, "right": {"source": "Explicit", "value": "(user"}}}]`), &Conditions{})
+
+	assert.IsType(s.T(), new(ConditionMalformedError), err)
+
+	err = yaml.Unmarshal([]byte("- type: GLOB\n  options:\n    left:\n      source: SubjectField\n      field: ID\n    right:\n      source: Explicit\n      value: \"[z-a]\"\n"), &Conditions{})
+
+	assert.IsType(s.T(), new(ConditionMalformedError), err)
+}
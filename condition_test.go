@@ -98,6 +98,30 @@ func (s *conditionsSuite) TestRegisterConditionFactory() {
 	err = RegisterConditionFactory(marshalableConditionMockName, factory)
 
 	assert.IsType(s.T(), new(ConditionFactoryAlreadyExistsError), err)
+
+	// Built-in Condition's types cannot be taken.
+	err = RegisterConditionFactory(GreaterThanConditionType, factory)
+
+	assert.IsType(s.T(), new(ConditionFactoryAlreadyExistsError), err)
+
+	// Deprecated aliases are replaced, but only once.
+	aliasFactory := ConditionFactories[GreaterThanAliasConditionType]
+
+	defer func() {
+		ConditionFactories[GreaterThanAliasConditionType] = aliasFactory
+		replaceableConditionTypes[GreaterThanAliasConditionType] = true
+	}()
+
+	assert.IsType(s.T(), new(GreaterThanCondition), aliasFactory())
+
+	err = RegisterConditionFactory(GreaterThanAliasConditionType, factory)
+
+	assert.Nil(s.T(), err)
+	assert.IsType(s.T(), new(marshalableConditionMock), ConditionFactories[GreaterThanAliasConditionType]())
+
+	err = RegisterConditionFactory(GreaterThanAliasConditionType, factory)
+
+	assert.IsType(s.T(), new(ConditionFactoryAlreadyExistsError), err)
 }
 
 func (s *conditionsSuite) TestUnmarshalJSON() {
@@ -372,9 +396,132 @@ func (s *conditionsSuite) TestFactories() {
 	notEqualConditionFactory := ConditionFactories[NotEqualConditionType]
 	emptyConditionFactory := ConditionFactories[EmptyConditionType]
 	notEmptyConditionFactory := ConditionFactories[NotEmptyConditionType]
+	greaterThanConditionFactory := ConditionFactories[GreaterThanConditionType]
+	greaterThanOrEqualConditionFactory := ConditionFactories[GreaterThanOrEqualConditionType]
+	lessThanConditionFactory := ConditionFactories[LessThanConditionType]
+	lessThanOrEqualConditionFactory := ConditionFactories[LessThanOrEqualConditionType]
+	betweenConditionFactory := ConditionFactories[BetweenConditionType]
+	inConditionFactory := ConditionFactories[InConditionType]
+	notInConditionFactory := ConditionFactories[NotInConditionType]
+	containsConditionFactory := ConditionFactories[ContainsConditionType]
+	intersectsConditionFactory := ConditionFactories[IntersectsConditionType]
+	matchesConditionFactory := ConditionFactories[MatchesConditionType]
+	globConditionFactory := ConditionFactories[GlobConditionType]
+	startsWithConditionFactory := ConditionFactories[StartsWithConditionType]
+	endsWithConditionFactory := ConditionFactories[EndsWithConditionType]
+	beforeConditionFactory := ConditionFactories[BeforeConditionType]
+	afterConditionFactory := ConditionFactories[AfterConditionType]
+	timeWindowConditionFactory := ConditionFactories[TimeWindowConditionType]
+	scheduleConditionFactory := ConditionFactories[ScheduleConditionType]
+	ipInCIDRConditionFactory := ConditionFactories[IPInCIDRConditionType]
+	ipNotInCIDRConditionFactory := ConditionFactories[IPNotInCIDRConditionType]
+	andConditionFactory := ConditionFactories[AndConditionType]
+	orConditionFactory := ConditionFactories[OrConditionType]
+	notConditionFactory := ConditionFactories[NotConditionType]
+	expressionConditionFactory := ConditionFactories[ExpressionConditionType]
 
 	assert.IsType(s.T(), new(EqualCondition), equalConditionFactory())
 	assert.IsType(s.T(), new(NotEqualCondition), notEqualConditionFactory())
 	assert.IsType(s.T(), new(EmptyCondition), emptyConditionFactory())
 	assert.IsType(s.T(), new(NotEmptyCondition), notEmptyConditionFactory())
+	assert.IsType(s.T(), new(GreaterThanCondition), greaterThanConditionFactory())
+	assert.IsType(s.T(), new(GreaterThanOrEqualCondition), greaterThanOrEqualConditionFactory())
+	assert.IsType(s.T(), new(LessThanCondition), lessThanConditionFactory())
+	assert.IsType(s.T(), new(LessThanOrEqualCondition), lessThanOrEqualConditionFactory())
+	assert.IsType(s.T(), new(BetweenCondition), betweenConditionFactory())
+	assert.IsType(s.T(), new(InCondition), inConditionFactory())
+	assert.IsType(s.T(), new(NotInCondition), notInConditionFactory())
+	assert.IsType(s.T(), new(ContainsCondition), containsConditionFactory())
+	assert.IsType(s.T(), new(IntersectsCondition), intersectsConditionFactory())
+	assert.IsType(s.T(), new(MatchesCondition), matchesConditionFactory())
+	assert.IsType(s.T(), new(GlobCondition), globConditionFactory())
+	assert.IsType(s.T(), new(StartsWithCondition), startsWithConditionFactory())
+	assert.IsType(s.T(), new(EndsWithCondition), endsWithConditionFactory())
+	assert.IsType(s.T(), new(BeforeCondition), beforeConditionFactory())
+	assert.IsType(s.T(), new(AfterCondition), afterConditionFactory())
+	assert.IsType(s.T(), new(TimeWindowCondition), timeWindowConditionFactory())
+	assert.IsType(s.T(), new(ScheduleCondition), scheduleConditionFactory())
+	assert.IsType(s.T(), new(IPInCIDRCondition), ipInCIDRConditionFactory())
+	assert.IsType(s.T(), new(IPNotInCIDRCondition), ipNotInCIDRConditionFactory())
+	assert.IsType(s.T(), new(AndCondition), andConditionFactory())
+	assert.IsType(s.T(), new(OrCondition), orConditionFactory())
+	assert.IsType(s.T(), new(NotCondition), notConditionFactory())
+	assert.IsType(s.T(), new(ExpressionCondition), expressionConditionFactory())
+}
+
+func (s *conditionsSuite) TestCopy() {
+	builtInTypes := []string{
+		EqualConditionType,
+		NotEqualConditionType,
+		EmptyConditionType,
+		NotEmptyConditionType,
+		GreaterThanConditionType,
+		GreaterThanOrEqualConditionType,
+		LessThanConditionType,
+		LessThanOrEqualConditionType,
+		BetweenConditionType,
+		InConditionType,
+		NotInConditionType,
+		ContainsConditionType,
+		IntersectsConditionType,
+		MatchesConditionType,
+		GlobConditionType,
+		StartsWithConditionType,
+		EndsWithConditionType,
+		BeforeConditionType,
+		AfterConditionType,
+		TimeWindowConditionType,
+		ScheduleConditionType,
+		IPInCIDRConditionType,
+		IPNotInCIDRConditionType,
+		AndConditionType,
+		OrConditionType,
+		NotConditionType,
+		ExpressionConditionType,
+	}
+
+	for _, conditionType := range builtInTypes {
+		condition := ConditionFactories[conditionType]()
+
+		assert.Implements(s.T(), (*CopyableCondition)(nil), condition, conditionType)
+
+		copied := copyCondition(condition)
+
+		assert.Equal(s.T(), condition, copied, conditionType)
+		assert.NotSame(s.T(), condition, copied, conditionType)
+	}
+
+	// Nested Conditions and ValueDescriptors are copied as well.
+	testCondition := &AndCondition{
+		ID: "test",
+		Conditions: Conditions{
+			&InCondition{
+				Left:  &ValueDescriptor{Source: SubjectField, Field: "ID"},
+				Right: &ValueDescriptor{Source: Explicit, Value: []interface{}{"one", []string{"two"}}},
+			},
+			&NotCondition{
+				Condition: &EqualCondition{
+					Left:  &ValueDescriptor{Source: Explicit, Value: "one"},
+					Right: &ValueDescriptor{Source: Explicit, Value: "one"},
+				},
+			},
+		},
+	}
+
+	copied := copyCondition(testCondition).(*AndCondition)
+
+	assert.Equal(s.T(), testCondition, copied)
+
+	copied.Conditions[0].(*InCondition).Right.Value.([]interface{})[1].([]string)[0] = "changed"
+	copied.Conditions[1].(*NotCondition).Condition.(*EqualCondition).Right.Value = "changed"
+	copied.Conditions = append(copied.Conditions[:1], &EmptyCondition{})
+
+	assert.Equal(s.T(), []string{"two"}, testCondition.Conditions[0].(*InCondition).Right.Value.([]interface{})[1])
+	assert.Equal(s.T(), "one", testCondition.Conditions[1].(*NotCondition).Condition.(*EqualCondition).Right.Value)
+
+	// Conditions not implementing CopyableCondition are shared.
+	customCondition := &marshalableConditionMock{}
+
+	assert.Same(s.T(), customCondition, copyCondition(customCondition))
+	assert.Nil(s.T(), Conditions(nil).copy())
 }
@@ -0,0 +1,374 @@
+package restrict
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/el-mike/restrict/v2/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// BeforeConditionType - BeforeCondition's type identifier.
+	BeforeConditionType = "BEFORE"
+	// AfterConditionType - AfterCondition's type identifier.
+	AfterConditionType = "AFTER"
+	// TimeWindowConditionType - TimeWindowCondition's type identifier.
+	TimeWindowConditionType = "TIME_WINDOW"
+	// ScheduleConditionType - ScheduleCondition's type identifier.
+	ScheduleConditionType = "SCHEDULE"
+)
+
+// scheduleTimeLayout - layout of ScheduleCondition's From and To fields.
+const scheduleTimeLayout = "15:04"
+
+// weekdaysByName - maps lower-cased full and abbreviated weekday names to time.Weekday.
+var weekdaysByName = map[string]time.Weekday{}
+
+func init() {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		name := strings.ToLower(weekday.String())
+
+		weekdaysByName[name] = weekday
+		weekdaysByName[name[:3]] = weekday
+	}
+}
+
+// BeforeCondition - checks whether given time (Left) is before some other time (Right).
+// Values can be time.Time, *time.Time or strings in RFC3339 or "2006-01-02" formats.
+// Use CurrentTime ValueSource to compare against current time.
+type BeforeCondition baseComparisonCondition
+
+// Type - returns Condition's type.
+func (c *BeforeCondition) Type() string {
+	return BeforeConditionType
+}
+
+// Copy - returns a deep copy of the Condition.
+func (c *BeforeCondition) Copy() Condition {
+	return (*BeforeCondition)((*baseComparisonCondition)(c).copy())
+}
+
+// Check - returns true if Left is before Right, false otherwise.
+func (c *BeforeCondition) Check(request *AccessRequest) error {
+	left, right, err := unpackTimeDescriptors(c, c.Left, c.Right, request)
+	if err != nil {
+		return err
+	}
+
+	if !left.Before(right) {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("time \"%v\" is not before \"%v\"", left, right))
+	}
+
+	return nil
+}
+
+// AfterCondition - checks whether given time (Left) is after some other time (Right).
+// Values can be time.Time, *time.Time or strings in RFC3339 or "2006-01-02" formats.
+// Use CurrentTime ValueSource to compare against current time.
+type AfterCondition baseComparisonCondition
+
+// Type - returns Condition's type.
+func (c *AfterCondition) Type() string {
+	return AfterConditionType
+}
+
+// Copy - returns a deep copy of the Condition.
+func (c *AfterCondition) Copy() Condition {
+	return (*AfterCondition)((*baseComparisonCondition)(c).copy())
+}
+
+// Check - returns true if Left is after Right, false otherwise.
+func (c *AfterCondition) Check(request *AccessRequest) error {
+	left, right, err := unpackTimeDescriptors(c, c.Left, c.Right, request)
+	if err != nil {
+		return err
+	}
+
+	if !left.After(right) {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("time \"%v\" is not after \"%v\"", left, right))
+	}
+
+	return nil
+}
+
+// TimeWindowCondition - checks whether current time is within a time window,
+// starting at From (inclusive) and ending at To (exclusive). If any of the bounds
+// is not defined, the window is unbounded on that side.
+type TimeWindowCondition struct {
+	// ID - Condition's id, useful when there is a need to identify failing Condition.
+	ID string `json:"name,omitempty" yaml:"name,omitempty"`
+	// From - ValueDescriptor for window's start.
+	From *ValueDescriptor `json:"from,omitempty" yaml:"from,omitempty"`
+	// To - ValueDescriptor for window's end.
+	To *ValueDescriptor `json:"to,omitempty" yaml:"to,omitempty"`
+}
+
+// Type - returns Condition's type.
+func (c *TimeWindowCondition) Type() string {
+	return TimeWindowConditionType
+}
+
+// Copy - returns a deep copy of the Condition.
+func (c *TimeWindowCondition) Copy() Condition {
+	if c == nil {
+		return c
+	}
+
+	result := *c
+	result.From = c.From.copy()
+	result.To = c.To.copy()
+
+	return &result
+}
+
+// Check - returns true if current time is within the window, false otherwise.
+func (c *TimeWindowCondition) Check(request *AccessRequest) error {
+	now := request.Now()
+
+	if c.From != nil {
+		from, err := getTimeValue(c, c.From, request)
+		if err != nil {
+			return err
+		}
+
+		if now.Before(from) {
+			return NewConditionNotSatisfiedError(c, request, fmt.Errorf("current time \"%v\" is before \"%v\"", now, from))
+		}
+	}
+
+	if c.To != nil {
+		to, err := getTimeValue(c, c.To, request)
+		if err != nil {
+			return err
+		}
+
+		if !now.Before(to) {
+			return NewConditionNotSatisfiedError(c, request, fmt.Errorf("current time \"%v\" is not before \"%v\"", now, to))
+		}
+	}
+
+	return nil
+}
+
+// ScheduleCondition - checks whether current time matches a weekly schedule, in given time zone.
+// Weekdays accept full or abbreviated names (e.g. "Monday" or "Mon"), while From (inclusive)
+// and To (exclusive) accept time of day in "15:04" format. If From is later than To, the window
+// spans midnight. Empty fields are not checked, and empty TimeZone means UTC.
+type ScheduleCondition struct {
+	// ID - Condition's id, useful when there is a need to identify failing Condition.
+	ID string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Weekdays - days of the week when the Condition can be satisfied.
+	Weekdays []string `json:"weekdays,omitempty" yaml:"weekdays,omitempty"`
+	// From - start of the time of day window.
+	From string `json:"from,omitempty" yaml:"from,omitempty"`
+	// To - end of the time of day window.
+	To string `json:"to,omitempty" yaml:"to,omitempty"`
+	// TimeZone - IANA time zone name (e.g. "Europe/Warsaw"), in which the schedule is defined.
+	TimeZone string `json:"timeZone,omitempty" yaml:"timeZone,omitempty"`
+
+	// Parsed schedule, set when the Condition is prepared.
+	schedule *schedule
+}
+
+// scheduleConditionOptions - helper type for unmarshaling ScheduleCondition
+// without recursion.
+type scheduleConditionOptions ScheduleCondition
+
+// schedule - parsed representation of ScheduleCondition's fields.
+type schedule struct {
+	// Fields the schedule has been parsed from.
+	source string
+	// Allowed weekdays, empty if not restricted.
+	weekdays map[time.Weekday]bool
+	// Minutes since midnight, -1 if not defined.
+	from int
+	to   int
+	// Location of the schedule.
+	location *time.Location
+}
+
+// Type - returns Condition's type.
+func (c *ScheduleCondition) Type() string {
+	return ScheduleConditionType
+}
+
+// Copy - returns a deep copy of the Condition.
+func (c *ScheduleCondition) Copy() Condition {
+	if c == nil {
+		return c
+	}
+
+	// Parsed schedule is never modified, so it can be shared.
+	result := *c
+
+	if c.Weekdays != nil {
+		result.Weekdays = append([]string{}, c.Weekdays...)
+	}
+
+	return &result
+}
+
+// Prepare - parses the schedule, returns ConditionMalformedError if it's not valid.
+func (c *ScheduleCondition) Prepare() error {
+	if c.schedule != nil && c.schedule.source == c.source() {
+		return nil
+	}
+
+	parsed, err := c.parse()
+	if err != nil {
+		return err
+	}
+
+	c.schedule = parsed
+
+	return nil
+}
+
+// Check - returns true if current time matches the schedule, false otherwise.
+func (c *ScheduleCondition) Check(request *AccessRequest) error {
+	parsed := c.schedule
+
+	// If the Condition has not been prepared (or its fields have changed since),
+	// the schedule is parsed for this check only.
+	if parsed == nil || parsed.source != c.source() {
+		var err error
+
+		if parsed, err = c.parse(); err != nil {
+			return err
+		}
+	}
+
+	now := request.Now().In(parsed.location)
+
+	if len(parsed.weekdays) > 0 && !parsed.weekdays[now.Weekday()] {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("weekday \"%s\" is not scheduled", now.Weekday()))
+	}
+
+	minutes := now.Hour()*60 + now.Minute()
+
+	afterFrom := parsed.from < 0 || minutes >= parsed.from
+	beforeTo := parsed.to < 0 || minutes < parsed.to
+
+	inWindow := afterFrom && beforeTo
+
+	// Window spanning midnight.
+	if parsed.from >= 0 && parsed.to >= 0 && parsed.from > parsed.to {
+		inWindow = afterFrom || beforeTo
+	}
+
+	if !inWindow {
+		return NewConditionNotSatisfiedError(c, request, fmt.Errorf("time \"%s\" is not within scheduled hours", now.Format(scheduleTimeLayout)))
+	}
+
+	return nil
+}
+
+// UnmarshalJSON - unmarshals ScheduleCondition and parses its schedule.
+func (c *ScheduleCondition) UnmarshalJSON(jsonData []byte) error {
+	if err := json.Unmarshal(jsonData, (*scheduleConditionOptions)(c)); err != nil {
+		return err
+	}
+
+	return c.Prepare()
+}
+
+// UnmarshalYAML - unmarshals ScheduleCondition and parses its schedule.
+func (c *ScheduleCondition) UnmarshalYAML(value *yaml.Node) error {
+	if err := value.Decode((*scheduleConditionOptions)(c)); err != nil {
+		return err
+	}
+
+	return c.Prepare()
+}
+
+// source - returns a string identifying the fields the schedule is parsed from.
+func (c *ScheduleCondition) source() string {
+	return strings.Join([]string{strings.Join(c.Weekdays, ","), c.From, c.To, c.TimeZone}, "|")
+}
+
+// parse - parses and validates ScheduleCondition's fields.
+func (c *ScheduleCondition) parse() (*schedule, error) {
+	parsed := &schedule{
+		source:   c.source(),
+		weekdays: map[time.Weekday]bool{},
+		from:     -1,
+		to:       -1,
+	}
+
+	for _, name := range c.Weekdays {
+		weekday, ok := weekdaysByName[strings.ToLower(name)]
+		if !ok {
+			return nil, newConditionMalformedError(c, fmt.Errorf("unknown weekday \"%s\"", name))
+		}
+
+		parsed.weekdays[weekday] = true
+	}
+
+	var err error
+
+	if parsed.from, err = parseTimeOfDay(c.From); err != nil {
+		return nil, newConditionMalformedError(c, err)
+	}
+
+	if parsed.to, err = parseTimeOfDay(c.To); err != nil {
+		return nil, newConditionMalformedError(c, err)
+	}
+
+	if parsed.from >= 0 && parsed.from == parsed.to {
+		return nil, newConditionMalformedError(c, fmt.Errorf("from and to cannot be equal"))
+	}
+
+	if parsed.location, err = time.LoadLocation(c.TimeZone); err != nil {
+		return nil, newConditionMalformedError(c, err)
+	}
+
+	return parsed, nil
+}
+
+// parseTimeOfDay - returns minutes since midnight for given time of day,
+// or -1 if it's empty.
+func parseTimeOfDay(value string) (int, error) {
+	if value == "" {
+		return -1, nil
+	}
+
+	parsed, err := time.Parse(scheduleTimeLayout, value)
+	if err != nil {
+		return 0, fmt.Errorf("time of day \"%s\" has to be in \"%s\" format", value, scheduleTimeLayout)
+	}
+
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+// getTimeValue - helper function for getting ValueDescriptor's value as time.Time.
+func getTimeValue(condition Condition, descriptor *ValueDescriptor, request *AccessRequest) (time.Time, error) {
+	value, err := descriptor.GetValue(request)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	timeValue, ok := utils.GetTimeValue(value)
+	if !ok {
+		return time.Time{}, NewConditionNotSatisfiedError(condition, request, fmt.Errorf("value \"%v\" is not a valid time", value))
+	}
+
+	return timeValue, nil
+}
+
+// unpackTimeDescriptors - helper function for unpacking ValueDescriptors' values as time.Time.
+func unpackTimeDescriptors(condition Condition, left, right *ValueDescriptor, request *AccessRequest) (time.Time, time.Time, error) {
+	leftTime, err := getTimeValue(condition, left, request)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	rightTime, err := getTimeValue(condition, right, request)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return leftTime, rightTime, nil
+}
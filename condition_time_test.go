@@ -0,0 +1,231 @@
+package restrict
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+type timeConditionSuite struct {
+	suite.Suite
+
+	// Wednesday.
+	testNow time.Time
+}
+
+func TestTimeConditionSuite(t *testing.T) {
+	suite.Run(t, new(timeConditionSuite))
+}
+
+func (s *timeConditionSuite) SetupSuite() {
+	s.testNow = time.Date(2023, time.March, 1, 12, 30, 0, 0, time.UTC)
+}
+
+func (s *timeConditionSuite) TestType() {
+	assert.Equal(s.T(), BeforeConditionType, (&BeforeCondition{}).Type())
+	assert.Equal(s.T(), AfterConditionType, (&AfterCondition{}).Type())
+	assert.Equal(s.T(), TimeWindowConditionType, (&TimeWindowCondition{}).Type())
+	assert.Equal(s.T(), ScheduleConditionType, (&ScheduleCondition{}).Type())
+}
+
+func (s *timeConditionSuite) TestCheck_BeforeAfter() {
+	expiresAt := s.testNow.Add(time.Hour)
+
+	testRequest := (&AccessRequest{
+		Context: Context{
+			"ExpiresAt": &expiresAt,
+		},
+	}).withTime(s.testNow)
+
+	afterCondition := &AfterCondition{
+		Left:  &ValueDescriptor{Source: ContextField, Field: "ExpiresAt"},
+		Right: &ValueDescriptor{Source: CurrentTime},
+	}
+
+	beforeCondition := &BeforeCondition{
+		Left:  &ValueDescriptor{Source: CurrentTime},
+		Right: &ValueDescriptor{Source: ContextField, Field: "ExpiresAt"},
+	}
+
+	assert.Nil(s.T(), afterCondition.Check(testRequest))
+	assert.Nil(s.T(), beforeCondition.Check(testRequest))
+
+	testRequest.Context["ExpiresAt"] = s.testNow
+
+	err := afterCondition.Check(testRequest)
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), err)
+	assert.Contains(s.T(), err.Error(), "is not after")
+
+	err = beforeCondition.Check(testRequest)
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), err)
+	assert.Contains(s.T(), err.Error(), "is not before")
+
+	// Explicit time.
+	beforeCondition.Right = &ValueDescriptor{Source: Explicit, Value: "2024-01-01"}
+
+	assert.Nil(s.T(), beforeCondition.Check(testRequest))
+
+	// Invalid time.
+	beforeCondition.Right = &ValueDescriptor{Source: Explicit, Value: "tomorrow"}
+
+	err = beforeCondition.Check(testRequest)
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), err)
+	assert.Contains(s.T(), err.Error(), "is not a valid time")
+
+	// Malformed descriptor.
+	afterCondition.Left = &ValueDescriptor{Source: ContextField}
+
+	assert.IsType(s.T(), new(ValueDescriptorMalformedError), afterCondition.Check(testRequest))
+}
+
+func (s *timeConditionSuite) TestCheck_TimeWindow() {
+	testRequest := (&AccessRequest{}).withTime(s.testNow)
+
+	testCondition := &TimeWindowCondition{
+		From: &ValueDescriptor{Source: Explicit, Value: "2023-03-01T12:00:00Z"},
+		To:   &ValueDescriptor{Source: Explicit, Value: "2023-03-01T13:00:00Z"},
+	}
+
+	assert.Nil(s.T(), testCondition.Check(testRequest))
+
+	// From is inclusive.
+	assert.Nil(s.T(), testCondition.Check((&AccessRequest{}).withTime(s.testNow.Add(-30*time.Minute))))
+
+	// To is exclusive.
+	err := testCondition.Check((&AccessRequest{}).withTime(s.testNow.Add(30 * time.Minute)))
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), err)
+	assert.Contains(s.T(), err.Error(), "is not before")
+
+	err = testCondition.Check((&AccessRequest{}).withTime(s.testNow.Add(-time.Hour)))
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), err)
+	assert.Contains(s.T(), err.Error(), "is before")
+
+	// Unbounded window.
+	testCondition.To = nil
+
+	assert.Nil(s.T(), testCondition.Check((&AccessRequest{}).withTime(s.testNow.Add(24*time.Hour))))
+
+	// Invalid bound.
+	testCondition.From = &ValueDescriptor{Source: Explicit, Value: 1}
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check(testRequest))
+}
+
+func (s *timeConditionSuite) TestCheck_Schedule() {
+	testCondition := &ScheduleCondition{
+		Weekdays: []string{"Monday", "tue", "WED", "Thursday", "Fri"},
+		From:     "09:00",
+		To:       "17:00",
+		TimeZone: "Europe/Warsaw",
+	}
+
+	assert.Nil(s.T(), testCondition.Prepare())
+	assert.NotNil(s.T(), testCondition.schedule)
+
+	// 13:30 in Warsaw.
+	assert.Nil(s.T(), testCondition.Check((&AccessRequest{}).withTime(s.testNow)))
+
+	// 17:30 in Warsaw.
+	err := testCondition.Check((&AccessRequest{}).withTime(s.testNow.Add(4 * time.Hour)))
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), err)
+	assert.Contains(s.T(), err.Error(), "is not within scheduled hours")
+
+	// Saturday.
+	err = testCondition.Check((&AccessRequest{}).withTime(s.testNow.Add(3 * 24 * time.Hour)))
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), err)
+	assert.Contains(s.T(), err.Error(), "is not scheduled")
+
+	// Window spanning midnight, changed after the Condition has been prepared.
+	testCondition.Weekdays = nil
+	testCondition.From = "22:00"
+	testCondition.To = "06:00"
+	testCondition.TimeZone = ""
+
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check((&AccessRequest{}).withTime(s.testNow)))
+	assert.Nil(s.T(), testCondition.Check((&AccessRequest{}).withTime(s.testNow.Add(10*time.Hour))))
+	assert.Nil(s.T(), testCondition.Check((&AccessRequest{}).withTime(s.testNow.Add(15*time.Hour))))
+
+	// Only one bound.
+	testCondition.To = ""
+
+	assert.Nil(s.T(), testCondition.Check((&AccessRequest{}).withTime(s.testNow.Add(10*time.Hour))))
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), testCondition.Check((&AccessRequest{}).withTime(s.testNow.Add(15*time.Hour))))
+
+	// Malformed schedules.
+	malformedConditions := []*ScheduleCondition{
+		{Weekdays: []string{"Someday"}},
+		{From: "9am"},
+		{To: "25:00"},
+		{From: "10:00", To: "10:00"},
+		{TimeZone: "Mars/Olympus_Mons"},
+	}
+
+	for _, malformedCondition := range malformedConditions {
+		assert.IsType(s.T(), new(ConditionMalformedError), malformedCondition.Prepare())
+		assert.IsType(s.T(), new(ConditionMalformedError), malformedCondition.Check(&AccessRequest{}))
+	}
+}
+
+func (s *timeConditionSuite) TestMarshaling() {
+	testConditions := Conditions{
+		&AfterCondition{
+			Left:  &ValueDescriptor{Source: ResourceField, Field: "ExpiresAt"},
+			Right: &ValueDescriptor{Source: CurrentTime},
+		},
+		&TimeWindowCondition{
+			ID:   "promotion",
+			From: &ValueDescriptor{Source: Explicit, Value: "2023-03-01T00:00:00Z"},
+		},
+		&ScheduleCondition{
+			ID:       "officeHours",
+			Weekdays: []string{"Mon", "Fri"},
+			From:     "09:00",
+			To:       "17:00",
+			TimeZone: "America/New_York",
+		},
+	}
+
+	conditionsJSON, err := json.Marshal(testConditions)
+	assert.Nil(s.T(), err)
+
+	jsonConditions := Conditions{}
+	err = json.Unmarshal(conditionsJSON, &jsonConditions)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), testConditions[0], jsonConditions[0])
+	assert.Equal(s.T(), testConditions[1], jsonConditions[1])
+	assert.Equal(s.T(), "America/New_York", jsonConditions[2].(*ScheduleCondition).TimeZone)
+	assert.NotNil(s.T(), jsonConditions[2].(*ScheduleCondition).schedule)
+
+	conditionsYAML, err := yaml.Marshal(testConditions)
+	assert.Nil(s.T(), err)
+
+	yamlConditions := Conditions{}
+	err = yaml.Unmarshal(conditionsYAML, &yamlConditions)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), testConditions[0], yamlConditions[0])
+	assert.Equal(s.T(), testConditions[1], yamlConditions[1])
+	assert.Equal(s.T(), []string{"Mon", "Fri"}, yamlConditions[2].(*ScheduleCondition).Weekdays)
+	assert.NotNil(s.T(), yamlConditions[2].(*ScheduleCondition).schedule)
+
+	// Unknown time zone.
+	err = json.Unmarshal([]byte(`[{"type": "SCHEDULE", "options": {"timeZone": "Unknown/Zone"}}]`), &Conditions{})
+
+	assert.IsType(s.T(), new(ConditionMalformedError), err)
+
+	err = yaml.Unmarshal([]byte("- type: SCHEDULE\n  options:\n    weekdays: [Someday]\n"), &Conditions{})
+
+	assert.IsType(s.T(), new(ConditionMalformedError), err)
+}
@@ -0,0 +1,137 @@
+package restrict
+
+// AppliedPermission - describes a Permission that applied to an Action, i.e. had its Conditions
+// satisfied (or skipped).
+type AppliedPermission struct {
+	// Permission - the Permission that applied.
+	Permission *Permission
+	// RoleName - name of the Role the Permission is granted for - either Subject's Role or its parent.
+	RoleName string
+	// InheritancePath - Roles leading to the Role the Permission is granted for, starting with
+	// Subject's Role and ending with RoleName.
+	InheritancePath []string
+	// ResourceName - name of the Resource the Permission is granted for - either Request's Resource
+	// or its ancestor.
+	ResourceName string
+}
+
+// ActionDecision - describes the decision made for a single Action.
+type ActionDecision struct {
+	// Action - the Action being decided.
+	Action string
+	// Allowed - true if the Action is allowed.
+	Allowed bool
+	// Effect - the Effect resolved by CombiningAlgorithm, empty if none of the Permissions applied.
+	Effect Effect
+	// Permissions - applied Permissions with the resolved Effect, i.e. the ones that granted the Action
+	// when it's allowed, or the ones that explicitly denied it otherwise.
+	Permissions []*AppliedPermission
+	// Reasons - PermissionErrors describing why the Action is not allowed, nil if it is.
+	Reasons PermissionErrors
+
+	// rolesReasons - Reasons kept per Subject's Role.
+	rolesReasons []PermissionErrors
+	// conditional - true if the decision depends on Conditions or Resource instance's ancestors.
+	conditional bool
+}
+
+// newActionDecision - returns new ActionDecision instance.
+func newActionDecision(action string, effect Effect, permissions []*AppliedPermission, rolesReasons []PermissionErrors) *ActionDecision {
+	decision := &ActionDecision{
+		Action:      action,
+		Allowed:     effect == AllowEffect,
+		Effect:      effect,
+		Permissions: permissions,
+	}
+
+	if decision.Allowed {
+		return decision
+	}
+
+	decision.rolesReasons = rolesReasons
+	decision.Reasons = PermissionErrors{}
+
+	for _, reasons := range rolesReasons {
+		decision.Reasons = append(decision.Reasons, reasons...)
+	}
+
+	return decision
+}
+
+// Decision - describes the result of AccessRequest's evaluation.
+type Decision struct {
+	// Request - evaluated AccessRequest.
+	Request *AccessRequest
+	// Allowed - true if all the Actions in the Request are allowed.
+	Allowed bool
+	// Actions - decisions made for every evaluated Action, in order of the Request's Actions.
+	Actions []*ActionDecision
+	// Trace - recorded evaluation steps, nil unless Request's Trace is set to true.
+	Trace *Trace
+}
+
+// newDecision - returns new Decision instance.
+func newDecision(request *AccessRequest) *Decision {
+	return &Decision{
+		Request: request,
+		Allowed: true,
+		Actions: []*ActionDecision{},
+	}
+}
+
+// addAction - adds ActionDecision to the Decision.
+func (d *Decision) addAction(actionDecision *ActionDecision) {
+	d.Actions = append(d.Actions, actionDecision)
+
+	if !actionDecision.Allowed {
+		d.Allowed = false
+	}
+}
+
+// GetAction - returns ActionDecision for given Action, or nil if the Action has not been evaluated.
+func (d *Decision) GetAction(action string) *ActionDecision {
+	for _, actionDecision := range d.Actions {
+		if actionDecision.Action == action {
+			return actionDecision
+		}
+	}
+
+	return nil
+}
+
+// GetDeniedActions - returns all Actions for which access was denied.
+func (d *Decision) GetDeniedActions() []string {
+	actions := []string{}
+
+	for _, actionDecision := range d.Actions {
+		if !actionDecision.Allowed {
+			actions = append(actions, actionDecision.Action)
+		}
+	}
+
+	return actions
+}
+
+// Reasons - returns PermissionErrors of all denied Actions, ordered by Subject's Roles first,
+// and Actions second.
+func (d *Decision) Reasons() PermissionErrors {
+	rolesReasons := []PermissionErrors{}
+
+	for _, actionDecision := range d.Actions {
+		for i, reasons := range actionDecision.rolesReasons {
+			if i >= len(rolesReasons) {
+				rolesReasons = append(rolesReasons, PermissionErrors{})
+			}
+
+			rolesReasons[i] = append(rolesReasons[i], reasons...)
+		}
+	}
+
+	result := PermissionErrors{}
+
+	for _, reasons := range rolesReasons {
+		result = append(result, reasons...)
+	}
+
+	return result
+}
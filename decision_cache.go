@@ -0,0 +1,166 @@
+package restrict
+
+import (
+	"container/list"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DecisionCache - bounded cache of ActionDecisions, used by AccessManager to avoid evaluating
+// the same Actions over and over again. Decisions that do not depend on Conditions are cached
+// by Subject's Roles, Resource's name and Action, while the ones that do - only when AccessRequest's
+// CacheKey is set. Entries expire after the TTL, least recently used ones are evicted when the cache
+// is full, and all of them are dropped once the policy version changes (see PolicyVersionProvider).
+// DecisionCache is safe for concurrent use.
+type DecisionCache struct {
+	// size - maximum number of cached decisions.
+	size int
+	// ttl - time after which cached decision expires, zero means no expiration.
+	ttl time.Duration
+
+	// version - version of the policy cached decisions have been made for.
+	version uint64
+	// entries - cached decisions' elements of the order list, by their keys.
+	entries map[string]*list.Element
+	// order - cached decisions, from the most to the least recently used.
+	order *list.List
+
+	sync.Mutex
+}
+
+// decisionCacheEntry - single cached decision.
+type decisionCacheEntry struct {
+	key       string
+	decision  *ActionDecision
+	expiresAt time.Time
+}
+
+// NewDecisionCache - returns new DecisionCache instance, holding up to size decisions, each one
+// for given TTL. Non-positive TTL means that decisions do not expire, while non-positive size
+// results in a cache that does not hold any decisions.
+func NewDecisionCache(size int, ttl time.Duration) *DecisionCache {
+	return &DecisionCache{
+		size:    size,
+		ttl:     ttl,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+// Len - returns the number of cached decisions, including expired ones that have not been evicted yet.
+func (c *DecisionCache) Len() int {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.order.Len()
+}
+
+// Purge - drops all cached decisions.
+func (c *DecisionCache) Purge() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.purge()
+}
+
+// purge - drops all cached decisions, without locking.
+func (c *DecisionCache) purge() {
+	c.entries = map[string]*list.Element{}
+	c.order.Init()
+}
+
+// get - returns cached decision for given key, or nil if there is none, it has expired,
+// or it has been made for a different policy version.
+func (c *DecisionCache) get(key string, version uint64, now time.Time) *ActionDecision {
+	c.Lock()
+	defer c.Unlock()
+
+	c.checkVersion(version)
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+
+	entry := element.Value.(*decisionCacheEntry)
+
+	if !entry.expiresAt.IsZero() && !now.Before(entry.expiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+
+		return nil
+	}
+
+	c.order.MoveToFront(element)
+
+	return entry.decision
+}
+
+// set - caches given decision under given key, evicting the least recently used one if the cache is full.
+func (c *DecisionCache) set(key string, decision *ActionDecision, version uint64, now time.Time) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.size <= 0 {
+		return
+	}
+
+	c.checkVersion(version)
+
+	// Decision made for an older version of the policy should not be cached.
+	if version != c.version {
+		return
+	}
+
+	entry := &decisionCacheEntry{
+		key:      key,
+		decision: decision,
+	}
+
+	if c.ttl > 0 {
+		entry.expiresAt = now.Add(c.ttl)
+	}
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*decisionCacheEntry).key)
+	}
+}
+
+// checkVersion - drops all cached decisions if given policy version is newer than the cached one.
+func (c *DecisionCache) checkVersion(version uint64) {
+	if version > c.version {
+		c.purge()
+		c.version = version
+	}
+}
+
+// getDecisionCacheKey - returns a key for ActionDecision made for given Roles, Resource name and Action.
+// Keys of decisions depending on Conditions contain request's CacheKey and SkipConditions option as well.
+func getDecisionCacheKey(request *AccessRequest, roles []string, resourceName, action string, conditional bool) string {
+	// Unit separator is used, as it's unlikely to be a part of any name.
+	parts := []string{resourceName, action, strings.Join(roles, "\x1e")}
+
+	if conditional {
+		parts = append(
+			parts,
+			request.CacheKey,
+			strconv.FormatBool(request.SkipConditions),
+		)
+	}
+
+	return strings.Join(parts, "\x1f")
+}
@@ -0,0 +1,315 @@
+package restrict
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// publishingPolicyProvider - PolicyManager publishing a change of the policy right after
+// AccessManager reads its snapshot or PolicyIndex for the first time.
+type publishingPolicyProvider struct {
+	*PolicyManager
+
+	change func()
+}
+
+func (p *publishingPolicyProvider) publishChange() {
+	if change := p.change; change != nil {
+		p.change = nil
+		change()
+	}
+}
+
+func (p *publishingPolicyProvider) getSnapshot() *policySnapshot {
+	snapshot := p.PolicyManager.getSnapshot()
+	p.publishChange()
+
+	return snapshot
+}
+
+func (p *publishingPolicyProvider) GetPolicyIndex() *PolicyIndex {
+	index := p.PolicyManager.GetPolicyIndex()
+	p.publishChange()
+
+	return index
+}
+
+// versionedPolicyProvider - PolicyProvider with PolicyIndex and version, not providing snapshots.
+type versionedPolicyProvider struct {
+	PolicyProvider
+	PolicyIndexProvider
+	PolicyVersionProvider
+}
+
+type decisionCacheSuite struct {
+	suite.Suite
+}
+
+func TestDecisionCacheSuite(t *testing.T) {
+	suite.Run(t, new(decisionCacheSuite))
+}
+
+func (s *decisionCacheSuite) TestNewDecisionCache() {
+	cache := NewDecisionCache(10, time.Minute)
+
+	assert.IsType(s.T(), new(DecisionCache), cache)
+	assert.Equal(s.T(), 0, cache.Len())
+}
+
+func (s *decisionCacheSuite) TestGetAndSet() {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	testDecision := newActionDecision(readAction, AllowEffect, nil, nil)
+
+	cache := NewDecisionCache(2, time.Minute)
+
+	assert.Nil(s.T(), cache.get("one", 1, now))
+
+	cache.set("one", testDecision, 1, now)
+
+	assert.Equal(s.T(), testDecision, cache.get("one", 1, now))
+	assert.Equal(s.T(), 1, cache.Len())
+
+	// Expired decision.
+	assert.Nil(s.T(), cache.get("one", 1, now.Add(time.Minute)))
+	assert.Equal(s.T(), 0, cache.Len())
+
+	// Least recently used decision is evicted.
+	cache.set("one", testDecision, 1, now)
+	cache.set("two", testDecision, 1, now)
+
+	assert.NotNil(s.T(), cache.get("one", 1, now))
+
+	cache.set("three", testDecision, 1, now)
+
+	assert.Equal(s.T(), 2, cache.Len())
+	assert.NotNil(s.T(), cache.get("one", 1, now))
+	assert.Nil(s.T(), cache.get("two", 1, now))
+	assert.NotNil(s.T(), cache.get("three", 1, now))
+
+	// Newer policy version drops all decisions.
+	assert.Nil(s.T(), cache.get("one", 2, now))
+	assert.Equal(s.T(), 0, cache.Len())
+
+	// Decisions made for older policy version are not cached.
+	cache.set("one", testDecision, 1, now)
+
+	assert.Equal(s.T(), 0, cache.Len())
+
+	cache.set("one", testDecision, 2, now)
+	cache.Purge()
+
+	assert.Equal(s.T(), 0, cache.Len())
+}
+
+func (s *decisionCacheSuite) TestGetAndSet_NoLimits() {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	testDecision := newActionDecision(readAction, AllowEffect, nil, nil)
+
+	// Non-positive TTL - decisions do not expire.
+	cache := NewDecisionCache(1, 0)
+
+	cache.set("one", testDecision, 0, now)
+
+	assert.NotNil(s.T(), cache.get("one", 0, now.Add(24*time.Hour)))
+
+	// Non-positive size - decisions are not cached.
+	cache = NewDecisionCache(0, time.Minute)
+
+	cache.set("one", testDecision, 0, now)
+
+	assert.Nil(s.T(), cache.get("one", 0, now))
+}
+
+func (s *decisionCacheSuite) TestAuthorize_WithCache() {
+	testAdapter := new(storageAdapterMock)
+	testAdapter.On("LoadPolicy").Return(getBasicPolicy(), nil)
+
+	policyManager, _ := NewPolicyManager(testAdapter, false)
+
+	manager := NewAccessManager(policyManager)
+	manager.SetDecisionCache(NewDecisionCache(10, time.Minute))
+
+	testSubject := new(subjectMock)
+	testSubject.On("GetRoles").Return([]string{basicRoleOneName})
+
+	testResource := new(resourceMock)
+	testResource.On("GetResourceName").Return(basicResourceOneName)
+
+	testRequest := &AccessRequest{
+		Subject:  testSubject,
+		Resource: testResource,
+		Actions:  []string{readAction},
+	}
+
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+	assert.Equal(s.T(), 1, manager.decisionCache.Len())
+
+	// Cached decision is used.
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+	assert.Equal(s.T(), 1, manager.decisionCache.Len())
+
+	// Changing the policy invalidates cached decisions.
+	err := policyManager.DeletePermission(basicRoleOneName, basicResourceOneName, readAction)
+
+	assert.Nil(s.T(), err)
+	assert.IsType(s.T(), new(AccessDeniedError), manager.Authorize(testRequest))
+
+	err = policyManager.AddPermission(basicRoleOneName, basicResourceOneName, &Permission{Action: readAction})
+
+	assert.Nil(s.T(), err)
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+
+	// Reloading the policy invalidates cached decisions.
+	testPolicy := getBasicPolicy()
+	testPolicy.Roles[basicRoleOneName].Grants[basicResourceOneName] = Permissions{}
+
+	testAdapter.ExpectedCalls = nil
+	testAdapter.On("LoadPolicy").Return(testPolicy, nil)
+
+	assert.Nil(s.T(), policyManager.LoadPolicy())
+	assert.IsType(s.T(), new(AccessDeniedError), manager.Authorize(testRequest))
+
+	// Disabled cache.
+	manager.SetDecisionCache(nil)
+
+	assert.IsType(s.T(), new(AccessDeniedError), manager.Authorize(testRequest))
+}
+
+func (s *decisionCacheSuite) TestAuthorize_WithCacheAndConditions() {
+	testRole := getBasicRoleOne()
+	testRole.Grants[basicResourceOneName] = append(testRole.Grants[basicResourceOneName], &Permission{
+		Action: updateAction,
+		Conditions: Conditions{
+			&EqualCondition{
+				Left:  &ValueDescriptor{Source: ResourceField, Field: "CreatedBy"},
+				Right: &ValueDescriptor{Source: SubjectField, Field: "ID"},
+			},
+		},
+	})
+
+	testPolicyProvider := new(policyProviderMock)
+	testPolicyProvider.On("GetRole", basicRoleOneName).Return(testRole, nil)
+
+	manager := NewAccessManager(testPolicyProvider)
+	manager.SetDecisionCache(NewDecisionCache(10, time.Minute))
+
+	testSubject := &subjectMock{ID: "user-1"}
+	testSubject.On("GetRoles").Return([]string{basicRoleOneName})
+
+	testOwnResource := &resourceMock{CreatedBy: "user-1"}
+	testOwnResource.On("GetResourceName").Return(basicResourceOneName)
+
+	testOtherResource := &resourceMock{CreatedBy: "user-2"}
+	testOtherResource.On("GetResourceName").Return(basicResourceOneName)
+
+	// Without CacheKey, conditional decisions are not cached.
+	err := manager.Authorize(&AccessRequest{
+		Subject:  testSubject,
+		Resource: testOwnResource,
+		Actions:  []string{readAction, updateAction},
+	})
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1, manager.decisionCache.Len())
+
+	err = manager.Authorize(&AccessRequest{
+		Subject:  testSubject,
+		Resource: testOtherResource,
+		Actions:  []string{updateAction},
+	})
+
+	assert.IsType(s.T(), new(AccessDeniedError), err)
+
+	// With CacheKey, conditional decisions are cached per key.
+	err = manager.Authorize(&AccessRequest{
+		Subject:  testSubject,
+		Resource: testOwnResource,
+		Actions:  []string{updateAction},
+		CacheKey: "user-1:own",
+	})
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 2, manager.decisionCache.Len())
+
+	err = manager.Authorize(&AccessRequest{
+		Subject:  testSubject,
+		Resource: testOtherResource,
+		Actions:  []string{updateAction},
+		CacheKey: "user-1:other",
+	})
+
+	assert.IsType(s.T(), new(AccessDeniedError), err)
+	assert.Equal(s.T(), 3, manager.decisionCache.Len())
+
+	// Cached decision is returned for the same key.
+	err = manager.Authorize(&AccessRequest{
+		Subject:  testSubject,
+		Resource: testOtherResource,
+		Actions:  []string{updateAction},
+		CacheKey: "user-1:own",
+	})
+
+	assert.Nil(s.T(), err)
+
+	// Traced requests bypass the cache.
+	err = manager.Authorize(&AccessRequest{
+		Subject:  testSubject,
+		Resource: testOtherResource,
+		Actions:  []string{updateAction},
+		CacheKey: "user-1:own",
+		Trace:    true,
+	})
+
+	assert.IsType(s.T(), new(AccessDeniedError), err)
+
+	// Changing CombiningAlgorithm drops cached decisions.
+	manager.SetCombiningAlgorithm(DenyOverrides)
+
+	assert.Equal(s.T(), 0, manager.decisionCache.Len())
+}
+
+func (s *decisionCacheSuite) TestAuthorize_WithCacheAndConcurrentChange() {
+	testAdapter := new(storageAdapterMock)
+	testAdapter.On("LoadPolicy").Return(getBasicPolicy(), nil)
+
+	policyManager, _ := NewPolicyManager(testAdapter, false)
+	provider := &publishingPolicyProvider{PolicyManager: policyManager}
+
+	testRequest := &AccessRequest{
+		Subject:  UseSubject([]string{basicRoleOneName}),
+		Resource: UseResource(basicResourceOneName),
+		Actions:  []string{readAction},
+	}
+
+	revoke := func() {
+		assert.Nil(s.T(), policyManager.DeletePermission(basicRoleOneName, basicResourceOneName, readAction))
+	}
+
+	// Policy changes while the request is being evaluated - the decision is made with the previous policy,
+	// and it's never reused for the new one.
+	manager := NewAccessManager(provider)
+	manager.SetDecisionCache(NewDecisionCache(10, 0))
+
+	provider.change = revoke
+
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+	assert.IsType(s.T(), new(AccessDeniedError), manager.Authorize(testRequest))
+
+	// PolicyProviders not providing snapshots have the version read before the PolicyIndex.
+	assert.Nil(s.T(), policyManager.AddPermission(basicRoleOneName, basicResourceOneName, &Permission{Action: readAction}))
+
+	manager = NewAccessManager(&versionedPolicyProvider{
+		PolicyProvider:        policyManager,
+		PolicyIndexProvider:   provider,
+		PolicyVersionProvider: policyManager,
+	})
+	manager.SetDecisionCache(NewDecisionCache(10, 0))
+
+	provider.change = revoke
+
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+	assert.IsType(s.T(), new(AccessDeniedError), manager.Authorize(testRequest))
+}
@@ -0,0 +1,40 @@
+package expression
+
+import "fmt"
+
+// SyntaxError - returned when expression source cannot be parsed.
+type SyntaxError struct {
+	Position int
+	Message  string
+}
+
+// newSyntaxError - returns new SyntaxError instance.
+func newSyntaxError(position int, message string) *SyntaxError {
+	return &SyntaxError{
+		Position: position,
+		Message:  message,
+	}
+}
+
+// Error - error interface implementation.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at position %d: %s", e.Position, e.Message)
+}
+
+// EvaluationError - returned when parsed expression cannot be evaluated
+// with resolved values, for example due to operand types mismatch.
+type EvaluationError struct {
+	Message string
+}
+
+// newEvaluationError - returns new EvaluationError instance.
+func newEvaluationError(format string, args ...interface{}) *EvaluationError {
+	return &EvaluationError{
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// Error - error interface implementation.
+func (e *EvaluationError) Error() string {
+	return e.Message
+}
@@ -0,0 +1,161 @@
+package expression
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type expressionSuite struct {
+	suite.Suite
+
+	values map[string]interface{}
+}
+
+func TestExpressionSuite(t *testing.T) {
+	suite.Run(t, new(expressionSuite))
+}
+
+func (s *expressionSuite) SetupTest() {
+	s.values = map[string]interface{}{
+		"subject.ID":         "user-one",
+		"subject.TenantID":   "tenant-one",
+		"subject.Age":        int64(30),
+		"resource.TenantID":  "tenant-one",
+		"resource.Tags":      []string{"a", "b"},
+		"resource.Owner.ID":  "user-two",
+		"resource.Tags[0]":   "a",
+		"context.ip":         "10.0.0.1",
+		"context.archived":   false,
+		"context.limit":      100.0,
+		"context.missing":    nil,
+		"context.notABool":   "true",
+		"context.collection": map[string]int{"one": 1},
+	}
+}
+
+func (s *expressionSuite) resolver(path *Path) (interface{}, error) {
+	if path.Root == "error" {
+		return nil, errors.New("testError")
+	}
+
+	return s.values[path.String()], nil
+}
+
+func (s *expressionSuite) evaluate(source string) (bool, error) {
+	expression, err := Parse(source)
+	if err != nil {
+		return false, err
+	}
+
+	return expression.Evaluate(s.resolver)
+}
+
+func (s *expressionSuite) TestEvaluate() {
+	testCases := []struct {
+		source string
+		result bool
+	}{
+		{`true`, true},
+		{`!true`, false},
+		{`subject.TenantID == resource.TenantID && context.ip in ["10.0.0.1"]`, true},
+		{`subject.TenantID == resource.TenantID && context.ip in ["10.0.0.2"]`, false},
+		{`subject.ID != resource.Owner.ID`, true},
+		{`subject.ID == 'user-one' || error.Field`, true},
+		{`context.archived && error.Field`, false},
+		{`!context.archived`, true},
+		{`!(subject.Age > 18)`, false},
+		{`subject.Age >= 30 && subject.Age <= 30.0 && subject.Age < 31 && subject.Age > -1`, true},
+		{`subject.Age < context.limit`, true},
+		{`subject.Age in [10, 20, 30]`, true},
+		{`"b" in resource.Tags`, true},
+		{`resource.Tags[0] == "a"`, true},
+		{`"one" in context.collection`, true},
+		{`context.missing == null`, true},
+		{`context.missing == nil`, true},
+		{`"abc" < "abd"`, true},
+		{`1e2 == 100`, true},
+		{`(true || false) && !(false)`, true},
+		{`"a\"b" == 'a"b'`, true},
+		{`[1, 2] == [1, 2]`, true},
+	}
+
+	for _, testCase := range testCases {
+		result, err := s.evaluate(testCase.source)
+
+		assert.Nil(s.T(), err, testCase.source)
+		assert.Equal(s.T(), testCase.result, result, testCase.source)
+	}
+}
+
+func (s *expressionSuite) TestEvaluate_Errors() {
+	evaluationErrorSources := []string{
+		`subject.ID`,
+		`context.notABool && true`,
+		`!subject.ID`,
+		`subject.ID < 1`,
+		`subject.ID in subject.ID`,
+	}
+
+	for _, source := range evaluationErrorSources {
+		_, err := s.evaluate(source)
+
+		assert.IsType(s.T(), new(EvaluationError), err, source)
+	}
+
+	_, err := s.evaluate(`error.Field == 1`)
+
+	assert.EqualError(s.T(), err, "testError")
+}
+
+func (s *expressionSuite) TestParse_Errors() {
+	invalidSources := []string{
+		``,
+		`   `,
+		`subject.ID ==`,
+		`subject.ID = 1`,
+		`(true`,
+		`true)`,
+		`[1, 2`,
+		`"unterminated`,
+		`"\x"`,
+		`subject.ID # 1`,
+		`in`,
+		`1 == 1 == 1`,
+		`1.2.3 == 1`,
+	}
+
+	for _, source := range invalidSources {
+		_, err := Parse(source)
+
+		assert.IsType(s.T(), new(SyntaxError), err, source)
+	}
+
+	deeplyNested := ""
+
+	for i := 0; i < maxDepth+1; i++ {
+		deeplyNested += "!("
+	}
+
+	_, err := Parse(deeplyNested + "true")
+
+	assert.IsType(s.T(), new(SyntaxError), err)
+}
+
+func (s *expressionSuite) TestPaths() {
+	expression, err := Parse(`subject.ID == resource.Owner.ID || context.ip in [context.allowed, "x"]`)
+
+	assert.Nil(s.T(), err)
+
+	paths := expression.Paths()
+
+	assert.Equal(s.T(), 4, len(paths))
+	assert.Equal(s.T(), &Path{Root: "subject", Field: "ID"}, paths[0])
+	assert.Equal(s.T(), &Path{Root: "resource", Field: "Owner.ID"}, paths[1])
+	assert.Equal(s.T(), &Path{Root: "context", Field: "ip"}, paths[2])
+	assert.Equal(s.T(), &Path{Root: "context", Field: "allowed"}, paths[3])
+
+	assert.Equal(s.T(), `((subject.ID == resource.Owner.ID) || (context.ip in [context.allowed, "x"]))`, expression.Root().String())
+}
@@ -0,0 +1,204 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenKind - enum type for kinds of tokens produced by the lexer.
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenLeftBracket
+	tokenRightBracket
+	tokenComma
+)
+
+// token - single lexical token of an expression.
+type token struct {
+	kind     tokenKind
+	value    string
+	position int
+}
+
+// operators - supported operators, ordered so that longer ones are matched first.
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!"}
+
+// tokenize - splits expression source into tokens.
+func tokenize(source string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(source)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLeftParen, value: "(", position: i})
+			i++
+
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRightParen, value: ")", position: i})
+			i++
+
+		case r == '[':
+			tokens = append(tokens, token{kind: tokenLeftBracket, value: "[", position: i})
+			i++
+
+		case r == ']':
+			tokens = append(tokens, token{kind: tokenRightBracket, value: "]", position: i})
+			i++
+
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, value: ",", position: i})
+			i++
+
+		case r == '"' || r == '\'':
+			value, next, err := readString(runes, i)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, token{kind: tokenString, value: value, position: i})
+			i = next
+
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || strings.ContainsRune(".eE", runes[i]) ||
+				((runes[i] == '-' || runes[i] == '+') && (runes[i-1] == 'e' || runes[i-1] == 'E'))) {
+				i++
+			}
+
+			tokens = append(tokens, token{kind: tokenNumber, value: string(runes[start:i]), position: start})
+
+		case isIdentifierStart(r):
+			start := i
+			i = readPath(runes, i)
+
+			tokens = append(tokens, token{kind: tokenIdentifier, value: string(runes[start:i]), position: start})
+
+		default:
+			operator := matchOperator(runes, i)
+			if operator == "" {
+				return nil, newSyntaxError(i, fmt.Sprintf("unexpected character '%c'", r))
+			}
+
+			tokens = append(tokens, token{kind: tokenOperator, value: operator, position: i})
+			i += len(operator)
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, position: len(runes)})
+
+	return tokens, nil
+}
+
+// readString - reads quoted string literal starting at given position.
+// Returns unquoted value and the position right after closing quote.
+func readString(runes []rune, start int) (string, int, error) {
+	quote := runes[start]
+	builder := strings.Builder{}
+
+	for i := start + 1; i < len(runes); i++ {
+		r := runes[i]
+
+		if r == quote {
+			return builder.String(), i + 1, nil
+		}
+
+		if r == '\\' {
+			if i+1 >= len(runes) {
+				break
+			}
+
+			i++
+
+			switch runes[i] {
+			case 'n':
+				builder.WriteRune('\n')
+			case 't':
+				builder.WriteRune('\t')
+			case '\\', '"', '\'':
+				builder.WriteRune(runes[i])
+			default:
+				return "", 0, newSyntaxError(i, fmt.Sprintf("unknown escape sequence '\\%c'", runes[i]))
+			}
+
+			continue
+		}
+
+		builder.WriteRune(r)
+	}
+
+	return "", 0, newSyntaxError(start, "unterminated string")
+}
+
+// readPath - reads dotted path (e.g. "subject.Owner.Tags[0]"), starting at given position.
+// Returns the position right after the path.
+func readPath(runes []rune, start int) int {
+	i := start
+
+	for i < len(runes) {
+		r := runes[i]
+
+		switch {
+		case isIdentifierPart(r):
+			i++
+		case r == '.' && i+1 < len(runes) && isIdentifierStart(runes[i+1]):
+			i++
+		case r == '[' && i > start && (isIdentifierPart(runes[i-1]) || runes[i-1] == ']') && !isKeyword(string(runes[start:i])):
+			// Index is a part of the path only if it directly follows an identifier
+			// and contains digits only - otherwise it's a list literal.
+			end := i + 1
+
+			for end < len(runes) && unicode.IsDigit(runes[end]) {
+				end++
+			}
+
+			if end == i+1 || end >= len(runes) || runes[end] != ']' {
+				return i
+			}
+
+			i = end + 1
+		default:
+			return i
+		}
+	}
+
+	return i
+}
+
+// matchOperator - returns an operator starting at given position, or empty string
+// if there is none.
+func matchOperator(runes []rune, start int) string {
+	for _, operator := range operators {
+		end := start + len(operator)
+
+		if end <= len(runes) && string(runes[start:end]) == operator {
+			return operator
+		}
+	}
+
+	return ""
+}
+
+func isIdentifierStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentifierPart(r rune) bool {
+	return isIdentifierStart(r) || unicode.IsDigit(r)
+}
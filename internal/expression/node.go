@@ -0,0 +1,246 @@
+package expression
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/el-mike/restrict/v2/internal/utils"
+)
+
+// Resolver - function resolving a value for given Path node.
+type Resolver func(path *Path) (interface{}, error)
+
+// Node - single node of parsed expression's tree.
+type Node interface {
+	// Evaluate - returns node's value, using resolver to get values of Paths.
+	Evaluate(resolver Resolver) (interface{}, error)
+
+	// String - Stringer implementation, returns node in its source form.
+	String() string
+}
+
+// Literal - node describing constant value (string, number, bool or nil).
+type Literal struct {
+	Value interface{}
+}
+
+// Evaluate - Node interface implementation.
+func (n *Literal) Evaluate(_ Resolver) (interface{}, error) {
+	return n.Value, nil
+}
+
+// String - Stringer implementation.
+func (n *Literal) String() string {
+	switch value := n.Value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("%q", value)
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+// Path - node describing a value taken from one of the roots, e.g. "subject.ID".
+type Path struct {
+	// Root - first segment of the path, e.g. "subject".
+	Root string
+	// Field - rest of the path, e.g. "ID".
+	Field string
+}
+
+// Evaluate - Node interface implementation.
+func (n *Path) Evaluate(resolver Resolver) (interface{}, error) {
+	return resolver(n)
+}
+
+// String - Stringer implementation.
+func (n *Path) String() string {
+	if n.Field == "" {
+		return n.Root
+	}
+
+	return n.Root + "." + n.Field
+}
+
+// List - node describing list literal, e.g. ["a", "b"].
+type List struct {
+	Items []Node
+}
+
+// Evaluate - Node interface implementation.
+func (n *List) Evaluate(resolver Resolver) (interface{}, error) {
+	values := []interface{}{}
+
+	for _, item := range n.Items {
+		value, err := item.Evaluate(resolver)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// String - Stringer implementation.
+func (n *List) String() string {
+	items := []string{}
+
+	for _, item := range n.Items {
+		items = append(items, item.String())
+	}
+
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// Unary - node describing unary operation (negation).
+type Unary struct {
+	Operator string
+	Operand  Node
+}
+
+// Evaluate - Node interface implementation.
+func (n *Unary) Evaluate(resolver Resolver) (interface{}, error) {
+	value, err := n.Operand.Evaluate(resolver)
+	if err != nil {
+		return nil, err
+	}
+
+	boolValue, ok := value.(bool)
+	if !ok {
+		return nil, newEvaluationError("operand of \"%s\" has to be a boolean, got \"%v\"", n.Operator, value)
+	}
+
+	return !boolValue, nil
+}
+
+// String - Stringer implementation.
+func (n *Unary) String() string {
+	return n.Operator + n.Operand.String()
+}
+
+// Binary - node describing binary operation (logical, comparison or membership).
+type Binary struct {
+	Operator string
+	Left     Node
+	Right    Node
+}
+
+// Evaluate - Node interface implementation.
+func (n *Binary) Evaluate(resolver Resolver) (interface{}, error) {
+	if n.Operator == "&&" || n.Operator == "||" {
+		return n.evaluateLogical(resolver)
+	}
+
+	left, err := n.Left.Evaluate(resolver)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := n.Right.Evaluate(resolver)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.Operator {
+	case "==":
+		return Equal(left, right), nil
+	case "!=":
+		return !Equal(left, right), nil
+	case "in":
+		if !utils.IsCollection(right) {
+			return nil, newEvaluationError("right operand of \"in\" has to be a collection, got \"%v\"", right)
+		}
+
+		for _, element := range utils.GetCollectionElements(right) {
+			if Equal(left, element) {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	default:
+		result, err := compare(left, right)
+		if err != nil {
+			return nil, err
+		}
+
+		switch n.Operator {
+		case "<":
+			return result < 0, nil
+		case "<=":
+			return result <= 0, nil
+		case ">":
+			return result > 0, nil
+		default:
+			return result >= 0, nil
+		}
+	}
+}
+
+// evaluateLogical - evaluates logical operators, short-circuiting when possible.
+func (n *Binary) evaluateLogical(resolver Resolver) (interface{}, error) {
+	left, err := evaluateBool(n.Left, n.Operator, resolver)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.Operator == "&&" && !left {
+		return false, nil
+	}
+
+	if n.Operator == "||" && left {
+		return true, nil
+	}
+
+	return evaluateBool(n.Right, n.Operator, resolver)
+}
+
+// String - Stringer implementation.
+func (n *Binary) String() string {
+	return fmt.Sprintf("(%s %s %s)", n.Left.String(), n.Operator, n.Right.String())
+}
+
+// Equal - returns true if values are equal. Numbers are compared by value regardless
+// of their kinds, other values are compared with reflect.DeepEqual.
+func Equal(a, b interface{}) bool {
+	if result, ok := utils.CompareNumbers(a, b); ok {
+		return result == 0
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// compare - compares two numbers or two strings.
+func compare(a, b interface{}) (int, error) {
+	if result, ok := utils.CompareNumbers(a, b); ok {
+		return result, nil
+	}
+
+	aString, aOk := a.(string)
+	bString, bOk := b.(string)
+
+	if aOk && bOk {
+		return strings.Compare(aString, bString), nil
+	}
+
+	return 0, newEvaluationError("values \"%v\" and \"%v\" are not comparable", a, b)
+}
+
+// evaluateBool - evaluates a node and ensures the result is a boolean.
+func evaluateBool(node Node, operator string, resolver Resolver) (bool, error) {
+	value, err := node.Evaluate(resolver)
+	if err != nil {
+		return false, err
+	}
+
+	boolValue, ok := value.(bool)
+	if !ok {
+		return false, newEvaluationError("operands of \"%s\" have to be booleans, got \"%v\"", operator, value)
+	}
+
+	return boolValue, nil
+}
@@ -0,0 +1,350 @@
+// Package expression implements a small, sandboxed boolean expression language.
+// Expressions can only compare values, test membership and combine the results
+// with logical operators - there are no function calls, assignments or loops.
+package expression
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxDepth - maximum nesting depth of parsed expression.
+const maxDepth = 64
+
+// keywords - identifiers with special meaning, that cannot be used as paths.
+var keywords = map[string]interface{}{
+	"true":  true,
+	"false": false,
+	"null":  nil,
+	"nil":   nil,
+	"in":    nil,
+}
+
+// Expression - parsed expression, ready to be evaluated.
+type Expression struct {
+	source string
+	root   Node
+}
+
+// Parse - parses given source into an Expression.
+func Parse(source string) (*Expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	if p.peek().kind == tokenEOF {
+		return nil, newSyntaxError(0, "expression cannot be empty")
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, newSyntaxError(next.position, fmt.Sprintf("unexpected token \"%s\"", next.value))
+	}
+
+	return &Expression{
+		source: source,
+		root:   root,
+	}, nil
+}
+
+// Source - returns expression's source.
+func (e *Expression) Source() string {
+	return e.source
+}
+
+// Root - returns root Node of the expression's tree.
+func (e *Expression) Root() Node {
+	return e.root
+}
+
+// Paths - returns all Path nodes used in the expression.
+func (e *Expression) Paths() []*Path {
+	paths := []*Path{}
+
+	Walk(e.root, func(node Node) {
+		if path, ok := node.(*Path); ok {
+			paths = append(paths, path)
+		}
+	})
+
+	return paths
+}
+
+// Evaluate - evaluates the expression using resolver to get Paths' values.
+// Returns an EvaluationError if the expression does not evaluate to a boolean.
+func (e *Expression) Evaluate(resolver Resolver) (bool, error) {
+	value, err := e.root.Evaluate(resolver)
+	if err != nil {
+		return false, err
+	}
+
+	result, ok := value.(bool)
+	if !ok {
+		return false, newEvaluationError("expression has to evaluate to a boolean, got \"%v\"", value)
+	}
+
+	return result, nil
+}
+
+// Walk - calls visit for given node and all of its descendants.
+func Walk(node Node, visit func(node Node)) {
+	visit(node)
+
+	switch n := node.(type) {
+	case *List:
+		for _, item := range n.Items {
+			Walk(item, visit)
+		}
+	case *Unary:
+		Walk(n.Operand, visit)
+	case *Binary:
+		Walk(n.Left, visit)
+		Walk(n.Right, visit)
+	}
+}
+
+// isKeyword - returns true if given identifier is a keyword.
+func isKeyword(identifier string) bool {
+	_, ok := keywords[identifier]
+
+	return ok
+}
+
+// parser - recursive descent parser, with following grammar:
+//
+//	or         = and { "||" and }
+//	and        = unary { "&&" unary }
+//	unary      = "!" unary | comparison
+//	comparison = primary [ ( "==" | "!=" | "<" | "<=" | ">" | ">=" | "in" ) primary ]
+//	primary    = literal | path | list | "(" or ")"
+//	list       = "[" [ or { "," or } ] "]"
+type parser struct {
+	tokens   []token
+	position int
+	depth    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.position]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.position]
+
+	if t.kind != tokenEOF {
+		p.position++
+	}
+
+	return t
+}
+
+func (p *parser) isOperator(operators ...string) bool {
+	t := p.peek()
+
+	for _, operator := range operators {
+		if (t.kind == tokenOperator || t.kind == tokenIdentifier) && t.value == operator {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *parser) enter(position int) error {
+	p.depth++
+
+	if p.depth > maxDepth {
+		return newSyntaxError(position, "expression is nested too deeply")
+	}
+
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+func (p *parser) parseOr() (Node, error) {
+	return p.parseBinary([]string{"||"}, p.parseAnd)
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	return p.parseBinary([]string{"&&"}, p.parseUnary)
+}
+
+func (p *parser) parseBinary(operators []string, parseOperand func() (Node, error)) (Node, error) {
+	left, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOperator(operators...) {
+		operator := p.next().value
+
+		right, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Binary{Operator: operator, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if !p.isOperator("!") {
+		return p.parseComparison()
+	}
+
+	t := p.next()
+
+	if err := p.enter(t.position); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Unary{Operator: t.value, Operand: operand}, nil
+}
+
+func (p *parser) parseComparison() (Node, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.isOperator("==", "!=", "<", "<=", ">", ">=", "in") {
+		operator := p.next().value
+
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &Binary{Operator: operator, Left: left, Right: right}, nil
+	}
+
+	return left, nil
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenString:
+		return &Literal{Value: t.value}, nil
+
+	case tokenNumber:
+		return parseNumber(t)
+
+	case tokenIdentifier:
+		if value, ok := keywords[t.value]; ok {
+			if t.value == "in" {
+				return nil, newSyntaxError(t.position, "unexpected keyword \"in\"")
+			}
+
+			return &Literal{Value: value}, nil
+		}
+
+		return parsePath(t), nil
+
+	case tokenLeftParen:
+		if err := p.enter(t.position); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if closing := p.next(); closing.kind != tokenRightParen {
+			return nil, newSyntaxError(closing.position, "expected \")\"")
+		}
+
+		return node, nil
+
+	case tokenLeftBracket:
+		if err := p.enter(t.position); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+
+		return p.parseList()
+
+	case tokenEOF:
+		return nil, newSyntaxError(t.position, "unexpected end of expression")
+
+	default:
+		return nil, newSyntaxError(t.position, fmt.Sprintf("unexpected token \"%s\"", t.value))
+	}
+}
+
+func (p *parser) parseList() (Node, error) {
+	list := &List{Items: []Node{}}
+
+	if p.peek().kind == tokenRightBracket {
+		p.next()
+		return list, nil
+	}
+
+	for {
+		item, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		list.Items = append(list.Items, item)
+
+		t := p.next()
+
+		if t.kind == tokenRightBracket {
+			return list, nil
+		}
+
+		if t.kind != tokenComma {
+			return nil, newSyntaxError(t.position, "expected \",\" or \"]\"")
+		}
+	}
+}
+
+func parseNumber(t token) (Node, error) {
+	if !strings.ContainsAny(t.value, ".eE") {
+		if value, err := strconv.Atoi(t.value); err == nil {
+			return &Literal{Value: value}, nil
+		}
+	}
+
+	value, err := strconv.ParseFloat(t.value, 64)
+	if err != nil {
+		return nil, newSyntaxError(t.position, fmt.Sprintf("invalid number \"%s\"", t.value))
+	}
+
+	return &Literal{Value: value}, nil
+}
+
+func parsePath(t token) *Path {
+	parts := strings.SplitN(t.value, ".", 2)
+
+	path := &Path{Root: parts[0]}
+
+	if len(parts) > 1 {
+		path.Field = parts[1]
+	}
+
+	return path
+}
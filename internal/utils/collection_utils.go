@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"reflect"
+)
+
+// IsCollection - returns true if argument is a slice, an array or a map, false otherwise.
+func IsCollection(value interface{}) bool {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+
+	return false
+}
+
+// GetCollectionElements - returns elements of passed slice or array, or keys of passed map.
+// Returns nil if argument is not a collection.
+func GetCollectionElements(collection interface{}) []interface{} {
+	rCollection := reflect.ValueOf(collection)
+
+	switch rCollection.Kind() {
+	case reflect.Slice, reflect.Array:
+		elements := make([]interface{}, 0, rCollection.Len())
+
+		for i := 0; i < rCollection.Len(); i++ {
+			elements = append(elements, rCollection.Index(i).Interface())
+		}
+
+		return elements
+	case reflect.Map:
+		elements := make([]interface{}, 0, rCollection.Len())
+
+		for _, key := range rCollection.MapKeys() {
+			elements = append(elements, key.Interface())
+		}
+
+		return elements
+	}
+
+	return nil
+}
+
+// CollectionContains - returns true if passed collection (slice, array or map's keys) contains
+// an element deeply equal to target, false otherwise.
+func CollectionContains(collection interface{}, target interface{}) bool {
+	for _, element := range GetCollectionElements(collection) {
+		if reflect.DeepEqual(element, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CollectionsIntersect - returns true if passed collections have at least one common element,
+// false otherwise.
+func CollectionsIntersect(a, b interface{}) bool {
+	for _, element := range GetCollectionElements(a) {
+		if CollectionContains(b, element) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CopyCollection - returns a deep copy of passed slice or map, including nested slices and maps.
+// Other values (including arrays, which are copied by value anyway) are returned as they are.
+func CopyCollection(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	return copyValue(reflect.ValueOf(value)).Interface()
+}
+
+// copyValue - returns a deep copy of given slice or map value, or the value itself otherwise.
+func copyValue(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+
+		result := reflect.New(value.Type()).Elem()
+		result.Set(copyValue(value.Elem()))
+
+		return result
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+
+		result := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+
+		for i := 0; i < value.Len(); i++ {
+			result.Index(i).Set(copyValue(value.Index(i)))
+		}
+
+		return result
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+
+		result := reflect.MakeMapWithSize(value.Type(), value.Len())
+
+		for _, key := range value.MapKeys() {
+			result.SetMapIndex(key, copyValue(value.MapIndex(key)))
+		}
+
+		return result
+	}
+
+	return value
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type collectionUtilsSuite struct {
+	suite.Suite
+}
+
+func TestCollectionUtilsSuite(t *testing.T) {
+	suite.Run(t, new(collectionUtilsSuite))
+}
+
+func (s *collectionUtilsSuite) TestIsCollection() {
+	assert.True(s.T(), IsCollection([]int{}))
+	assert.True(s.T(), IsCollection([2]int{}))
+	assert.True(s.T(), IsCollection(map[string]int{}))
+
+	assert.False(s.T(), IsCollection(nil))
+	assert.False(s.T(), IsCollection(1))
+	assert.False(s.T(), IsCollection("test"))
+	assert.False(s.T(), IsCollection(&[]int{}))
+}
+
+func (s *collectionUtilsSuite) TestGetCollectionElements() {
+	assert.Equal(s.T(), []interface{}{1, 2}, GetCollectionElements([]int{1, 2}))
+	assert.Equal(s.T(), []interface{}{"a", "b"}, GetCollectionElements([2]string{"a", "b"}))
+	assert.Equal(s.T(), []interface{}{"key"}, GetCollectionElements(map[string]int{"key": 1}))
+	assert.Equal(s.T(), []interface{}{}, GetCollectionElements([]int(nil)))
+
+	assert.Nil(s.T(), GetCollectionElements(1))
+	assert.Nil(s.T(), GetCollectionElements(nil))
+}
+
+func (s *collectionUtilsSuite) TestCollectionContains() {
+	assert.True(s.T(), CollectionContains([]string{"one", "two"}, "two"))
+	assert.True(s.T(), CollectionContains([]interface{}{1, "two"}, "two"))
+	assert.True(s.T(), CollectionContains(map[string]bool{"one": true}, "one"))
+	assert.True(s.T(), CollectionContains([][]int{{1, 2}}, []int{1, 2}))
+
+	assert.False(s.T(), CollectionContains([]string{"one", "two"}, "three"))
+	assert.False(s.T(), CollectionContains([]int64{1}, 1))
+	assert.False(s.T(), CollectionContains("one", "one"))
+}
+
+func (s *collectionUtilsSuite) TestCollectionsIntersect() {
+	assert.True(s.T(), CollectionsIntersect([]string{"one", "two"}, []string{"two", "three"}))
+	assert.True(s.T(), CollectionsIntersect([]string{"one"}, map[string]int{"one": 1}))
+
+	assert.False(s.T(), CollectionsIntersect([]string{"one"}, []string{"two"}))
+	assert.False(s.T(), CollectionsIntersect([]string{}, []string{"two"}))
+	assert.False(s.T(), CollectionsIntersect(1, []int{1}))
+}
+
+func (s *collectionUtilsSuite) TestCopyCollection() {
+	nested := []interface{}{"a", []interface{}{"b"}, map[string]interface{}{"c": []string{"d"}}}
+
+	copied := CopyCollection(nested).([]interface{})
+
+	assert.Equal(s.T(), nested, copied)
+
+	copied[0] = "changed"
+	copied[1].([]interface{})[0] = "changed"
+	copied[2].(map[string]interface{})["c"].([]string)[0] = "changed"
+
+	assert.Equal(s.T(), []interface{}{"a", []interface{}{"b"}, map[string]interface{}{"c": []string{"d"}}}, nested)
+
+	assert.Equal(s.T(), []int(nil), CopyCollection([]int(nil)))
+	assert.Equal(s.T(), 1, CopyCollection(1))
+	assert.Equal(s.T(), "test", CopyCollection("test"))
+	assert.Nil(s.T(), CopyCollection(nil))
+}
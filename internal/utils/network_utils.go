@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// GetIP - returns net.IP value of passed argument. Accepts net.IP and strings
+// containing IPv4 or IPv6 address. Second return value is false if the argument
+// cannot be converted.
+func GetIP(value interface{}) (net.IP, bool) {
+	if ip, ok := value.(net.IP); ok {
+		return ip, len(ip) == net.IPv4len || len(ip) == net.IPv6len
+	}
+
+	stringValue, ok := GetStringValue(value)
+	if !ok {
+		return nil, false
+	}
+
+	ip := net.ParseIP(strings.TrimSpace(stringValue))
+
+	return ip, ip != nil
+}
+
+// GetNetworks - returns a list of networks described by passed argument, which can be
+// a single network or a collection of networks. Networks can be defined as net.IPNet,
+// *net.IPNet or strings in CIDR notation - strings containing a single address are
+// treated as single-host networks. Returns an error if any of the networks is not valid.
+func GetNetworks(value interface{}) ([]*net.IPNet, error) {
+	if !IsCollection(value) {
+		network, err := getNetwork(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return []*net.IPNet{network}, nil
+	}
+
+	elements := GetCollectionElements(value)
+	networks := make([]*net.IPNet, 0, len(elements))
+
+	for _, element := range elements {
+		network, err := getNetwork(element)
+		if err != nil {
+			return nil, err
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+// getNetwork - converts a single value into *net.IPNet.
+func getNetwork(value interface{}) (*net.IPNet, error) {
+	switch typedValue := value.(type) {
+	case net.IPNet:
+		return &typedValue, nil
+	case *net.IPNet:
+		if typedValue == nil {
+			return nil, fmt.Errorf("network cannot be nil")
+		}
+
+		return typedValue, nil
+	}
+
+	stringValue, ok := GetStringValue(value)
+	if !ok {
+		return nil, fmt.Errorf("value \"%v\" is not a network", value)
+	}
+
+	stringValue = strings.TrimSpace(stringValue)
+
+	if !strings.Contains(stringValue, "/") {
+		ip := net.ParseIP(stringValue)
+		if ip == nil {
+			return nil, fmt.Errorf("value \"%s\" is not a valid IP address", stringValue)
+		}
+
+		if ipv4 := ip.To4(); ipv4 != nil {
+			return &net.IPNet{IP: ipv4, Mask: net.CIDRMask(8*net.IPv4len, 8*net.IPv4len)}, nil
+		}
+
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(8*net.IPv6len, 8*net.IPv6len)}, nil
+	}
+
+	_, network, err := net.ParseCIDR(stringValue)
+	if err != nil {
+		return nil, fmt.Errorf("value \"%s\" is not a valid CIDR block", stringValue)
+	}
+
+	return network, nil
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type networkUtilsSuite struct {
+	suite.Suite
+}
+
+func TestNetworkUtilsSuite(t *testing.T) {
+	suite.Run(t, new(networkUtilsSuite))
+}
+
+func (s *networkUtilsSuite) TestGetIP() {
+	ip, ok := GetIP("10.0.0.1")
+
+	assert.True(s.T(), ok)
+	assert.True(s.T(), ip.Equal(net.IPv4(10, 0, 0, 1)))
+
+	ip, ok = GetIP("2001:db8::1")
+
+	assert.True(s.T(), ok)
+	assert.True(s.T(), ip.Equal(net.ParseIP("2001:db8::1")))
+
+	ip, ok = GetIP(net.IPv4(10, 0, 0, 1))
+
+	assert.True(s.T(), ok)
+	assert.True(s.T(), ip.Equal(net.IPv4(10, 0, 0, 1)))
+
+	_, ok = GetIP("10.0.0.256")
+	assert.False(s.T(), ok)
+
+	_, ok = GetIP(net.IP{1, 2})
+	assert.False(s.T(), ok)
+
+	_, ok = GetIP(1)
+	assert.False(s.T(), ok)
+
+	_, ok = GetIP(nil)
+	assert.False(s.T(), ok)
+}
+
+func (s *networkUtilsSuite) TestGetNetworks() {
+	networks, err := GetNetworks("10.0.0.0/8")
+
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), networks, 1)
+	assert.Equal(s.T(), "10.0.0.0/8", networks[0].String())
+
+	networks, err = GetNetworks([]string{"192.168.0.0/16", "2001:db8::/32", "10.0.0.1", "::1"})
+
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), networks, 4)
+	assert.Equal(s.T(), "192.168.0.0/16", networks[0].String())
+	assert.Equal(s.T(), "2001:db8::/32", networks[1].String())
+	assert.Equal(s.T(), "10.0.0.1/32", networks[2].String())
+	assert.Equal(s.T(), "::1/128", networks[3].String())
+
+	networks, err = GetNetworks([]interface{}{"10.0.0.0/8"})
+
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), networks, 1)
+
+	_, testNetwork, _ := net.ParseCIDR("172.16.0.0/12")
+
+	networks, err = GetNetworks(testNetwork)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []*net.IPNet{testNetwork}, networks)
+
+	networks, err = GetNetworks([]net.IPNet{*testNetwork})
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), testNetwork.String(), networks[0].String())
+
+	networks, err = GetNetworks([]string{})
+
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), networks, 0)
+
+	invalidValues := []interface{}{
+		"10.0.0.0/33",
+		"10.0.0",
+		[]string{"10.0.0.0/8", "invalid"},
+		[]int{1},
+		1,
+		nil,
+	}
+
+	for _, value := range invalidValues {
+		_, err = GetNetworks(value)
+
+		assert.NotNil(s.T(), err, "%v", value)
+	}
+}
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"math"
+	"reflect"
+)
+
+// IsNumeric - returns true if argument is any of int, uint or float kinds, false otherwise.
+func IsNumeric(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+// CompareNumbers - compares two numeric values of any int, uint or float kinds.
+// Returns -1 if a < b, 0 if a == b and 1 if a > b. Second return value is false
+// when any of the arguments is not a number (or is NaN), in which case the result
+// should be ignored.
+func CompareNumbers(a, b interface{}) (int, bool) {
+	if !IsNumeric(a) || !IsNumeric(b) {
+		return 0, false
+	}
+
+	rA := reflect.ValueOf(a)
+	rB := reflect.ValueOf(b)
+
+	// If any of the values is a float, both are compared as floats.
+	if isFloatKind(rA.Kind()) || isFloatKind(rB.Kind()) {
+		fA := toFloat(rA)
+		fB := toFloat(rB)
+
+		if math.IsNaN(fA) || math.IsNaN(fB) {
+			return 0, false
+		}
+
+		return compareFloats(fA, fB), true
+	}
+
+	aUnsigned := isUintKind(rA.Kind())
+	bUnsigned := isUintKind(rB.Kind())
+
+	switch {
+	case !aUnsigned && !bUnsigned:
+		return compareInts(rA.Int(), rB.Int()), true
+	case aUnsigned && bUnsigned:
+		return compareUints(rA.Uint(), rB.Uint()), true
+	case aUnsigned:
+		// Negative int is always smaller than any uint.
+		if rB.Int() < 0 {
+			return 1, true
+		}
+
+		return compareUints(rA.Uint(), uint64(rB.Int())), true
+	default:
+		if rA.Int() < 0 {
+			return -1, true
+		}
+
+		return compareUints(uint64(rA.Int()), rB.Uint()), true
+	}
+}
+
+func isFloatKind(kind reflect.Kind) bool {
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
+
+func isUintKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+
+	return false
+}
+
+func toFloat(value reflect.Value) float64 {
+	switch {
+	case isFloatKind(value.Kind()):
+		return value.Float()
+	case isUintKind(value.Kind()):
+		return float64(value.Uint())
+	default:
+		return float64(value.Int())
+	}
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareUints(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type numericUtilsSuite struct {
+	suite.Suite
+}
+
+func TestNumericUtilsSuite(t *testing.T) {
+	suite.Run(t, new(numericUtilsSuite))
+}
+
+func (s *numericUtilsSuite) TestIsNumeric() {
+	assert.True(s.T(), IsNumeric(1))
+	assert.True(s.T(), IsNumeric(int8(1)))
+	assert.True(s.T(), IsNumeric(uint64(1)))
+	assert.True(s.T(), IsNumeric(1.5))
+	assert.True(s.T(), IsNumeric(float32(1.5)))
+
+	assert.False(s.T(), IsNumeric(nil))
+	assert.False(s.T(), IsNumeric("1"))
+	assert.False(s.T(), IsNumeric(true))
+	assert.False(s.T(), IsNumeric([]int{1}))
+}
+
+func (s *numericUtilsSuite) TestCompareNumbers() {
+	result, ok := CompareNumbers(1, 2)
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), -1, result)
+
+	result, ok = CompareNumbers(2, 2)
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), 0, result)
+
+	result, ok = CompareNumbers(int8(3), int64(2))
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), 1, result)
+
+	// Mixed int and uint kinds.
+	result, ok = CompareNumbers(-1, uint(1))
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), -1, result)
+
+	result, ok = CompareNumbers(uint(1), -1)
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), 1, result)
+
+	result, ok = CompareNumbers(uint64(math.MaxUint64), math.MaxInt64)
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), 1, result)
+
+	result, ok = CompareNumbers(uint8(5), 5)
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), 0, result)
+
+	// Mixed int and float kinds.
+	result, ok = CompareNumbers(1, 1.5)
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), -1, result)
+
+	result, ok = CompareNumbers(float32(2), uint(2))
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), 0, result)
+
+	// Not a number.
+	_, ok = CompareNumbers("1", 1)
+	assert.False(s.T(), ok)
+
+	_, ok = CompareNumbers(1, nil)
+	assert.False(s.T(), ok)
+
+	_, ok = CompareNumbers(math.NaN(), 1)
+	assert.False(s.T(), ok)
+}
@@ -0,0 +1,230 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// AttributeProvider - interface for values providing their attributes by name, used
+// instead of reflection when resolving paths.
+type AttributeProvider interface {
+	GetAttribute(name string) (interface{}, bool)
+}
+
+// PathSegment - single segment of a field path - either a field (or map key) name, or an index.
+type PathSegment struct {
+	// Name - field or map key name, empty for index segments.
+	Name string
+	// Index - slice or array index, used when IsIndex is true.
+	Index int
+	// IsIndex - true if the segment is an index (e.g. "[0]").
+	IsIndex bool
+}
+
+// String - Stringer implementation.
+func (s PathSegment) String() string {
+	if s.IsIndex {
+		return fmt.Sprintf("[%d]", s.Index)
+	}
+
+	return s.Name
+}
+
+// PathError - returned when a field path is malformed, or cannot be resolved.
+type PathError struct {
+	// Path - the whole path being resolved.
+	Path string
+	// Segment - the segment that failed.
+	Segment string
+	// Reason - description of the failure.
+	Reason string
+}
+
+// Error - error interface implementation.
+func (e *PathError) Error() string {
+	return fmt.Sprintf("segment \"%s\" of path \"%s\" could not be resolved: %s", e.Segment, e.Path, e.Reason)
+}
+
+// ParsePath - parses a field path, e.g. "Owner.Team.ID", "Tags[0]" or "Items[1].Name",
+// into a list of segments.
+func ParsePath(path string) ([]PathSegment, error) {
+	if path == "" {
+		return nil, &PathError{Path: path, Reason: "path cannot be empty"}
+	}
+
+	segments := []PathSegment{}
+	position := 0
+
+	for position < len(path) {
+		switch path[position] {
+		case '[':
+			end := strings.IndexByte(path[position:], ']')
+			if end < 0 {
+				return nil, &PathError{Path: path, Segment: path[position:], Reason: "unclosed index"}
+			}
+
+			rawIndex := path[position+1 : position+end]
+
+			index, err := strconv.Atoi(rawIndex)
+			if err != nil || index < 0 {
+				return nil, &PathError{Path: path, Segment: path[position : position+end+1], Reason: "index has to be a non-negative integer"}
+			}
+
+			segments = append(segments, PathSegment{Index: index, IsIndex: true})
+			position += end + 1
+
+			// Index can be followed only by another index or a dot.
+			if position < len(path) && path[position] == '.' {
+				position++
+
+				if position == len(path) {
+					return nil, &PathError{Path: path, Reason: "path cannot end with a dot"}
+				}
+			}
+		case '.':
+			return nil, &PathError{Path: path, Reason: fmt.Sprintf("unexpected dot at position %d", position)}
+		default:
+			end := position
+
+			for end < len(path) && path[end] != '.' && path[end] != '[' {
+				end++
+			}
+
+			segments = append(segments, PathSegment{Name: path[position:end]})
+			position = end
+
+			if position < len(path) && path[position] == '.' {
+				position++
+
+				if position == len(path) {
+					return nil, &PathError{Path: path, Reason: "path cannot end with a dot"}
+				}
+			}
+		}
+	}
+
+	return segments, nil
+}
+
+// GetPathValue - returns a value under given field path in passed source, walking through
+// pointers, interfaces, structs, maps, slices and arrays. Values implementing AttributeProvider
+// are asked for their attributes, and struct members are resolved with GetStructMember.
+// If source is a map (or AttributeProvider) containing the whole path as a key, the value
+// under that key is returned. Missing key in the last map returns nil, while any other
+// unresolvable segment returns PathError.
+func GetPathValue(source interface{}, path string) (interface{}, error) {
+	if provider, ok := source.(AttributeProvider); ok && !IsNilPointer(source) {
+		if value, found := provider.GetAttribute(path); found {
+			return value, nil
+		}
+	}
+
+	if IsMap(source) {
+		rSource := reflect.ValueOf(source)
+
+		if rSource.Type().Key().Kind() == reflect.String {
+			value := rSource.MapIndex(reflect.ValueOf(path).Convert(rSource.Type().Key()))
+
+			if value.IsValid() {
+				return value.Interface(), nil
+			}
+		}
+	}
+
+	segments, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := reflect.ValueOf(source)
+
+	for i, segment := range segments {
+		isLast := i == len(segments)-1
+
+		newError := func(reason string) error {
+			return &PathError{Path: path, Segment: segment.String(), Reason: reason}
+		}
+
+		if !segment.IsIndex && current.IsValid() && current.CanInterface() {
+			if provider, ok := current.Interface().(AttributeProvider); ok && !IsNilPointer(current.Interface()) {
+				value, found := provider.GetAttribute(segment.Name)
+				if !found {
+					return nil, newError("attribute is not provided")
+				}
+
+				current = reflect.ValueOf(value)
+				continue
+			}
+		}
+
+		// Last pointer is kept, so getters with pointer receivers can be called.
+		var pointer reflect.Value
+
+		for current.IsValid() && (current.Kind() == reflect.Ptr || current.Kind() == reflect.Interface) {
+			if current.IsNil() {
+				return nil, newError("parent value is nil")
+			}
+
+			if current.Kind() == reflect.Ptr {
+				pointer = current
+			}
+
+			current = current.Elem()
+		}
+
+		if !current.IsValid() {
+			return nil, newError("parent value is nil")
+		}
+
+		if segment.IsIndex {
+			if current.Kind() != reflect.Slice && current.Kind() != reflect.Array {
+				return nil, newError(fmt.Sprintf("parent value of type \"%s\" is not a slice or an array", current.Type()))
+			}
+
+			if segment.Index >= current.Len() {
+				return nil, newError(fmt.Sprintf("index out of range with length %d", current.Len()))
+			}
+
+			current = current.Index(segment.Index)
+			continue
+		}
+
+		switch current.Kind() {
+		case reflect.Struct:
+			value, err := GetStructMember(current, pointer, segment.Name)
+			if err != nil {
+				return nil, newError(err.Error())
+			}
+
+			current = value
+		case reflect.Map:
+			keyType := current.Type().Key()
+
+			if keyType.Kind() != reflect.String {
+				return nil, newError(fmt.Sprintf("map with keys of type \"%s\" cannot be accessed by name", keyType))
+			}
+
+			value := current.MapIndex(reflect.ValueOf(segment.Name).Convert(keyType))
+
+			if !value.IsValid() {
+				if isLast {
+					return nil, nil
+				}
+
+				return nil, newError("key does not exist")
+			}
+
+			current = value
+		default:
+			return nil, newError(fmt.Sprintf("parent value of type \"%s\" is not a struct or a map", current.Type()))
+		}
+	}
+
+	if !current.IsValid() || !current.CanInterface() {
+		return nil, nil
+	}
+
+	return current.Interface(), nil
+}
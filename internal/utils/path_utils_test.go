@@ -0,0 +1,280 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type pathTeam struct {
+	ID string
+}
+
+type pathOwner struct {
+	Team  *pathTeam
+	Roles []string
+}
+
+type pathResource struct {
+	Owner    pathOwner
+	Tags     []string
+	Matrix   [][]int
+	Meta     map[string]interface{}
+	Any      interface{}
+	NilOwner *pathOwner
+}
+
+type pathUtilsSuite struct {
+	suite.Suite
+}
+
+func TestPathUtilsSuite(t *testing.T) {
+	suite.Run(t, new(pathUtilsSuite))
+}
+
+func (s *pathUtilsSuite) TestParsePath() {
+	segments, err := ParsePath("Owner.Team.ID")
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []PathSegment{{Name: "Owner"}, {Name: "Team"}, {Name: "ID"}}, segments)
+
+	segments, err = ParsePath("Items[1].Tags[0][2]")
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []PathSegment{
+		{Name: "Items"},
+		{Index: 1, IsIndex: true},
+		{Name: "Tags"},
+		{Index: 0, IsIndex: true},
+		{Index: 2, IsIndex: true},
+	}, segments)
+
+	invalidPaths := []string{"", ".Owner", "Owner.", "Owner..ID", "Tags[", "Tags[a]", "Tags[-1]", "Tags[0]."}
+
+	for _, path := range invalidPaths {
+		_, err = ParsePath(path)
+
+		assert.IsType(s.T(), new(PathError), err, path)
+	}
+}
+
+func (s *pathUtilsSuite) TestGetPathValue() {
+	testResource := &pathResource{
+		Owner: pathOwner{
+			Team:  &pathTeam{ID: "team-one"},
+			Roles: []string{"admin"},
+		},
+		Tags:   []string{"one", "two"},
+		Matrix: [][]int{{1, 2}, {3, 4}},
+		Meta: map[string]interface{}{
+			"region": "eu",
+			"nested": map[string]interface{}{"zone": "a"},
+		},
+		Any: &pathTeam{ID: "team-two"},
+	}
+
+	testCases := []struct {
+		path  string
+		value interface{}
+	}{
+		{"Owner.Team.ID", "team-one"},
+		{"Owner.Roles[0]", "admin"},
+		{"Tags[1]", "two"},
+		{"Matrix[1][0]", 3},
+		{"Meta.region", "eu"},
+		{"Meta.nested.zone", "a"},
+		{"Meta.missing", nil},
+		{"Any.ID", "team-two"},
+	}
+
+	for _, testCase := range testCases {
+		value, err := GetPathValue(testResource, testCase.path)
+
+		assert.Nil(s.T(), err, testCase.path)
+		assert.Equal(s.T(), testCase.value, value, testCase.path)
+	}
+
+	failingCases := []struct {
+		path    string
+		segment string
+	}{
+		{"Owner.Group.ID", "Group"},
+		{"Tags[2]", "[2]"},
+		{"Meta.missing.zone", "missing"},
+		{"NilOwner.Team", "Team"},
+		{"Owner.Team.ID.Value", "Value"},
+		{"Owner[0]", "[0]"},
+	}
+
+	for _, failingCase := range failingCases {
+		value, err := GetPathValue(testResource, failingCase.path)
+
+		assert.Nil(s.T(), value, failingCase.path)
+		assert.IsType(s.T(), new(PathError), err, failingCase.path)
+		assert.Equal(s.T(), failingCase.segment, err.(*PathError).Segment, failingCase.path)
+	}
+
+	// Map with the whole path as a key.
+	value, err := GetPathValue(map[string]interface{}{"meta.region": "us"}, "meta.region")
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "us", value)
+
+	// Map with non-string keys.
+	_, err = GetPathValue(map[int]string{1: "one"}, "one")
+
+	assert.IsType(s.T(), new(PathError), err)
+}
+
+type taggedEntity struct {
+	OwnerID   string `json:"ownerId"`
+	TenantID  string `restrict:"tenant" json:"tenantId"`
+	Ignored   string `json:"-"`
+	region    string
+	secret    string
+	createdBy string `restrict:"createdBy"`
+}
+
+func (e *taggedEntity) GetRegion() string {
+	return e.region
+}
+
+func (e taggedEntity) GetCreatedBy() (string, error) {
+	if e.createdBy == "" {
+		return "", errors.New("not set")
+	}
+
+	return e.createdBy, nil
+}
+
+func (e *taggedEntity) GetDisplayName() string {
+	return "Entity " + e.OwnerID
+}
+
+func (e *taggedEntity) GetWithArgument(prefix string) string {
+	return prefix
+}
+
+func (s *pathUtilsSuite) TestGetPathValue_Members() {
+	testEntity := &taggedEntity{
+		OwnerID:   "user-one",
+		TenantID:  "tenant-one",
+		region:    "eu",
+		secret:    "secret",
+		createdBy: "user-two",
+	}
+
+	testCases := []struct {
+		path  string
+		value interface{}
+	}{
+		{"OwnerID", "user-one"},
+		{"ownerId", "user-one"},
+		{"tenant", "tenant-one"},
+		{"tenantId", "tenant-one"},
+		{"region", "eu"},
+		{"Region", "eu"},
+		{"createdBy", "user-two"},
+		{"DisplayName", "Entity user-one"},
+		{"displayName", "Entity user-one"},
+	}
+
+	for _, testCase := range testCases {
+		value, err := GetPathValue(testEntity, testCase.path)
+
+		assert.Nil(s.T(), err, testCase.path)
+		assert.Equal(s.T(), testCase.value, value, testCase.path)
+	}
+
+	failingPaths := []string{"secret", "-", "WithArgument", "Unknown"}
+
+	for _, path := range failingPaths {
+		_, err := GetPathValue(testEntity, path)
+
+		assert.IsType(s.T(), new(PathError), err, path)
+	}
+
+	// Getter returning an error.
+	testEntity.createdBy = ""
+
+	_, err := GetPathValue(testEntity, "createdBy")
+
+	assert.IsType(s.T(), new(PathError), err)
+	assert.Contains(s.T(), err.Error(), "not set")
+
+	// Getter with pointer receiver on non-addressable value.
+	_, err = GetPathValue(*testEntity, "region")
+
+	assert.IsType(s.T(), new(PathError), err)
+	assert.Contains(s.T(), err.Error(), "pointer receiver")
+
+	// Getter with pointer receiver on addressable value.
+	value, err := GetPathValue(map[string]interface{}{"entities": []taggedEntity{*testEntity}}, "entities[0].region")
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "eu", value)
+}
+
+type providerEntity struct {
+	calls      []string
+	attributes map[string]interface{}
+}
+
+func (e *providerEntity) GetAttribute(name string) (interface{}, bool) {
+	e.calls = append(e.calls, name)
+
+	value, ok := e.attributes[name]
+
+	return value, ok
+}
+
+func (s *pathUtilsSuite) TestGetPathValue_AttributeProvider() {
+	testTeam := &providerEntity{
+		attributes: map[string]interface{}{"ID": "team-one"},
+	}
+
+	testEntity := &providerEntity{
+		attributes: map[string]interface{}{
+			"Team":        testTeam,
+			"Tags":        []string{"one", "two"},
+			"meta.region": "eu",
+			"Owner":       pathOwner{Team: &pathTeam{ID: "team-two"}},
+		},
+	}
+
+	testCases := []struct {
+		path  string
+		value interface{}
+	}{
+		{"Team.ID", "team-one"},
+		{"Tags[1]", "two"},
+		{"meta.region", "eu"},
+		{"Owner.Team.ID", "team-two"},
+	}
+
+	for _, testCase := range testCases {
+		value, err := GetPathValue(testEntity, testCase.path)
+
+		assert.Nil(s.T(), err, testCase.path)
+		assert.Equal(s.T(), testCase.value, value, testCase.path)
+	}
+
+	// Whole path is asked first, then its segments.
+	assert.Equal(s.T(), []string{"Team.ID", "Team", "Tags[1]", "Tags", "meta.region", "Owner.Team.ID", "Owner"}, testEntity.calls)
+	assert.Equal(s.T(), []string{"ID"}, testTeam.calls)
+
+	_, err := GetPathValue(testEntity, "Team.Name")
+
+	assert.IsType(s.T(), new(PathError), err)
+	assert.Equal(s.T(), "Name", err.(*PathError).Segment)
+
+	// Nil provider.
+	testEntity.attributes["Empty"] = (*providerEntity)(nil)
+
+	_, err = GetPathValue(testEntity, "Empty.ID")
+
+	assert.IsType(s.T(), new(PathError), err)
+	assert.Equal(s.T(), "ID", err.(*PathError).Segment)
+}
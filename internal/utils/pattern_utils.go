@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// patternWildcards - characters that make a name a pattern.
+const patternWildcards = "*?["
+
+// matchAll - pattern matching any name.
+const matchAll = "*"
+
+// compiledPatterns - cache of compiled name patterns, so every pattern is compiled once.
+var compiledPatterns sync.Map
+
+// IsPattern - returns true if given name contains any of the glob wildcards.
+func IsPattern(name string) bool {
+	return strings.ContainsAny(name, patternWildcards)
+}
+
+// MatchPattern - returns true if given name matches given glob pattern (see GlobToRegexp).
+// "*" on its own matches any name. Pattern that cannot be compiled does not match anything.
+func MatchPattern(pattern, name string) bool {
+	if pattern == matchAll {
+		return true
+	}
+
+	if !IsPattern(pattern) {
+		return pattern == name
+	}
+
+	compiled := getCompiledPattern(pattern)
+
+	return compiled != nil && compiled.MatchString(name)
+}
+
+// PatternSpecificity - returns the number of non-wildcard characters in given pattern.
+// The more literal characters pattern contains, the more specific it is.
+func PatternSpecificity(pattern string) int {
+	specificity := 0
+
+	for _, r := range pattern {
+		if r != '*' && r != '?' {
+			specificity++
+		}
+	}
+
+	return specificity
+}
+
+// FindBestMatch - returns the candidate that matches given name best: exact match wins over patterns,
+// and more specific patterns win over less specific ones (ties are resolved in lexical order).
+// Second return value is false if none of the candidates matches.
+func FindBestMatch(candidates []string, name string) (string, bool) {
+	best := ""
+	found := false
+
+	for _, candidate := range candidates {
+		if candidate == name {
+			return candidate, true
+		}
+
+		if !IsPattern(candidate) || !MatchPattern(candidate, name) {
+			continue
+		}
+
+		if !found || isMoreSpecific(candidate, best) {
+			best = candidate
+			found = true
+		}
+	}
+
+	return best, found
+}
+
+// FindMatches - returns all candidates matching given name, ordered the same way FindBestMatch
+// picks them: exact match first, then patterns from the most specific one.
+func FindMatches(candidates []string, name string) []string {
+	matches := []string{}
+
+	for _, candidate := range candidates {
+		if candidate == name || (IsPattern(candidate) && MatchPattern(candidate, name)) {
+			matches = append(matches, candidate)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i] == name || matches[j] == name {
+			return matches[i] == name && matches[j] != name
+		}
+
+		return isMoreSpecific(matches[i], matches[j])
+	})
+
+	return matches
+}
+
+// isMoreSpecific - returns true if pattern a takes precedence over pattern b.
+func isMoreSpecific(a, b string) bool {
+	aSpecificity := PatternSpecificity(a)
+	bSpecificity := PatternSpecificity(b)
+
+	if aSpecificity != bSpecificity {
+		return aSpecificity > bSpecificity
+	}
+
+	return a < b
+}
+
+// getCompiledPattern - returns cached, compiled pattern, compiling it if needed.
+// Returns nil if pattern cannot be compiled.
+func getCompiledPattern(pattern string) *regexp.Regexp {
+	if cached, ok := compiledPatterns.Load(pattern); ok {
+		return cached.(*regexp.Regexp)
+	}
+
+	compiled, err := regexp.Compile(GlobToRegexp(pattern))
+	if err != nil {
+		compiled = nil
+	}
+
+	compiledPatterns.Store(pattern, compiled)
+
+	return compiled
+}
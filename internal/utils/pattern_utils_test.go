@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type patternUtilsSuite struct {
+	suite.Suite
+}
+
+func TestPatternUtilsSuite(t *testing.T) {
+	suite.Run(t, new(patternUtilsSuite))
+}
+
+func (s *patternUtilsSuite) TestIsPattern() {
+	assert.True(s.T(), IsPattern("*"))
+	assert.True(s.T(), IsPattern("read:*"))
+	assert.True(s.T(), IsPattern("billing/?"))
+	assert.True(s.T(), IsPattern("billing/[ab]"))
+	assert.False(s.T(), IsPattern("read"))
+	assert.False(s.T(), IsPattern("billing/invoices"))
+}
+
+func (s *patternUtilsSuite) TestMatchPattern() {
+	testCases := []struct {
+		pattern  string
+		name     string
+		expected bool
+	}{
+		{pattern: "*", name: "read", expected: true},
+		{pattern: "*", name: "billing/invoices/items", expected: true},
+		{pattern: "read", name: "read", expected: true},
+		{pattern: "read", name: "reads", expected: false},
+		{pattern: "read:*", name: "read:comments", expected: true},
+		{pattern: "read:*", name: "read", expected: false},
+		{pattern: "read:*", name: "write:comments", expected: false},
+		{pattern: "billing/*", name: "billing/invoices", expected: true},
+		{pattern: "billing/*", name: "billing/invoices/items", expected: false},
+		{pattern: "billing/**", name: "billing/invoices/items", expected: true},
+		{pattern: "billing/?", name: "billing/a", expected: true},
+		{pattern: "billing/[z-a]", name: "billing/a", expected: false},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(s.T(), testCase.expected, MatchPattern(testCase.pattern, testCase.name), testCase.pattern)
+	}
+}
+
+func (s *patternUtilsSuite) TestPatternSpecificity() {
+	assert.Equal(s.T(), 0, PatternSpecificity("*"))
+	assert.Equal(s.T(), 5, PatternSpecificity("read:*"))
+	assert.Equal(s.T(), 8, PatternSpecificity("billing/**"))
+}
+
+func (s *patternUtilsSuite) TestFindBestMatch() {
+	candidates := []string{"*", "read:*", "read:comments", "r*"}
+
+	match, ok := FindBestMatch(candidates, "read:comments")
+
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), "read:comments", match)
+
+	match, ok = FindBestMatch(candidates, "read:posts")
+
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), "read:*", match)
+
+	match, ok = FindBestMatch(candidates, "remove")
+
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), "r*", match)
+
+	match, ok = FindBestMatch(candidates, "delete")
+
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), "*", match)
+
+	// Ties are resolved in lexical order.
+	match, ok = FindBestMatch([]string{"b*", "a*", "*b"}, "ab")
+
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), "*b", match)
+
+	_, ok = FindBestMatch([]string{"read", "write:*"}, "delete")
+
+	assert.False(s.T(), ok)
+}
+
+func (s *patternUtilsSuite) TestFindMatches() {
+	candidates := []string{"*", "read:*", "read:comments", "r*", "write:*"}
+
+	assert.Equal(s.T(), []string{"read:comments", "read:*", "r*", "*"}, FindMatches(candidates, "read:comments"))
+	assert.Equal(s.T(), []string{"read:*", "r*", "*"}, FindMatches(candidates, "read:posts"))
+	assert.Equal(s.T(), []string{"*"}, FindMatches(candidates, "delete"))
+
+	// Ties are resolved in lexical order.
+	assert.Equal(s.T(), []string{"*b", "a*"}, FindMatches([]string{"b*", "a*", "*b"}, "ab"))
+
+	assert.Equal(s.T(), []string{}, FindMatches([]string{"read", "write:*"}, "delete"))
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// GetStringValue - returns string value of passed argument, if it's of a string kind.
+// Second return value is false otherwise.
+func GetStringValue(value interface{}) (string, bool) {
+	rValue := reflect.ValueOf(value)
+
+	if rValue.Kind() != reflect.String {
+		return "", false
+	}
+
+	return rValue.String(), true
+}
+
+// GlobToRegexp - translates glob pattern into anchored regular expression.
+// "**" matches any sequence of characters, "*" matches any sequence of characters
+// except "/", "?" matches any single character except "/", and "[...]" matches
+// a character class ("[!...]" negates the class).
+func GlobToRegexp(pattern string) string {
+	builder := strings.Builder{}
+	builder.WriteString("^")
+
+	runes := []rune(pattern)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch r {
+		case '*':
+			if i+1 < len(runes) && runes[i+1] == '*' {
+				builder.WriteString(".*")
+				i++
+			} else {
+				builder.WriteString("[^/]*")
+			}
+		case '?':
+			builder.WriteString("[^/]")
+		case '[':
+			end := i + 1
+
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+
+			// Unclosed bracket is treated literally.
+			if end >= len(runes) {
+				builder.WriteString(regexp.QuoteMeta(string(r)))
+				continue
+			}
+
+			class := string(runes[i+1 : end])
+
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+
+			builder.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i = end
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	builder.WriteString("$")
+
+	return builder.String()
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type stringUtilsSuite struct {
+	suite.Suite
+}
+
+func TestStringUtilsSuite(t *testing.T) {
+	suite.Run(t, new(stringUtilsSuite))
+}
+
+type namedString string
+
+func (s *stringUtilsSuite) TestGetStringValue() {
+	value, ok := GetStringValue("test")
+
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), "test", value)
+
+	value, ok = GetStringValue(namedString("test"))
+
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), "test", value)
+
+	_, ok = GetStringValue(1)
+	assert.False(s.T(), ok)
+
+	_, ok = GetStringValue(nil)
+	assert.False(s.T(), ok)
+}
+
+func (s *stringUtilsSuite) TestGlobToRegexp() {
+	testCases := []struct {
+		pattern string
+		value   string
+		matches bool
+	}{
+		{"/projects/*/docs", "/projects/team/docs", true},
+		{"/projects/*/docs", "/projects/team/sub/docs", false},
+		{"/projects/**", "/projects/team/sub/docs", true},
+		{"/projects/**", "/other/team", false},
+		{"file?.txt", "file1.txt", true},
+		{"file?.txt", "file10.txt", false},
+		{"file[0-9].txt", "file5.txt", true},
+		{"file[!0-9].txt", "file5.txt", false},
+		{"file[!0-9].txt", "fileA.txt", true},
+		{"a.b", "a.b", true},
+		{"a.b", "axb", false},
+		{"a[b", "a[b", true},
+		{"(a)+", "(a)+", true},
+	}
+
+	for _, testCase := range testCases {
+		matched := regexp.MustCompile(GlobToRegexp(testCase.pattern)).MatchString(testCase.value)
+
+		assert.Equal(s.T(), testCase.matches, matched, "%s - %s", testCase.pattern, testCase.value)
+	}
+}
@@ -0,0 +1,188 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// memberTags - struct tags used for resolving members, in order of precedence.
+var memberTags = []string{"restrict", "json"}
+
+// errorType - reflect.Type of error interface.
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// memberKey - key of the members cache.
+type memberKey struct {
+	structType reflect.Type
+	name       string
+}
+
+// member - describes how to access a member of a struct under given name.
+type member struct {
+	// fieldIndex - index of exported field, nil if member is accessed with a getter.
+	fieldIndex []int
+	// getter - name of the getter method, empty if member is accessed directly.
+	getter string
+	// err - reason why member cannot be accessed, nil if it can.
+	err error
+}
+
+// members - cache of resolved members, so reflection lookups are performed once per type and name.
+var members sync.Map
+
+// GetStructMember - returns a value of struct's member with given name. Member can be:
+// an exported field with given name, an exported field with given name in "restrict" or "json"
+// tag, or a value returned by exported getter method (e.g. "GetOwnerID()" for "OwnerID" name).
+// Getters are also used for unexported fields. Getter needs to take no arguments and return
+// a value, optionally followed by an error. Pointer should be the addressable pointer to
+// the struct (if available), so getters with pointer receivers can be used.
+func GetStructMember(structValue, pointer reflect.Value, name string) (reflect.Value, error) {
+	memberDescriptor := getMember(structValue.Type(), name)
+
+	if memberDescriptor.err != nil {
+		return reflect.Value{}, memberDescriptor.err
+	}
+
+	if memberDescriptor.fieldIndex != nil {
+		return structValue.FieldByIndex(memberDescriptor.fieldIndex), nil
+	}
+
+	receiver := pointer
+
+	if !receiver.IsValid() && structValue.CanAddr() {
+		receiver = structValue.Addr()
+	}
+
+	if !receiver.IsValid() {
+		receiver = structValue
+	}
+
+	method := receiver.MethodByName(memberDescriptor.getter)
+
+	if !method.IsValid() {
+		return reflect.Value{}, fmt.Errorf("getter \"%s\" requires a pointer receiver, but value of type \"%s\" is not addressable", memberDescriptor.getter, structValue.Type())
+	}
+
+	results := method.Call(nil)
+
+	if len(results) == 2 && !results[1].IsNil() {
+		return reflect.Value{}, fmt.Errorf("getter \"%s\" returned an error: %s", memberDescriptor.getter, results[1].Interface().(error).Error())
+	}
+
+	return results[0], nil
+}
+
+// getMember - returns cached member descriptor for given struct type and name,
+// resolving it if needed.
+func getMember(structType reflect.Type, name string) *member {
+	key := memberKey{structType: structType, name: name}
+
+	if cached, ok := members.Load(key); ok {
+		return cached.(*member)
+	}
+
+	resolved := resolveMember(structType, name)
+	members.Store(key, resolved)
+
+	return resolved
+}
+
+// resolveMember - finds a way to access a member of given struct type under given name.
+func resolveMember(structType reflect.Type, name string) *member {
+	fieldName := ""
+
+	if field, ok := structType.FieldByName(name); ok {
+		if field.PkgPath == "" {
+			return &member{fieldIndex: field.Index}
+		}
+
+		fieldName = field.Name
+	}
+
+	if fieldName == "" {
+		for _, tag := range memberTags {
+			field, ok := findTaggedField(structType, tag, name)
+			if !ok {
+				continue
+			}
+
+			if field.PkgPath == "" {
+				return &member{fieldIndex: field.Index}
+			}
+
+			fieldName = field.Name
+			break
+		}
+	}
+
+	getterName := "Get" + upperFirst(name)
+
+	if fieldName != "" {
+		getterName = "Get" + upperFirst(fieldName)
+	}
+
+	if isGetter(reflect.PtrTo(structType), getterName) {
+		return &member{getter: getterName}
+	}
+
+	if fieldName != "" {
+		return &member{err: fmt.Errorf("field \"%s\" of type \"%s\" is unexported and has no \"%s\" getter", fieldName, structType, getterName)}
+	}
+
+	return &member{err: fmt.Errorf("field does not exist on type \"%s\"", structType)}
+}
+
+// findTaggedField - returns a field of given struct type, which has given name in given tag.
+func findTaggedField(structType reflect.Type, tag, name string) (reflect.StructField, bool) {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+
+		tagName := strings.Split(field.Tag.Get(tag), ",")[0]
+
+		if tagName != "" && tagName != "-" && tagName == name {
+			return field, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+// isGetter - returns true if given type has an exported method with given name, taking
+// no arguments and returning a value, optionally followed by an error.
+func isGetter(rType reflect.Type, methodName string) bool {
+	method, ok := rType.MethodByName(methodName)
+	if !ok {
+		return false
+	}
+
+	methodType := method.Type
+
+	// First input is the receiver.
+	if methodType.NumIn() != 1 {
+		return false
+	}
+
+	switch methodType.NumOut() {
+	case 1:
+		return true
+	case 2:
+		return methodType.Out(1) == errorType
+	}
+
+	return false
+}
+
+// upperFirst - returns given string with the first letter in upper case.
+func upperFirst(value string) string {
+	if value == "" {
+		return value
+	}
+
+	first, size := utf8.DecodeRuneInString(value)
+
+	return string(unicode.ToUpper(first)) + value[size:]
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"time"
+)
+
+// timeLayouts - layouts accepted when parsing time from a string.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// GetTimeValue - returns time.Time value of passed argument. Accepts time.Time,
+// non-nil *time.Time and strings in RFC3339 or "2006-01-02" formats.
+// Second return value is false if the argument cannot be converted.
+func GetTimeValue(value interface{}) (time.Time, bool) {
+	switch typedValue := value.(type) {
+	case time.Time:
+		return typedValue, true
+	case *time.Time:
+		if typedValue == nil {
+			return time.Time{}, false
+		}
+
+		return *typedValue, true
+	}
+
+	stringValue, ok := GetStringValue(value)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	for _, layout := range timeLayouts {
+		if parsed, err := time.Parse(layout, stringValue); err == nil {
+			return parsed, true
+		}
+	}
+
+	return time.Time{}, false
+}
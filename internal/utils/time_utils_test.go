@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type timeUtilsSuite struct {
+	suite.Suite
+}
+
+func TestTimeUtilsSuite(t *testing.T) {
+	suite.Run(t, new(timeUtilsSuite))
+}
+
+func (s *timeUtilsSuite) TestGetTimeValue() {
+	testTime := time.Date(2023, time.March, 1, 12, 30, 0, 0, time.UTC)
+
+	value, ok := GetTimeValue(testTime)
+
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), testTime, value)
+
+	value, ok = GetTimeValue(&testTime)
+
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), testTime, value)
+
+	value, ok = GetTimeValue("2023-03-01T12:30:00Z")
+
+	assert.True(s.T(), ok)
+	assert.True(s.T(), testTime.Equal(value))
+
+	value, ok = GetTimeValue("2023-03-01T14:30:00+02:00")
+
+	assert.True(s.T(), ok)
+	assert.True(s.T(), testTime.Equal(value))
+
+	value, ok = GetTimeValue("2023-03-01")
+
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC), value)
+
+	var nilTime *time.Time
+
+	_, ok = GetTimeValue(nilTime)
+	assert.False(s.T(), ok)
+
+	_, ok = GetTimeValue("01.03.2023")
+	assert.False(s.T(), ok)
+
+	_, ok = GetTimeValue(1)
+	assert.False(s.T(), ok)
+
+	_, ok = GetTimeValue(nil)
+	assert.False(s.T(), ok)
+}
@@ -75,3 +75,10 @@ func GetStructFieldValue(structValue interface{}, fieldName string) interface{}
 
 	return rValue.Interface()
 }
+
+// IsNilPointer - returns true if argument is a nil pointer, false otherwise.
+func IsNilPointer(value interface{}) bool {
+	rValue := reflect.ValueOf(value)
+
+	return rValue.Kind() == reflect.Ptr && rValue.IsNil()
+}
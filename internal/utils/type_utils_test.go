@@ -64,6 +64,16 @@ func (s *typeUtilsSuite) TestIsMap() {
 	assert.False(s.T(), IsMap(&testMap))
 }
 
+func (s *typeUtilsSuite) TestIsNilPointer() {
+	assert.False(s.T(), IsNilPointer(1))
+	assert.False(s.T(), IsNilPointer(nil))
+	assert.False(s.T(), IsNilPointer(&testStruct{}))
+
+	var nilStruct *testStruct
+
+	assert.True(s.T(), IsNilPointer(nilStruct))
+}
+
 func (s *typeUtilsSuite) TestGetMapValue() {
 	testKey := "testKey"
 	testValue := 1
@@ -1,6 +1,8 @@
 package restrict
 
 import (
+	"context"
+
 	"github.com/stretchr/testify/mock"
 )
 
@@ -69,6 +71,33 @@ func (m *resourceMock) GetResourceName() string {
 	return args.String(0)
 }
 
+type hierarchicalResourceMock struct {
+	ID     string
+	Name   string
+	Parent Resource
+}
+
+func (m *hierarchicalResourceMock) GetResourceName() string {
+	return m.Name
+}
+
+func (m *hierarchicalResourceMock) GetParentResource() Resource {
+	return m.Parent
+}
+
+// endlessResourceMock - returns new parent instance every time, so its hierarchy never ends.
+type endlessResourceMock struct {
+	Name string
+}
+
+func (m *endlessResourceMock) GetResourceName() string {
+	return m.Name
+}
+
+func (m *endlessResourceMock) GetParentResource() Resource {
+	return &endlessResourceMock{Name: m.Name}
+}
+
 type conditionMock struct {
 	mock.Mock
 }
@@ -88,6 +117,16 @@ func (m *conditionMock) Check(request *AccessRequest) error {
 	return args.Error(0)
 }
 
+type contextConditionMock struct {
+	conditionMock
+}
+
+func (m *contextConditionMock) CheckContext(ctx context.Context, request *AccessRequest) error {
+	args := m.Called(ctx)
+
+	return args.Error(0)
+}
+
 func getBasicRoleOne() *Role {
 	return &Role{
 		ID:          basicRoleOneName,
@@ -1,20 +1,202 @@
 package restrict
 
+import (
+	"encoding/json"
+
+	"github.com/el-mike/restrict/v2/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// Effect - describes whether a Permission allows or denies its Action.
+type Effect string
+
+const (
+	// AllowEffect - Permission allows its Action. Default Effect, used when none is specified.
+	AllowEffect Effect = "allow"
+	// DenyEffect - Permission explicitly denies its Action.
+	DenyEffect Effect = "deny"
+)
+
+// UnmarshalJSON - unmarshals a string into Effect, returns UnknownEffectError
+// if the value is not one of the known Effects.
+func (e *Effect) UnmarshalJSON(jsonData []byte) error {
+	var name string
+
+	if err := json.Unmarshal(jsonData, &name); err != nil {
+		return err
+	}
+
+	return e.set(name)
+}
+
+// UnmarshalYAML - unmarshals a string into Effect, returns UnknownEffectError
+// if the value is not one of the known Effects.
+func (e *Effect) UnmarshalYAML(value *yaml.Node) error {
+	var name string
+
+	if err := value.Decode(&name); err != nil {
+		return err
+	}
+
+	return e.set(name)
+}
+
+// set - sets Effect to given value, if it's valid.
+func (e *Effect) set(name string) error {
+	effect := Effect(name)
+
+	if err := effect.validate(); err != nil {
+		return err
+	}
+
+	*e = effect
+
+	return nil
+}
+
+// validate - returns UnknownEffectError if Effect is neither empty nor one of the known Effects.
+func (e Effect) validate() error {
+	if e != "" && e != AllowEffect && e != DenyEffect {
+		return newUnknownEffectError(string(e))
+	}
+
+	return nil
+}
+
 // Permission - describes an Action that can be performed in regards to
 // some Resource, with specified Conditions.
 type Permission struct {
 	// Action that will be allowed to perform if the Permission is granted, and Conditions
-	// are satisfied.
+	// are satisfied. Can be a glob pattern matching Actions (e.g. "read:*" or "*").
 	Action string `json:"action,omitempty" yaml:"action,omitempty"`
+	// Effect - whether the Permission allows (default) or explicitly denies the Action.
+	// Deny Permission applies when its Conditions are satisfied.
+	Effect Effect `json:"effect,omitempty" yaml:"effect,omitempty"`
 	// Conditions that need to be satisfied in order to allow the subject perform given Action.
 	Conditions Conditions `json:"conditions,omitempty" yaml:"conditions,omitempty"`
 	// Preset allows to extend Permission defined in PolicyDefinition.
 	Preset string `json:"preset,omitempty" yaml:"preset,omitempty"`
 }
 
+// getEffect - returns Permission's Effect, or AllowEffect if none is specified.
+func (p *Permission) getEffect() Effect {
+	if p.Effect == "" {
+		return AllowEffect
+	}
+
+	return p.Effect
+}
+
+// isDeny - returns true if the Permission explicitly denies its Action.
+func (p *Permission) isDeny() bool {
+	return p.Effect == DenyEffect
+}
+
+// matches - returns true if Permission's Action is given Action, or a pattern matching it.
+func (p *Permission) matches(action string) bool {
+	return utils.MatchPattern(p.Action, action)
+}
+
 // Permissions - alias type for slice of Permissions.
 type Permissions []*Permission
 
+// forAction - returns Permissions matching given Action. Permissions with exact Action
+// take precedence over patterns, and more specific patterns take precedence over less
+// specific ones - only Permissions with the best matching Action are returned, followed by
+// deny Permissions matching the Action with other patterns, as precedence never bypasses them.
+func (ps Permissions) forAction(action string) Permissions {
+	actions := []string{}
+
+	for _, permission := range ps {
+		if !utils.StringSliceContains(actions, permission.Action) {
+			actions = append(actions, permission.Action)
+		}
+	}
+
+	bestMatch, ok := utils.FindBestMatch(actions, action)
+	if !ok {
+		return nil
+	}
+
+	result := Permissions{}
+
+	for _, permission := range ps {
+		if permission.Action == bestMatch {
+			result = append(result, permission)
+		}
+	}
+
+	for _, permission := range ps {
+		if permission.Action != bestMatch && permission.isDeny() && permission.matches(action) {
+			result = append(result, permission)
+		}
+	}
+
+	return result
+}
+
+// forActions - returns Permissions matching any of given Actions (see forAction), in order
+// of the Actions, without duplicates.
+func (ps Permissions) forActions(actions []string) Permissions {
+	result := Permissions{}
+
+	for _, action := range actions {
+		for _, permission := range ps.forAction(action) {
+			if !result.contains(permission) {
+				result = append(result, permission)
+			}
+		}
+	}
+
+	return result
+}
+
+// denying - returns deny Permissions matching any of given Actions, exactly or with a pattern.
+func (ps Permissions) denying(actions []string) Permissions {
+	result := Permissions{}
+
+	for _, permission := range ps {
+		if !permission.isDeny() {
+			continue
+		}
+
+		for _, action := range actions {
+			if permission.matches(action) {
+				result = append(result, permission)
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+// copy - returns a copy of the Permissions (see Permission's copy).
+func (ps Permissions) copy() Permissions {
+	if ps == nil {
+		return nil
+	}
+
+	result := make(Permissions, len(ps))
+
+	for i, permission := range ps {
+		result[i] = permission.copy()
+	}
+
+	return result
+}
+
+// contains - returns true if given Permission is present in Permissions.
+func (ps Permissions) contains(permission *Permission) bool {
+	for _, p := range ps {
+		if p == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
 // mergePreset - merges preset values into Permission.
 func (p *Permission) mergePreset(preset *Permission) {
 	if preset == nil {
@@ -26,6 +208,11 @@ func (p *Permission) mergePreset(preset *Permission) {
 		p.Action = preset.Action
 	}
 
+	// The same goes for the effect.
+	if p.Effect == "" {
+		p.Effect = preset.Effect
+	}
+
 	// If Permission have its own Conditions, they will be merged
 	// together with the ones from the preset.
 	p.Conditions = append(p.Conditions, preset.Conditions...)
@@ -35,7 +222,35 @@ func (p *Permission) mergePreset(preset *Permission) {
 	p.Preset = ""
 }
 
+// copy - returns a deep copy of the Permission, including its Conditions (see CopyableCondition).
+func (p *Permission) copy() *Permission {
+	if p == nil {
+		return nil
+	}
+
+	permission := *p
+
+	permission.Conditions = p.Conditions.copy()
+
+	return &permission
+}
+
 // PermissionPresets - a map of reusable Permissions. Map key serves as a preset's name,
 // that can be later referenced by Permission.
 // Presets are applied when policy is loaded.
 type PermissionPresets map[string]*Permission
+
+// copy - returns a copy of the PermissionPresets (see Permission's copy).
+func (pp PermissionPresets) copy() PermissionPresets {
+	if pp == nil {
+		return nil
+	}
+
+	result := make(PermissionPresets, len(pp))
+
+	for name, preset := range pp {
+		result[name] = preset.copy()
+	}
+
+	return result
+}
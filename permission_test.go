@@ -1,10 +1,12 @@
 package restrict
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
 )
 
 type permissionSuite struct {
@@ -71,6 +73,91 @@ func (s *permissionSuite) TestMergePreset() {
 	assert.Equal(s.T(), customAction, testPermission.Action)
 }
 
+func (s *permissionSuite) TestMergePreset_Effect() {
+	testPreset := &Permission{
+		Action: s.testAction,
+		Effect: DenyEffect,
+	}
+
+	testPermission := &Permission{}
+
+	testPermission.mergePreset(testPreset)
+
+	assert.Equal(s.T(), DenyEffect, testPermission.Effect)
+	assert.True(s.T(), testPermission.isDeny())
+
+	// Should not override Permission's own effect.
+	testPermission = &Permission{
+		Effect: AllowEffect,
+	}
+
+	testPermission.mergePreset(testPreset)
+
+	assert.Equal(s.T(), AllowEffect, testPermission.Effect)
+	assert.False(s.T(), testPermission.isDeny())
+}
+
+func (s *permissionSuite) TestForAction() {
+	readPermission := &Permission{Action: "read:comments"}
+	secondReadPermission := &Permission{Action: "read:comments"}
+	readAllPermission := &Permission{Action: "read:*"}
+	allPermission := &Permission{Action: "*"}
+
+	testPermissions := Permissions{allPermission, readPermission, readAllPermission, secondReadPermission}
+
+	assert.Equal(s.T(), Permissions{readPermission, secondReadPermission}, testPermissions.forAction("read:comments"))
+	assert.Equal(s.T(), Permissions{readAllPermission}, testPermissions.forAction("read:posts"))
+	assert.Equal(s.T(), Permissions{allPermission}, testPermissions.forAction("delete"))
+
+	// Matching deny patterns are always included.
+	denyAllPermission := &Permission{Action: "*", Effect: DenyEffect}
+	denyDeletePermission := &Permission{Action: "delete", Effect: DenyEffect}
+
+	testPermissions = Permissions{denyAllPermission, readPermission, readAllPermission, denyDeletePermission}
+
+	assert.Equal(s.T(), Permissions{readPermission, denyAllPermission}, testPermissions.forAction("read:comments"))
+	assert.Equal(s.T(), Permissions{readAllPermission, denyAllPermission}, testPermissions.forAction("read:posts"))
+	assert.Equal(s.T(), Permissions{denyDeletePermission, denyAllPermission}, testPermissions.forAction("delete"))
+
+	testPermissions = Permissions{readPermission, readAllPermission}
+
+	assert.Nil(s.T(), testPermissions.forAction("delete"))
+	assert.Nil(s.T(), Permissions(nil).forAction("delete"))
+}
+
+func (s *permissionSuite) TestEffect_Unmarshal() {
+	testPermission := &Permission{}
+
+	err := json.Unmarshal([]byte(`{"action": "delete", "effect": "deny"}`), testPermission)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), DenyEffect, testPermission.Effect)
+
+	testPermission = &Permission{}
+
+	err = yaml.Unmarshal([]byte("action: delete\neffect: allow\n"), testPermission)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), AllowEffect, testPermission.Effect)
+
+	// Effect is optional.
+	testPermission = &Permission{}
+
+	err = json.Unmarshal([]byte(`{"action": "delete"}`), testPermission)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), Effect(""), testPermission.Effect)
+	assert.False(s.T(), testPermission.isDeny())
+
+	err = json.Unmarshal([]byte(`{"action": "delete", "effect": "forbid"}`), &Permission{})
+
+	assert.IsType(s.T(), new(UnknownEffectError), err)
+
+	err = yaml.Unmarshal([]byte("action: delete\neffect: Deny\n"), &Permission{})
+
+	assert.IsType(s.T(), new(UnknownEffectError), err)
+}
+
 func (s *permissionSuite) TestMergePreset_NilPresetConditions() {
 	testPreset := &Permission{
 		Action: s.testAction,
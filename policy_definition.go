@@ -7,4 +7,23 @@ type PolicyDefinition struct {
 	PermissionPresets PermissionPresets `json:"permissionPresets,omitempty" yaml:"permissionPresets,omitempty"`
 	// Roles - collection of Roles used in the domain.
 	Roles Roles `json:"roles" yaml:"roles"`
+	// Actions - optional hierarchy of Actions, describing which Actions imply other Actions.
+	Actions *ActionHierarchy `json:"actions,omitempty" yaml:"actions,omitempty"`
+	// Resources - optional definitions of Resources' hierarchy.
+	Resources ResourceDefinitions `json:"resources,omitempty" yaml:"resources,omitempty"`
+}
+
+// copy - returns a deep copy of the PolicyDefinition, including Permissions' Conditions
+// (see CopyableCondition).
+func (p *PolicyDefinition) copy() *PolicyDefinition {
+	if p == nil {
+		return nil
+	}
+
+	return &PolicyDefinition{
+		PermissionPresets: p.PermissionPresets.copy(),
+		Roles:             p.Roles.copy(),
+		Actions:           p.Actions.copy(),
+		Resources:         p.Resources.copy(),
+	}
 }
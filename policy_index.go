@@ -0,0 +1,388 @@
+package restrict
+
+import (
+	"sort"
+
+	"github.com/el-mike/restrict/v2/internal/utils"
+)
+
+// PolicyIndex - immutable, precompiled view of PolicyDefinition's Roles, used by AccessManager
+// to evaluate Permissions without fetching Roles one by one. For every Role, it holds the Role's
+// flattened ancestors (in order of evaluation), and its Permissions indexed by Resource and Action.
+// PolicyIndex does not follow changes made to the policy - it has to be rebuilt instead.
+type PolicyIndex struct {
+	// roles - indexed Roles by their IDs.
+	roles map[string]*indexedRole
+	// hasDeny - true if any of the Roles has a Permission with DenyEffect.
+	hasDeny bool
+	// implications - compiled ActionHierarchy of the policy.
+	implications *compiledImplications
+	// actions - all non-pattern Actions used in the policy's Permissions and ActionHierarchy, sorted.
+	actions []string
+}
+
+// indexedRole - Role's entry in PolicyIndex.
+type indexedRole struct {
+	// ancestors - the Role itself and all its ancestors, in depth-first order of evaluation.
+	// Every ancestor is listed once, even if it can be reached by multiple inheritance paths.
+	ancestors []*indexedAncestor
+	// grants - Role's Permissions by Resource names (or patterns) they are granted for.
+	grants map[string]*indexedGrants
+	// resourcePatterns - Resource patterns Role's Permissions are granted for.
+	resourcePatterns []string
+	// denyResourcePatterns - Resource patterns Role's deny Permissions are granted for.
+	denyResourcePatterns []string
+}
+
+// indexedAncestor - single entry of Role's flattened ancestors.
+type indexedAncestor struct {
+	// name - ancestor's ID.
+	name string
+	// path - inheritance path from the indexed Role to the ancestor. For entries closing
+	// an inheritance cycle, it ends with the Role referencing the ancestor.
+	path []string
+	// role - indexed ancestor, nil if it's not defined in the policy or it closes a cycle.
+	role *indexedRole
+	// isCycle - true if the ancestor closes an inheritance cycle.
+	isCycle bool
+}
+
+// indexedGrants - Permissions granted for single Resource name (or pattern), indexed by Actions.
+type indexedGrants struct {
+	// actions - Permissions by Actions, for every non-pattern Action of the Permissions.
+	actions map[string]Permissions
+	// actionPatterns - Permissions with Actions defined as patterns.
+	actionPatterns Permissions
+	// denyPatterns - deny Permissions with Actions defined as patterns.
+	denyPatterns Permissions
+	// denies - all deny Permissions.
+	denies Permissions
+}
+
+// NewPolicyIndex - returns new PolicyIndex instance, compiled from given PolicyDefinition.
+func NewPolicyIndex(policy *PolicyDefinition) *PolicyIndex {
+	index := &PolicyIndex{
+		roles: map[string]*indexedRole{},
+	}
+
+	if policy == nil {
+		return index
+	}
+
+	index.implications = policy.Actions.compile()
+	index.actions = getPolicyActions(policy)
+
+	for roleID, role := range policy.Roles {
+		if role == nil {
+			continue
+		}
+
+		index.roles[roleID] = newIndexedRole(role)
+		index.hasDeny = index.hasDeny || hasDenyPermissions(role)
+	}
+
+	for roleID := range index.roles {
+		ancestors := []*indexedAncestor{}
+		index.flatten(policy.Roles, roleID, []string{}, map[string]bool{}, &ancestors)
+
+		index.roles[roleID].ancestors = ancestors
+	}
+
+	return index
+}
+
+// newIndexedRole - returns new indexedRole instance, with given Role's Permissions indexed.
+// Ancestors are not set.
+func newIndexedRole(role *Role) *indexedRole {
+	indexed := &indexedRole{
+		grants:               map[string]*indexedGrants{},
+		resourcePatterns:     []string{},
+		denyResourcePatterns: []string{},
+	}
+
+	for resourceName, permissions := range role.Grants {
+		grants := newIndexedGrants(permissions)
+		indexed.grants[resourceName] = grants
+
+		if utils.IsPattern(resourceName) {
+			indexed.resourcePatterns = append(indexed.resourcePatterns, resourceName)
+
+			if len(grants.denies) > 0 {
+				indexed.denyResourcePatterns = append(indexed.denyResourcePatterns, resourceName)
+			}
+		}
+	}
+
+	return indexed
+}
+
+// getPolicyActions - returns all non-pattern Actions used in given policy's Permissions and ActionHierarchy, sorted.
+func getPolicyActions(policy *PolicyDefinition) []string {
+	found := map[string]bool{}
+
+	add := func(action string) {
+		if action != "" && !utils.IsPattern(action) {
+			found[action] = true
+		}
+	}
+
+	addImplications := func(implications ActionImplications) {
+		for action, implied := range implications {
+			add(action)
+
+			for _, impliedAction := range implied {
+				add(impliedAction)
+			}
+		}
+	}
+
+	for _, role := range policy.Roles {
+		if role == nil {
+			continue
+		}
+
+		for _, permissions := range role.Grants {
+			for _, permission := range permissions {
+				if permission != nil {
+					add(permission.Action)
+				}
+			}
+		}
+	}
+
+	if policy.Actions != nil {
+		addImplications(policy.Actions.Global)
+
+		for _, implications := range policy.Actions.Resources {
+			addImplications(implications)
+		}
+	}
+
+	actions := make([]string, 0, len(found))
+
+	for action := range found {
+		actions = append(actions, action)
+	}
+
+	sort.Strings(actions)
+
+	return actions
+}
+
+// hasDenyPermissions - returns true if any of given Role's Permissions has DenyEffect.
+func hasDenyPermissions(role *Role) bool {
+	for _, permissions := range role.Grants {
+		for _, permission := range permissions {
+			if permission.isDeny() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// newIndexedGrants - returns new indexedGrants instance for given Permissions.
+func newIndexedGrants(permissions Permissions) *indexedGrants {
+	grants := &indexedGrants{
+		actions:        map[string]Permissions{},
+		actionPatterns: Permissions{},
+		denyPatterns:   Permissions{},
+		denies:         Permissions{},
+	}
+
+	for _, permission := range permissions {
+		if permission.isDeny() {
+			grants.denies = append(grants.denies, permission)
+		}
+
+		if utils.IsPattern(permission.Action) {
+			grants.actionPatterns = append(grants.actionPatterns, permission)
+
+			if permission.isDeny() {
+				grants.denyPatterns = append(grants.denyPatterns, permission)
+			}
+
+			continue
+		}
+
+		grants.actions[permission.Action] = append(grants.actions[permission.Action], permission)
+	}
+
+	return grants
+}
+
+// flatten - appends given Role and its ancestors to the ancestors slice, in the same order
+// AccessManager evaluates them. Ancestors reachable by multiple inheritance paths are appended
+// only when they are visited for the first time, as evaluating them again would not bring any
+// new Permissions - this keeps the index linear in size for diamond-shaped hierarchies.
+// Cycles and undefined Roles are recorded, so they can be reported when (and if) the evaluation
+// reaches them.
+func (i *PolicyIndex) flatten(
+	roles Roles,
+	roleID string,
+	checkedRoles []string,
+	visited map[string]bool,
+	ancestors *[]*indexedAncestor,
+) {
+	path := append(append([]string{}, checkedRoles...), roleID)
+
+	visited[roleID] = true
+	indexed := i.roles[roleID]
+
+	*ancestors = append(*ancestors, &indexedAncestor{
+		name: roleID,
+		path: path,
+		role: indexed,
+	})
+
+	if indexed == nil {
+		return
+	}
+
+	for _, parent := range roles[roleID].Parents {
+		if utils.StringSliceContains(path, parent) {
+			*ancestors = append(*ancestors, &indexedAncestor{
+				name:    parent,
+				path:    path,
+				isCycle: true,
+			})
+
+			continue
+		}
+
+		if visited[parent] {
+			continue
+		}
+
+		i.flatten(roles, parent, path, visited, ancestors)
+	}
+}
+
+// getAncestors - returns flattened ancestors of a Role with given ID, including the Role itself.
+// If the Role is not defined, the only returned entry describes it as such.
+func (i *PolicyIndex) getAncestors(roleID string) []*indexedAncestor {
+	if role, ok := i.roles[roleID]; ok {
+		return role.ancestors
+	}
+
+	return []*indexedAncestor{{name: roleID, path: []string{roleID}}}
+}
+
+// getImplyingActions - returns all Actions implying given Action for given Resource, directly or not,
+// according to ActionHierarchy the index has been compiled from. Returned slice cannot be modified.
+func (i *PolicyIndex) getImplyingActions(resourceName, action string) []string {
+	return i.implications.getImplyingActions(resourceName, action)
+}
+
+// getPermissions - returns Permissions granted for given Resource name, matching any of given
+// Actions, in the same order as GrantsMap's forActions would return them.
+func (r *indexedRole) getPermissions(resourceName string, actions []string) Permissions {
+	// Most of the time, only a single Action is checked - in such case,
+	// matching Permissions can be returned directly.
+	if len(actions) == 1 {
+		return r.forAction(resourceName, actions[0])
+	}
+
+	result := Permissions{}
+
+	for _, action := range actions {
+		for _, permission := range r.forAction(resourceName, action) {
+			if !result.contains(permission) {
+				result = append(result, permission)
+			}
+		}
+	}
+
+	return result
+}
+
+// forAction - returns Permissions granted for given Resource name, matching given Action,
+// the same way GrantsMap's forAction does.
+func (r *indexedRole) forAction(resourceName, action string) Permissions {
+	if len(r.resourcePatterns) == 0 {
+		if grants, ok := r.grants[resourceName]; ok {
+			return grants.forAction(action)
+		}
+
+		return Permissions{}
+	}
+
+	keys := utils.FindMatches(r.resourcePatterns, resourceName)
+
+	if _, ok := r.grants[resourceName]; ok && !utils.IsPattern(resourceName) {
+		keys = append([]string{resourceName}, keys...)
+	}
+
+	for i, key := range keys {
+		permissions := r.grants[key].forAction(action)
+		if len(permissions) == 0 {
+			continue
+		}
+
+		if len(r.denyResourcePatterns) == 0 {
+			return permissions
+		}
+
+		// Deny Permissions granted for less specific patterns are never bypassed.
+		// Returned Permissions can be shared with the index, so they are copied first.
+		result := append(Permissions{}, permissions...)
+
+		for _, pattern := range keys[i+1:] {
+			for _, permission := range r.grants[pattern].denies.denying([]string{action}) {
+				if !result.contains(permission) {
+					result = append(result, permission)
+				}
+			}
+		}
+
+		return result
+	}
+
+	return Permissions{}
+}
+
+// forAction - returns Permissions matching given Action (see Permissions' forAction).
+func (g *indexedGrants) forAction(action string) Permissions {
+	permissions, ok := g.actions[action]
+	if !ok {
+		return g.actionPatterns.forAction(action)
+	}
+
+	if len(g.denyPatterns) == 0 {
+		return permissions
+	}
+
+	// Deny patterns matching the Action are never bypassed by the exact one.
+	result := append(Permissions{}, permissions...)
+
+	for _, permission := range g.denyPatterns {
+		if permission.matches(action) {
+			result = append(result, permission)
+		}
+	}
+
+	return result
+}
+
+// forActions - returns Permissions matching any of given Actions, in order of the Actions,
+// without duplicates.
+func (g *indexedGrants) forActions(actions []string) Permissions {
+	// Most of the time, only a single Action is checked - in such case,
+	// matching Permissions can be returned directly.
+	if len(actions) == 1 {
+		return g.forAction(actions[0])
+	}
+
+	result := Permissions{}
+
+	for _, action := range actions {
+		for _, permission := range g.forAction(action) {
+			if !result.contains(permission) {
+				result = append(result, permission)
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,510 @@
+package restrict
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// notIndexedPolicyProvider - exposes PolicyManager without its PolicyIndex, so Roles are fetched one by one.
+type notIndexedPolicyProvider struct {
+	PolicyProvider
+	ResourceDefinitionProvider
+	ActionHierarchyProvider
+
+	manager *PolicyManager
+}
+
+func newNotIndexedPolicyProvider(manager *PolicyManager) *notIndexedPolicyProvider {
+	return &notIndexedPolicyProvider{
+		PolicyProvider:             manager,
+		ResourceDefinitionProvider: manager,
+		ActionHierarchyProvider:    manager,
+		manager:                    manager,
+	}
+}
+
+// getSnapshot - returns PolicyManager's snapshot without its PolicyIndex, so Roles are read from
+// the snapshot the same way, but evaluated one by one.
+func (p *notIndexedPolicyProvider) getSnapshot() *policySnapshot {
+	snapshot := p.manager.getSnapshot()
+
+	return &policySnapshot{
+		policy:  snapshot.policy,
+		version: snapshot.version,
+	}
+}
+
+type policyIndexSuite struct {
+	suite.Suite
+}
+
+func TestPolicyIndexSuite(t *testing.T) {
+	suite.Run(t, new(policyIndexSuite))
+}
+
+func (s *policyIndexSuite) getAncestorsNames(index *PolicyIndex, roleID string) []string {
+	names := []string{}
+
+	for _, ancestor := range index.getAncestors(roleID) {
+		name := ancestor.name
+
+		if ancestor.isCycle {
+			name += " (cycle)"
+		} else if ancestor.role == nil {
+			name += " (missing)"
+		}
+
+		names = append(names, name)
+	}
+
+	return names
+}
+
+func (s *policyIndexSuite) TestNewPolicyIndex() {
+	testPolicy := &PolicyDefinition{
+		Roles: Roles{
+			"User":      {ID: "User", Parents: []string{"Reader", "Commenter"}},
+			"Reader":    {ID: "Reader", Parents: []string{"Guest"}},
+			"Commenter": {ID: "Commenter", Parents: []string{"Guest", "Missing"}},
+			"Guest":     {ID: "Guest", Parents: []string{"User"}},
+		},
+	}
+
+	index := NewPolicyIndex(testPolicy)
+
+	assert.Equal(
+		s.T(),
+		[]string{"User", "Reader", "Guest", "User (cycle)", "Commenter", "Missing (missing)"},
+		s.getAncestorsNames(index, "User"),
+	)
+
+	ancestors := index.getAncestors("User")
+
+	assert.Equal(s.T(), []string{"User", "Reader", "Guest"}, ancestors[2].path)
+	assert.Equal(s.T(), []string{"User", "Reader", "Guest"}, ancestors[3].path)
+	assert.Equal(s.T(), []string{"User", "Commenter", "Missing"}, ancestors[5].path)
+
+	assert.Equal(s.T(), []string{"NotDefined (missing)"}, s.getAncestorsNames(index, "NotDefined"))
+
+	// Empty policy.
+	index = NewPolicyIndex(nil)
+
+	assert.Equal(s.T(), []string{basicRoleOneName + " (missing)"}, s.getAncestorsNames(index, basicRoleOneName))
+}
+
+func (s *policyIndexSuite) TestNewPolicyIndex_DiamondHierarchy() {
+	depth := 30
+	index := NewPolicyIndex(getDiamondHierarchyPolicy(depth))
+
+	// Every Role is listed once, even though there are 2^30 inheritance paths to the last level.
+	names := s.getAncestorsNames(index, "Role0")
+
+	assert.Len(s.T(), names, 2*depth+1)
+	assert.Equal(s.T(), []string{"Role0", "Role1A", "Role2A", "Role3A"}, names[:4])
+	assert.Equal(s.T(), fmt.Sprintf("Role%dB", depth), names[depth+1])
+
+	testAdapter := new(storageAdapterMock)
+	testAdapter.On("LoadPolicy").Return(getDiamondHierarchyPolicy(depth), nil)
+
+	policyManager, err := NewPolicyManager(testAdapter, false)
+
+	assert.Nil(s.T(), err)
+
+	manager := NewAccessManager(policyManager)
+
+	assert.Nil(s.T(), manager.Authorize(&AccessRequest{
+		Subject:  UseSubject([]string{"Role0"}),
+		Resource: UseResource(basicResourceOneName),
+		Actions:  []string{readAction},
+	}))
+}
+
+func (s *policyIndexSuite) TestGetPermissions() {
+	testRead := &Permission{Action: readAction}
+	testReadPattern := &Permission{Action: "read:*"}
+	testReadAll := &Permission{Action: "read:all"}
+	testAll := &Permission{Action: "*"}
+	testUpdate := &Permission{Action: updateAction}
+	testDeleteDeny := &Permission{Action: deleteAction, Effect: DenyEffect}
+	testDenyReadPattern := &Permission{Action: "read:s*", Effect: DenyEffect}
+	testBilling := &Permission{Action: readAction}
+	testBillingDeny := &Permission{Action: "share:*", Effect: DenyEffect}
+	testAllDeny := &Permission{Action: "share:*", Effect: DenyEffect}
+	testAllRead := &Permission{Action: readAction}
+
+	testRole := &Role{
+		ID: basicRoleOneName,
+		Grants: GrantsMap{
+			basicResourceOneName: {testRead, testReadPattern, testAll, testUpdate, testReadAll, testDeleteDeny, testDenyReadPattern},
+			"billing/*":          {testBilling, testBillingDeny},
+			"*":                  {testAllDeny, testAllRead},
+		},
+	}
+
+	index := NewPolicyIndex(&PolicyDefinition{Roles: Roles{basicRoleOneName: testRole}})
+
+	role := index.roles[basicRoleOneName]
+
+	testCases := []struct {
+		resourceName string
+		actions      []string
+	}{
+		{basicResourceOneName, []string{readAction}},
+		{basicResourceOneName, []string{"read:all"}},
+		{basicResourceOneName, []string{"read:own"}},
+		{basicResourceOneName, []string{"share"}},
+		{basicResourceOneName, []string{deleteAction, updateAction, readAction}},
+		{basicResourceOneName, []string{"read:own", "read:all"}},
+		{"billing/invoices", []string{readAction}},
+		{"billing/invoices", []string{updateAction}},
+		{"billing/invoices", []string{"share:link"}},
+		{basicResourceOneName, []string{"read:secret"}},
+		{basicResourceOneName, []string{"share:link", readAction}},
+		{"NotGranted", []string{readAction}},
+		{"NotGranted", []string{"share:link"}},
+	}
+
+	for _, testCase := range testCases {
+		expected := testRole.Grants.forActions(testCase.resourceName, testCase.actions)
+
+		assert.Equal(
+			s.T(),
+			append(Permissions{}, expected...),
+			append(Permissions{}, role.getPermissions(testCase.resourceName, testCase.actions)...),
+			fmt.Sprintf("%s %v", testCase.resourceName, testCase.actions),
+		)
+	}
+}
+
+func (s *policyIndexSuite) TestAuthorize_WithIndex() {
+	testPolicy := &PolicyDefinition{
+		Resources: ResourceDefinitions{
+			basicResourceOneName: {Parent: "Project"},
+		},
+		Actions: &ActionHierarchy{
+			Global: ActionImplications{
+				updateAction: {readAction},
+			},
+		},
+		Roles: Roles{
+			"Owner": {
+				ID:      "Owner",
+				Parents: []string{"Editor", "Reader"},
+				Grants: GrantsMap{
+					basicResourceOneName: {
+						&Permission{Action: deleteAction, Conditions: Conditions{
+							&EqualCondition{
+								Left:  &ValueDescriptor{Source: ResourceField, Field: "CreatedBy"},
+								Right: &ValueDescriptor{Source: SubjectField, Field: "ID"},
+							},
+						}},
+					},
+				},
+			},
+			"Editor": {
+				ID:      "Editor",
+				Parents: []string{"Reader"},
+				Grants: GrantsMap{
+					basicResourceOneName: {&Permission{Action: updateAction}},
+					"*":                  {&Permission{Action: "share:*", Effect: DenyEffect}},
+				},
+			},
+			"Reader": {
+				ID:      "Reader",
+				Parents: []string{"Owner"},
+				Grants: GrantsMap{
+					"Project": {&Permission{Action: "comment"}},
+				},
+			},
+			"Guest": {
+				ID:      "Guest",
+				Parents: []string{"Missing"},
+			},
+		},
+	}
+
+	testAdapter := new(storageAdapterMock)
+	testAdapter.On("LoadPolicy").Return(testPolicy, nil)
+
+	policyManager, err := NewPolicyManager(testAdapter, false)
+
+	assert.Nil(s.T(), err)
+
+	indexedManager := NewAccessManager(policyManager)
+	manager := NewAccessManager(newNotIndexedPolicyProvider(policyManager))
+
+	testResource := &resourceMock{CreatedBy: "user-1"}
+	testResource.On("GetResourceName").Return(basicResourceOneName)
+
+	testCases := []struct {
+		roles   []string
+		actions []string
+	}{
+		{[]string{"Owner"}, []string{readAction, updateAction, deleteAction, "comment", "share:link", "archive"}},
+		{[]string{"Editor"}, []string{readAction, updateAction, deleteAction, "comment"}},
+		{[]string{"Reader"}, []string{readAction, "comment"}},
+		{[]string{"Reader", "Editor"}, []string{readAction, deleteAction}},
+		{[]string{"Guest"}, []string{readAction}},
+		{[]string{"NotDefined"}, []string{readAction}},
+	}
+
+	for _, testCase := range testCases {
+		for _, subjectID := range []string{"user-1", "user-2"} {
+			testSubject := &subjectMock{ID: subjectID}
+			testSubject.On("GetRoles").Return(testCase.roles)
+
+			testRequest := &AccessRequest{
+				Subject:  testSubject,
+				Resource: testResource,
+				Actions:  testCase.actions,
+				Trace:    true,
+			}
+
+			message := fmt.Sprintf("%v %v %s", testCase.roles, testCase.actions, subjectID)
+
+			expected, expectedErr := manager.Evaluate(testRequest)
+			decision, err := indexedManager.Evaluate(testRequest)
+
+			if expectedErr != nil {
+				assert.Equal(s.T(), expectedErr, err, message)
+				assert.Nil(s.T(), decision, message)
+
+				continue
+			}
+
+			assert.Nil(s.T(), err, message)
+			assert.Equal(s.T(), expected.Allowed, decision.Allowed, message)
+			assert.Equal(s.T(), expected.Trace.String(), decision.Trace.String(), message)
+			assert.Equal(s.T(), fmt.Sprint(expected.Reasons()), fmt.Sprint(decision.Reasons()), message)
+
+			for i, actionDecision := range decision.Actions {
+				assert.Equal(s.T(), expected.Actions[i].Effect, actionDecision.Effect, message)
+				assert.Equal(s.T(), expected.Actions[i].Permissions, actionDecision.Permissions, message)
+			}
+		}
+	}
+
+	// Index follows policy changes.
+	testSubject := &subjectMock{ID: "user-2"}
+	testSubject.On("GetRoles").Return([]string{"Guest"})
+
+	assert.Nil(s.T(), policyManager.AddRole(&Role{
+		ID: "Missing",
+		Grants: GrantsMap{
+			basicResourceOneName: {&Permission{Action: readAction}},
+		},
+	}))
+
+	err = indexedManager.Authorize(&AccessRequest{
+		Subject:  testSubject,
+		Resource: testResource,
+		Actions:  []string{readAction},
+	})
+
+	assert.Nil(s.T(), err)
+}
+
+func (s *policyIndexSuite) TestAuthorize_WithIndexWithoutDeny() {
+	testPolicy := &PolicyDefinition{
+		Roles: Roles{
+			"Reader": {
+				ID:      "Reader",
+				Parents: []string{"Missing"},
+				Grants: GrantsMap{
+					basicResourceOneName: {&Permission{Action: readAction}},
+				},
+			},
+			"Editor": {
+				ID: "Editor",
+				Grants: GrantsMap{
+					basicResourceOneName: {&Permission{Action: updateAction}},
+				},
+			},
+		},
+	}
+
+	testAdapter := new(storageAdapterMock)
+	testAdapter.On("LoadPolicy").Return(testPolicy, nil)
+
+	policyManager, err := NewPolicyManager(testAdapter, false)
+
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), policyManager.GetPolicyIndex().hasDeny)
+
+	manager := NewAccessManager(policyManager)
+
+	testRequest := &AccessRequest{
+		Subject:  UseSubject([]string{"Reader", "NotDefined"}),
+		Resource: UseResource(basicResourceOneName),
+		Actions:  []string{readAction},
+	}
+
+	// Without deny Permissions, the first allowing Permission decides - missing parent
+	// and Role are never reached.
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+
+	// Once any deny Permission is defined, all Roles and their Parents are evaluated - missing ones
+	// are skipped, as the Action has already been allowed, unless strict evaluation is enabled.
+	assert.Nil(s.T(), policyManager.AddPermission("Editor", basicResourceOneName, &Permission{
+		Action: deleteAction,
+		Effect: DenyEffect,
+	}))
+
+	assert.True(s.T(), policyManager.GetPolicyIndex().hasDeny)
+	assert.Nil(s.T(), manager.Authorize(testRequest))
+
+	manager.SetStrictEvaluation(true)
+
+	assert.IsType(s.T(), new(RoleNotFoundError), manager.Authorize(testRequest))
+}
+
+// getDeepHierarchyPolicy - returns a policy with a chain of Roles of given depth,
+// where only the last one grants the Permission.
+func getDeepHierarchyPolicy(depth int) *PolicyDefinition {
+	policy := &PolicyDefinition{
+		Roles: Roles{},
+	}
+
+	for i := 0; i < depth; i++ {
+		role := &Role{
+			ID:     fmt.Sprintf("Role%d", i),
+			Grants: GrantsMap{},
+		}
+
+		if i < depth-1 {
+			role.Parents = []string{fmt.Sprintf("Role%d", i+1)}
+		} else {
+			role.Grants[basicResourceOneName] = Permissions{&Permission{Action: readAction}}
+		}
+
+		policy.Roles[role.ID] = role
+	}
+
+	return policy
+}
+
+// getDiamondHierarchyPolicy - returns a policy with Role0 inheriting from a lattice of given depth,
+// with two Roles on every level, both inheriting from both Roles of the next level. Only Roles
+// of the last level grant the Permission.
+func getDiamondHierarchyPolicy(depth int) *PolicyDefinition {
+	policy := &PolicyDefinition{
+		Roles: Roles{
+			"Role0": {ID: "Role0", Parents: []string{"Role1A", "Role1B"}},
+		},
+	}
+
+	for i := 1; i <= depth; i++ {
+		for _, suffix := range []string{"A", "B"} {
+			role := &Role{
+				ID:     fmt.Sprintf("Role%d%s", i, suffix),
+				Grants: GrantsMap{},
+			}
+
+			if i < depth {
+				role.Parents = []string{fmt.Sprintf("Role%dA", i+1), fmt.Sprintf("Role%dB", i+1)}
+			} else {
+				role.Grants[basicResourceOneName] = Permissions{&Permission{Action: readAction}}
+			}
+
+			policy.Roles[role.ID] = role
+		}
+	}
+
+	return policy
+}
+
+// getLargeGrantsPolicy - returns a policy with a single Role, granting given number
+// of Permissions for a single Resource, with the checked one being the last.
+func getLargeGrantsPolicy(size int) *PolicyDefinition {
+	permissions := Permissions{}
+
+	for i := 0; i < size-1; i++ {
+		permissions = append(permissions, &Permission{Action: fmt.Sprintf("action%d", i)})
+	}
+
+	permissions = append(permissions, &Permission{Action: readAction})
+
+	return &PolicyDefinition{
+		Roles: Roles{
+			"Role0": {
+				ID:     "Role0",
+				Grants: GrantsMap{basicResourceOneName: permissions},
+			},
+		},
+	}
+}
+
+func benchmarkAuthorize(b *testing.B, policy *PolicyDefinition, indexed bool) {
+	testAdapter := new(storageAdapterMock)
+	testAdapter.On("LoadPolicy").Return(policy, nil)
+
+	policyManager, err := NewPolicyManager(testAdapter, false)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var manager *AccessManager
+
+	if indexed {
+		manager = NewAccessManager(policyManager)
+	} else {
+		manager = NewAccessManager(newNotIndexedPolicyProvider(policyManager))
+	}
+
+	request := &AccessRequest{
+		Subject:  UseSubject([]string{"Role0"}),
+		Resource: UseResource(basicResourceOneName),
+		Actions:  []string{readAction},
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := manager.Authorize(request); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAuthorize_DeepHierarchy(b *testing.B) {
+	for _, depth := range []int{10, 50} {
+		policy := getDeepHierarchyPolicy(depth)
+
+		b.Run(fmt.Sprintf("Depth%d/NotIndexed", depth), func(b *testing.B) {
+			benchmarkAuthorize(b, policy, false)
+		})
+
+		b.Run(fmt.Sprintf("Depth%d/Indexed", depth), func(b *testing.B) {
+			benchmarkAuthorize(b, policy, true)
+		})
+	}
+}
+
+func BenchmarkAuthorize_LargeGrants(b *testing.B) {
+	for _, size := range []int{100, 1000} {
+		policy := getLargeGrantsPolicy(size)
+
+		b.Run(fmt.Sprintf("Permissions%d/NotIndexed", size), func(b *testing.B) {
+			benchmarkAuthorize(b, policy, false)
+		})
+
+		b.Run(fmt.Sprintf("Permissions%d/Indexed", size), func(b *testing.B) {
+			benchmarkAuthorize(b, policy, true)
+		})
+	}
+}
+
+func BenchmarkNewPolicyIndex_DiamondHierarchy(b *testing.B) {
+	for _, depth := range []int{10, 20} {
+		policy := getDiamondHierarchyPolicy(depth)
+
+		b.Run(fmt.Sprintf("Depth%d", depth), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				NewPolicyIndex(policy)
+			}
+		})
+	}
+}
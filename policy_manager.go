@@ -1,9 +1,16 @@
 package restrict
 
-import "sync"
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
 
 // PolicyManager - an entity responsible for managing PolicyDefinition. It uses passed StorageAdapter
 // for policy persistence.
+// Loaded policy is kept as an immutable snapshot - every change is made on a deep copy of the policy,
+// which is then published as a new snapshot. Therefore, reading methods never lock, and always
+// see a consistent policy.
 type PolicyManager struct {
 	// StorageAdapter used to load and save policy.
 	adapter StorageAdapter
@@ -12,13 +19,24 @@ type PolicyManager struct {
 	// the policy every time any change is made.
 	autoUpdate bool
 
-	// PolicyDefinition currently loaded into memory.
-	policy *PolicyDefinition
+	// Currently published policySnapshot.
+	snapshot atomic.Value
 
-	// PolicyManager should thread-safe for writing operations, therefore it uses RWMutex.
+	// PolicyManager should be thread-safe for writing operations, therefore writers are serialized
+	// with RWMutex. Readers do not lock, as published snapshots are never modified.
 	sync.RWMutex
 }
 
+// policySnapshot - immutable state of PolicyManager, published on every change of the policy.
+type policySnapshot struct {
+	// PolicyDefinition currently loaded into memory.
+	policy *PolicyDefinition
+	// PolicyIndex compiled from the policy.
+	index *PolicyIndex
+	// version - incremented every time the policy changes.
+	version uint64
+}
+
 // NewPolicyManager - returns new PolicyManager instance and loads PolicyDefinition
 // using passed StorageAdapter.
 func NewPolicyManager(adapter StorageAdapter, autoUpdate bool) (*PolicyManager, error) {
@@ -37,48 +55,121 @@ func NewPolicyManager(adapter StorageAdapter, autoUpdate bool) (*PolicyManager,
 
 // LoadPolicy - proxy method for loading the policy via StorageAdapter set
 // when creating PolicyManager instance.
-// Calling this method will override currently loaded policy.
+// Calling this method will override currently loaded policy, unless loaded policy is not valid.
 func (pm *PolicyManager) LoadPolicy() error {
 	pm.Lock()
 	defer pm.Unlock()
 
-	policy, err := pm.adapter.LoadPolicy()
+	loaded, err := pm.adapter.LoadPolicy()
 	if err != nil {
 		return err
 	}
 
-	pm.policy = policy
+	// StorageAdapter can keep the policy it returns, so it's copied before presets are applied.
+	policy := loaded.copy()
+
+	if err := pm.applyPresets(policy); err != nil {
+		return err
+	}
+
+	if err := pm.validateEffects(policy); err != nil {
+		return err
+	}
+
+	if err := pm.prepareConditions(policy); err != nil {
+		return err
+	}
 
-	if err := pm.applyPresets(); err != nil {
+	if err := policy.Resources.validate(); err != nil {
 		return err
 	}
 
+	if err := policy.Actions.validate(); err != nil {
+		return err
+	}
+
+	pm.publish(policy)
+
 	return nil
 }
 
 // SavePolicy - proxy method for saving the policy via StorageAdapter set
 // when creating PolicyManager instance.
 func (pm *PolicyManager) SavePolicy() error {
-	return pm.adapter.SavePolicy(pm.policy)
+	return pm.adapter.SavePolicy(pm.getSnapshot().policy.copy())
 }
 
-// GetPolicy - returns currently loaded PolicyDefinition.
+// GetPolicyVersion - returns current version of the policy, incremented every time the policy
+// is loaded or changed with any of PolicyManager's methods.
+func (pm *PolicyManager) GetPolicyVersion() uint64 {
+	return pm.getSnapshot().version
+}
+
+// GetPolicyIndex - returns PolicyIndex compiled from currently loaded policy.
+func (pm *PolicyManager) GetPolicyIndex() *PolicyIndex {
+	return pm.getSnapshot().index
+}
+
+// GetPolicy - returns a copy of currently loaded PolicyDefinition. Changes made to the copy
+// do not affect the policy - use PolicyManager's methods to change it.
 func (pm *PolicyManager) GetPolicy() *PolicyDefinition {
-	pm.RLock()
-	defer pm.RUnlock()
+	return pm.getSnapshot().policy.copy()
+}
+
+// getSnapshot - returns currently published policySnapshot.
+func (pm *PolicyManager) getSnapshot() *policySnapshot {
+	if snapshot, ok := pm.snapshot.Load().(*policySnapshot); ok {
+		return snapshot
+	}
 
-	return pm.policy
+	// No policy has been loaded yet.
+	return &policySnapshot{
+		policy: &PolicyDefinition{},
+		index:  NewPolicyIndex(nil),
+	}
+}
+
+// publish - publishes new policySnapshot of given policy, with its PolicyIndex and incremented version.
+// Published policy cannot be modified anymore.
+func (pm *PolicyManager) publish(policy *PolicyDefinition) {
+	pm.snapshot.Store(&policySnapshot{
+		policy:  policy,
+		index:   NewPolicyIndex(policy),
+		version: pm.getSnapshot().version + 1,
+	})
+}
+
+// update - applies given change to a copy of currently loaded policy, and publishes the copy
+// if the change succeeds. Saves with StorageAdapter if autoUpdate is set to true.
+func (pm *PolicyManager) update(change func(policy *PolicyDefinition) error) error {
+	pm.Lock()
+	defer pm.Unlock()
+
+	policy := pm.getSnapshot().policy.copy()
+
+	if err := change(policy); err != nil {
+		return err
+	}
+
+	pm.publish(policy)
+
+	if pm.autoUpdate {
+		// Published policy is read concurrently - StorageAdapter gets its own copy.
+		return pm.adapter.SavePolicy(policy.copy())
+	}
+
+	return nil
 }
 
 // applyPresets - applies defined presets to Permissions that are not yet merged.
-func (pm *PolicyManager) applyPresets() error {
+func (pm *PolicyManager) applyPresets(policy *PolicyDefinition) error {
 	// For every Role, iterate over all Permissions for given Resource and
 	// merge Permission with it's preset if defined.
-	for _, role := range pm.policy.Roles {
+	for _, role := range policy.Roles {
 		for _, grants := range role.Grants {
 			for _, permission := range grants {
 				if permission.Preset != "" {
-					if err := pm.applyPreset(permission); err != nil {
+					if err := pm.applyPreset(policy, permission); err != nil {
 						return err
 					}
 				}
@@ -90,8 +181,8 @@ func (pm *PolicyManager) applyPresets() error {
 }
 
 // applyPreset - applies defined preset to Permission.
-func (pm *PolicyManager) applyPreset(permission *Permission) error {
-	preset := pm.policy.PermissionPresets[permission.Preset]
+func (pm *PolicyManager) applyPreset(policy *PolicyDefinition, permission *Permission) error {
+	preset := policy.PermissionPresets[permission.Preset]
 
 	// If given preset does not exist, return an error.
 	if preset == nil {
@@ -104,12 +195,83 @@ func (pm *PolicyManager) applyPreset(permission *Permission) error {
 	return nil
 }
 
-// GetRole - returns a Role with given ID from currently loaded PolicyDefiniton.
+// validateEffects - returns UnknownEffectError if any of Permission presets or Roles' Permissions
+// has an unknown Effect.
+func (pm *PolicyManager) validateEffects(policy *PolicyDefinition) error {
+	for _, preset := range policy.PermissionPresets {
+		if err := preset.Effect.validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, role := range policy.Roles {
+		if err := pm.validateRoleEffects(role); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateRoleEffects - returns UnknownEffectError if any of Permissions granted to given Role
+// has an unknown Effect.
+func (pm *PolicyManager) validateRoleEffects(role *Role) error {
+	for _, grants := range role.Grants {
+		for _, permission := range grants {
+			if err := permission.Effect.validate(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// prepareConditions - prepares Conditions of all Permission presets and Roles' Permissions.
+func (pm *PolicyManager) prepareConditions(policy *PolicyDefinition) error {
+	for _, preset := range policy.PermissionPresets {
+		if err := preset.Conditions.prepare(); err != nil {
+			return err
+		}
+	}
+
+	for _, role := range policy.Roles {
+		if err := pm.prepareRoleConditions(role); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// prepareRoleConditions - prepares Conditions of all Permissions granted to given Role.
+func (pm *PolicyManager) prepareRoleConditions(role *Role) error {
+	for _, grants := range role.Grants {
+		for _, permission := range grants {
+			if err := permission.Conditions.prepare(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetRole - returns a copy of a Role with given ID from currently loaded PolicyDefiniton.
+// Changes made to the copy do not affect the policy - use UpdateRole to change it.
 func (pm *PolicyManager) GetRole(roleID string) (*Role, error) {
-	pm.RLock()
-	defer pm.RUnlock()
+	role, err := pm.getSnapshotRole(roleID)
+	if err != nil {
+		return nil, err
+	}
 
-	role := pm.getRole(roleID)
+	return role.copy(), nil
+}
+
+// getSnapshotRole - returns a Role with given ID from current policy snapshot, without copying it.
+// Returned Role is shared with the snapshot, and cannot be modified.
+func (pm *PolicyManager) getSnapshotRole(roleID string) (*Role, error) {
+	role := pm.getRole(pm.getSnapshot().policy, roleID)
 	// If given Role does not exists, return an error.
 	if role == nil {
 		return nil, newRoleNotFoundError(roleID)
@@ -118,54 +280,95 @@ func (pm *PolicyManager) GetRole(roleID string) (*Role, error) {
 	return role, nil
 }
 
+// GetRoleContext - works as GetRole, but returns context's error if given context is already done.
+func (pm *PolicyManager) GetRoleContext(ctx context.Context, roleID string) (*Role, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return pm.GetRole(roleID)
+}
+
+// GetResourceDefinition - returns a copy of ResourceDefinition for given Resource name from currently
+// loaded PolicyDefinition, or nil if Resource has no definition.
+func (pm *PolicyManager) GetResourceDefinition(resourceName string) *ResourceDefinition {
+	policy := pm.getSnapshot().policy
+
+	if policy.Resources == nil {
+		return nil
+	}
+
+	return policy.Resources[resourceName].copy()
+}
+
+// GetImplyingActions - returns all Actions implying given Action for given Resource, directly or not,
+// according to ActionHierarchy of currently loaded PolicyDefinition.
+func (pm *PolicyManager) GetImplyingActions(resourceName, action string) []string {
+	return append([]string{}, pm.getSnapshot().index.getImplyingActions(resourceName, action)...)
+}
+
+// GetImpliedActions - returns all Actions implied by given Action for given Resource, directly or not,
+// according to ActionHierarchy of currently loaded PolicyDefinition.
+func (pm *PolicyManager) GetImpliedActions(resourceName, action string) []string {
+	return pm.getSnapshot().policy.Actions.getImpliedActions(resourceName, action)
+}
+
+// GetRolesGranting - returns all Roles that are granted given Action on given Resource, directly or through
+// their parents, along with copies of matching Permissions (and their Conditions) and inheritance paths.
+// Permissions granted for Actions implying given Action, or for Resource's ancestors declared in
+// ResourceDefinitions, are taken into account. Roles with deny Permissions only are omitted.
+func (pm *PolicyManager) GetRolesGranting(action, resourceName string) ([]*RoleAccess, error) {
+	snapshot := pm.getSnapshot()
+
+	return snapshot.policy.getRolesAccess(snapshot.index, action, resourceName)
+}
+
 // AddRole - adds a new role to the policy.
 // Saves with StorageAdapter if autoUpdate is set to true.
 func (pm *PolicyManager) AddRole(role *Role) error {
-	pm.Lock()
-	defer pm.Unlock()
-
-	// Check if role already exists - if yes, return an error.
-	if r := pm.getRole(role.ID); r != nil {
-		return newRoleAlreadyExistsError(role.ID)
-	}
+	return pm.update(func(policy *PolicyDefinition) error {
+		// Check if role already exists - if yes, return an error.
+		if r := pm.getRole(policy, role.ID); r != nil {
+			return newRoleAlreadyExistsError(role.ID)
+		}
 
-	pm.policy.Roles[role.ID] = role
+		policy.Roles[role.ID] = role.copy()
 
-	// Since new Permissions with presets could be added, run ApplyPresets.
-	if err := pm.applyPresets(); err != nil {
-		return err
-	}
+		// Since new Permissions with presets could be added, run ApplyPresets.
+		if err := pm.applyPresets(policy); err != nil {
+			return err
+		}
 
-	if pm.autoUpdate {
-		return pm.adapter.SavePolicy(pm.policy)
-	}
+		if err := pm.validateRoleEffects(policy.Roles[role.ID]); err != nil {
+			return err
+		}
 
-	return nil
+		return pm.prepareRoleConditions(policy.Roles[role.ID])
+	})
 }
 
 // UpdateRole - updates existing Role in currently loaded policy.
 // Saves with StorageAdapter if autoUpdate is set to true.
 func (pm *PolicyManager) UpdateRole(role *Role) error {
-	pm.Lock()
-	defer pm.Unlock()
-
-	// If given Role does not exists, return an error.
-	if r := pm.getRole(role.ID); r == nil {
-		return newRoleNotFoundError(role.ID)
-	}
+	return pm.update(func(policy *PolicyDefinition) error {
+		// If given Role does not exists, return an error.
+		if r := pm.getRole(policy, role.ID); r == nil {
+			return newRoleNotFoundError(role.ID)
+		}
 
-	pm.policy.Roles[role.ID] = role
+		policy.Roles[role.ID] = role.copy()
 
-	// Since new Permissions with presets could be added, run ApplyPresets.
-	if err := pm.applyPresets(); err != nil {
-		return err
-	}
+		// Since new Permissions with presets could be added, run ApplyPresets.
+		if err := pm.applyPresets(policy); err != nil {
+			return err
+		}
 
-	if pm.autoUpdate {
-		return pm.adapter.SavePolicy(pm.policy)
-	}
+		if err := pm.validateRoleEffects(policy.Roles[role.ID]); err != nil {
+			return err
+		}
 
-	return nil
+		return pm.prepareRoleConditions(policy.Roles[role.ID])
+	})
 }
 
 // UpsertRole - updates a Role if exists, adds new Role otherwise.
@@ -185,55 +388,51 @@ func (pm *PolicyManager) UpsertRole(role *Role) error {
 // DeleteRole - removes a Role with given ID.
 // Saves with StorageAdapter if autoUpdate is set to true.
 func (pm *PolicyManager) DeleteRole(roleID string) error {
-	pm.Lock()
-	defer pm.Unlock()
-
-	if pm.policy.Roles == nil {
-		pm.policy.Roles = Roles{}
-	}
-
-	// If Role with given ID does not exist, return an error.
-	if r := pm.getRole(roleID); r == nil {
-		return newRoleNotFoundError(roleID)
-	}
+	return pm.update(func(policy *PolicyDefinition) error {
+		if policy.Roles == nil {
+			policy.Roles = Roles{}
+		}
 
-	delete(pm.policy.Roles, roleID)
+		// If Role with given ID does not exist, return an error.
+		if r := pm.getRole(policy, roleID); r == nil {
+			return newRoleNotFoundError(roleID)
+		}
 
-	if pm.autoUpdate {
-		return pm.adapter.SavePolicy(pm.policy)
-	}
+		delete(policy.Roles, roleID)
 
-	return nil
+		return nil
+	})
 }
 
 // AddPermission - adds a new Permission for the Role and Resource with passed ids.
 // Saves with StorageAdapter if autoUpdate is set to true.
 func (pm *PolicyManager) AddPermission(roleID, resourceID string, permission *Permission) error {
-	pm.Lock()
-	defer pm.Unlock()
+	return pm.update(func(policy *PolicyDefinition) error {
+		role := pm.getRole(policy, roleID)
+		// If role does not exist, return an error.
+		if role == nil {
+			return newRoleNotFoundError(roleID)
+		}
 
-	role := pm.getRole(roleID)
-	// If role does not exist, return an error.
-	if role == nil {
-		return newRoleNotFoundError(roleID)
-	}
+		pm.ensurePermissionsArray(role, resourceID)
 
-	pm.ensurePermissionsArray(role, resourceID)
+		added := permission.copy()
 
-	role.Grants[resourceID] = append(role.Grants[resourceID], permission)
+		role.Grants[resourceID] = append(role.Grants[resourceID], added)
 
-	// If added Permission has preset defined, apply it immediately.
-	if permission.Preset != "" {
-		if err := pm.applyPreset(permission); err != nil {
-			return err
+		// If added Permission has preset defined, apply it immediately.
+		if added.Preset != "" {
+			if err := pm.applyPreset(policy, added); err != nil {
+				return err
+			}
 		}
-	}
 
-	if pm.autoUpdate {
-		return pm.adapter.SavePolicy(pm.policy)
-	}
+		if err := added.Effect.validate(); err != nil {
+			return err
+		}
 
-	return nil
+		return added.Conditions.prepare()
+	})
 }
 
 // DeletePermission - removes a Permission with given name for Role and Resource with
@@ -241,29 +440,24 @@ func (pm *PolicyManager) AddPermission(roleID, resourceID string, permission *Pe
 // ALL of the Permissions that share this action.
 // Saves with StorageAdapter if autoUpdate is set to true.
 func (pm *PolicyManager) DeletePermission(roleID, resourceID, action string) error {
-	pm.Lock()
-	defer pm.Unlock()
+	return pm.update(func(policy *PolicyDefinition) error {
+		role := pm.getRole(policy, roleID)
 
-	role := pm.getRole(roleID)
-
-	// If role does not exist, return an error.
-	if role == nil {
-		return newRoleNotFoundError(roleID)
-	}
+		// If role does not exist, return an error.
+		if role == nil {
+			return newRoleNotFoundError(roleID)
+		}
 
-	pm.ensurePermissionsArray(role, resourceID)
+		pm.ensurePermissionsArray(role, resourceID)
 
-	for i, permission := range role.Grants[resourceID] {
-		if permission.Action == action {
-			role.Grants[resourceID] = pm.deletePermissionFromSlice(role.Grants[resourceID], i)
+		for i, permission := range role.Grants[resourceID] {
+			if permission.Action == action {
+				role.Grants[resourceID] = pm.deletePermissionFromSlice(role.Grants[resourceID], i)
+			}
 		}
-	}
-
-	if pm.autoUpdate {
-		return pm.adapter.SavePolicy(pm.policy)
-	}
 
-	return nil
+		return nil
+	})
 }
 
 // deletePermissionFromSlice - helper function for removing Permission under given index
@@ -283,45 +477,55 @@ func (pm *PolicyManager) deletePermissionFromSlice(grants []*Permission, index i
 // AddPermissionPreset - adds new Permission preset to PolicyDefinition.
 // Saves with StorageAdapter if autoUpdate is set to true.
 func (pm *PolicyManager) AddPermissionPreset(name string, preset *Permission) error {
-	pm.Lock()
-	defer pm.Unlock()
+	return pm.update(func(policy *PolicyDefinition) error {
+		// If there is already a preset with given name, return an error.
+		if p := pm.getPermissionPreset(policy, name); p != nil {
+			return newPermissionPresetAlreadyExistsError(name)
+		}
 
-	// If there is already a preset with given name, return an error.
-	if p := pm.getPermissionPreset(name); p != nil {
-		return newPermissionPresetAlreadyExistsError(name)
-	}
+		added := preset.copy()
 
-	if pm.policy.PermissionPresets == nil {
-		pm.policy.PermissionPresets = PermissionPresets{}
-	}
+		if err := added.Effect.validate(); err != nil {
+			return err
+		}
 
-	pm.policy.PermissionPresets[name] = preset
+		if err := added.Conditions.prepare(); err != nil {
+			return err
+		}
 
-	if pm.autoUpdate {
-		return pm.adapter.SavePolicy(pm.policy)
-	}
+		if policy.PermissionPresets == nil {
+			policy.PermissionPresets = PermissionPresets{}
+		}
 
-	return nil
+		policy.PermissionPresets[name] = added
+
+		return nil
+	})
 }
 
 // UpdatePermissionPreset - updates a Permission preset in PolicyDefinition.
 // Saves with StorageAdapter if autoUpdate is set to true.
 func (pm *PolicyManager) UpdatePermissionPreset(name string, preset *Permission) error {
-	pm.Lock()
-	defer pm.Unlock()
+	return pm.update(func(policy *PolicyDefinition) error {
+		// If there is no preset with given name, return an error.
+		if p := pm.getPermissionPreset(policy, name); p == nil {
+			return newPermissionPresetNotFoundError(name)
+		}
 
-	// If there is no preset with given name, return an error.
-	if p := pm.getPermissionPreset(name); p == nil {
-		return newPermissionPresetNotFoundError(name)
-	}
+		updated := preset.copy()
 
-	pm.policy.PermissionPresets[name] = preset
+		if err := updated.Effect.validate(); err != nil {
+			return err
+		}
 
-	if pm.autoUpdate {
-		return pm.adapter.SavePolicy(pm.policy)
-	}
+		if err := updated.Conditions.prepare(); err != nil {
+			return err
+		}
 
-	return nil
+		policy.PermissionPresets[name] = updated
+
+		return nil
+	})
 }
 
 // UpsertPermissionPreset - updates Permission preset if exists, adds a new otherwise.
@@ -341,21 +545,16 @@ func (pm *PolicyManager) UpsertPermissionPreset(name string, preset *Permission)
 // DeletePermissionPreset - removes Permission preset with given name.
 // Saves with StorageAdapter if autoUpdate is set to true.
 func (pm *PolicyManager) DeletePermissionPreset(name string) error {
-	pm.Lock()
-	defer pm.Unlock()
-
-	// If there is no preset with given name, return an error.
-	if p := pm.getPermissionPreset(name); p == nil {
-		return newPermissionPresetNotFoundError(name)
-	}
-
-	delete(pm.policy.PermissionPresets, name)
+	return pm.update(func(policy *PolicyDefinition) error {
+		// If there is no preset with given name, return an error.
+		if p := pm.getPermissionPreset(policy, name); p == nil {
+			return newPermissionPresetNotFoundError(name)
+		}
 
-	if pm.autoUpdate {
-		return pm.adapter.SavePolicy(pm.policy)
-	}
+		delete(policy.PermissionPresets, name)
 
-	return nil
+		return nil
+	})
 }
 
 // DisableAutoUpdate - disables automatic update.
@@ -380,9 +579,9 @@ func (pm *PolicyManager) ensurePermissionsArray(role *Role, resourceID string) {
 	}
 }
 
-// getRole - helper function for getting a Role with given ID.
-func (pm *PolicyManager) getRole(roleID string) *Role {
-	role, ok := pm.policy.Roles[roleID]
+// getRole - helper function for getting a Role with given ID from given PolicyDefinition.
+func (pm *PolicyManager) getRole(policy *PolicyDefinition, roleID string) *Role {
+	role, ok := policy.Roles[roleID]
 
 	if !ok {
 		return nil
@@ -391,9 +590,9 @@ func (pm *PolicyManager) getRole(roleID string) *Role {
 	return role
 }
 
-// getPermissionPreset - helper function for getting PermissionPreset from PolicyDefinition.
-func (pm *PolicyManager) getPermissionPreset(name string) *Permission {
-	preset, ok := pm.policy.PermissionPresets[name]
+// getPermissionPreset - helper function for getting PermissionPreset from given PolicyDefinition.
+func (pm *PolicyManager) getPermissionPreset(policy *PolicyDefinition, name string) *Permission {
+	preset, ok := policy.PermissionPresets[name]
 
 	if !ok {
 		return nil
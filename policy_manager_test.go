@@ -1,8 +1,12 @@
 package restrict
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -84,7 +88,7 @@ func (s *policyManagerSuite) TestLoadPolicy() {
 	err = manager.LoadPolicy()
 
 	assert.Nil(s.T(), err)
-	assert.Equal(s.T(), testPolicy, manager.policy)
+	assert.Equal(s.T(), testPolicy, manager.getSnapshot().policy)
 	// We expect 2, since NewPolicyManager calls LoadPolicy as well.
 	testAdapter.AssertNumberOfCalls(s.T(), "LoadPolicy", 2)
 
@@ -96,6 +100,68 @@ func (s *policyManagerSuite) TestLoadPolicy() {
 	assert.Error(s.T(), err)
 }
 
+func (s *policyManagerSuite) TestLoadPolicy_PrepareConditions() {
+	testPolicy := getBasicPolicy()
+
+	testCondition := &ExpressionCondition{Expression: "subject.ID == resource.CreatedBy"}
+
+	testPolicy.Roles[basicRoleOneName].Grants[basicResourceOneName] = append(
+		testPolicy.Roles[basicRoleOneName].Grants[basicResourceOneName],
+		&Permission{Action: updateAction, Conditions: Conditions{testCondition}},
+	)
+
+	testAdapter := new(storageAdapterMock)
+	testAdapter.On("LoadPolicy").Return(testPolicy, nil)
+
+	manager, err := NewPolicyManager(testAdapter, false)
+
+	assert.Nil(s.T(), err)
+
+	// Loaded policy is copied - Conditions returned by StorageAdapter are left untouched.
+	loadedPermissions := manager.getSnapshot().policy.Roles[basicRoleOneName].Grants[basicResourceOneName]
+
+	assert.NotNil(s.T(), loadedPermissions[len(loadedPermissions)-1].Conditions[0].(*ExpressionCondition).parsed)
+	assert.Nil(s.T(), testCondition.parsed)
+
+	// Malformed Condition in Role's Permissions.
+	testCondition.Expression = "subject.ID =="
+
+	err = manager.LoadPolicy()
+
+	assert.IsType(s.T(), new(ConditionMalformedError), err)
+
+	testCondition.Expression = "subject.ID == resource.CreatedBy"
+
+	// Malformed Condition in added Permission.
+	err = manager.AddPermission(basicRoleOneName, basicResourceOneName, &Permission{
+		Action:     deleteAction,
+		Conditions: Conditions{&ExpressionCondition{Expression: "unknown.ID == 1"}},
+	})
+
+	assert.IsType(s.T(), new(ConditionMalformedError), err)
+
+	// Malformed Condition in added preset.
+	err = manager.AddPermissionPreset("testPreset", &Permission{
+		Conditions: Conditions{&NotCondition{Condition: &ExpressionCondition{Expression: "("}}},
+	})
+
+	assert.IsType(s.T(), new(ConditionMalformedError), err)
+	assert.Nil(s.T(), manager.GetPolicy().PermissionPresets["testPreset"])
+
+	// Invalid pattern in Role's Permissions.
+	testPolicy.Roles[basicRoleOneName].Grants[basicResourceOneName] = append(
+		testPolicy.Roles[basicRoleOneName].Grants[basicResourceOneName],
+		&Permission{Action: deleteAction, Conditions: Conditions{&MatchesCondition{
+			Left:  &ValueDescriptor{Source: SubjectField, Field: "ID"},
+			Right: &ValueDescriptor{Source: Explicit, Value: "(user"},
+		}}},
+	)
+
+	err = manager.LoadPolicy()
+
+	assert.IsType(s.T(), new(ConditionMalformedError), err)
+}
+
 func (s *policyManagerSuite) TestLoadPolicy_ApplyPresets() {
 	testPolicy := getBasicPolicy()
 
@@ -122,9 +188,11 @@ func (s *policyManagerSuite) TestLoadPolicy_ApplyPresets() {
 
 	err := manager.LoadPolicy()
 
+	permissions := manager.GetPolicy().Roles[basicRoleOneName].Grants[basicResourceOneName]
+
 	assert.Nil(s.T(), err)
-	assert.Equal(s.T(), "test-action-1", testPermissions[0].Action)
-	assert.Equal(s.T(), "test-action-2", testPermissions[1].Action)
+	assert.Equal(s.T(), "test-action-1", permissions[0].Action)
+	assert.Equal(s.T(), "test-action-2", permissions[1].Action)
 }
 
 func (s *policyManagerSuite) TestLoadPolicy_ApplyPresetFailure() {
@@ -144,6 +212,89 @@ func (s *policyManagerSuite) TestLoadPolicy_ApplyPresetFailure() {
 	assert.IsType(s.T(), new(PermissionPresetNotFoundError), err)
 }
 
+func (s *policyManagerSuite) TestLoadPolicy_UnknownEffect() {
+	testPolicy := getBasicPolicy()
+
+	testAdapter := new(storageAdapterMock)
+	testAdapter.On("LoadPolicy").Return(testPolicy, nil)
+
+	manager, _ := NewPolicyManager(testAdapter, false)
+	version := manager.GetPolicyVersion()
+
+	// Unknown Effect on Role's Permission.
+	testPolicy.Roles[basicRoleOneName].Grants[basicResourceOneName][0].Effect = Effect("DENY")
+
+	err := manager.LoadPolicy()
+
+	assert.IsType(s.T(), new(UnknownEffectError), err)
+	assert.Equal(s.T(), version, manager.GetPolicyVersion())
+
+	// Unknown Effect on Permission preset.
+	testPolicy.Roles[basicRoleOneName].Grants[basicResourceOneName][0].Effect = ""
+	testPolicy.PermissionPresets = PermissionPresets{
+		"test-preset": &Permission{Action: readAction, Effect: Effect("forbid")},
+	}
+
+	err = manager.LoadPolicy()
+
+	assert.IsType(s.T(), new(UnknownEffectError), err)
+	assert.Equal(s.T(), version, manager.GetPolicyVersion())
+}
+
+func (s *policyManagerSuite) TestLoadPolicy_ResourceDefinitions() {
+	testPolicy := getBasicPolicy()
+	testPolicy.Resources = ResourceDefinitions{
+		"Document": {Parent: "Folder"},
+		"Folder":   {Parent: "Project", ConditionsTarget: AncestorResource},
+		"Project":  nil,
+	}
+
+	testAdapter := new(storageAdapterMock)
+	testAdapter.On("LoadPolicy").Return(testPolicy, nil)
+
+	manager, err := NewPolicyManager(testAdapter, false)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), testPolicy.Resources["Folder"], manager.GetResourceDefinition("Folder"))
+	assert.Nil(s.T(), manager.GetResourceDefinition("Project"))
+	assert.Nil(s.T(), manager.GetResourceDefinition("Comment"))
+
+	// Unknown ConditionsTarget.
+	testPolicy.Resources["Project"] = &ResourceDefinition{ConditionsTarget: "parent"}
+
+	assert.IsType(s.T(), new(ResourceDefinitionMalformedError), manager.LoadPolicy())
+
+	// Cycle.
+	testPolicy.Resources["Project"] = &ResourceDefinition{Parent: "Document"}
+
+	err = manager.LoadPolicy()
+
+	assert.IsType(s.T(), new(ResourceHierarchyCycleError), err)
+	assert.Contains(s.T(), err.Error(), "\"Folder\" -> \"Project\"")
+}
+
+func (s *policyManagerSuite) TestLoadPolicy_ActionHierarchy() {
+	testPolicy := getBasicPolicy()
+	testPolicy.Actions = &ActionHierarchy{
+		Global: ActionImplications{
+			"manage": {createAction, readAction, updateAction, deleteAction},
+		},
+	}
+
+	testAdapter := new(storageAdapterMock)
+	testAdapter.On("LoadPolicy").Return(testPolicy, nil)
+
+	manager, err := NewPolicyManager(testAdapter, false)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{"manage"}, manager.GetImplyingActions(basicResourceOneName, deleteAction))
+	assert.Equal(s.T(), []string{createAction, readAction, updateAction, deleteAction}, manager.GetImpliedActions(basicResourceOneName, "manage"))
+
+	testPolicy.Actions.Global[deleteAction] = []string{"manage"}
+
+	assert.IsType(s.T(), new(ActionImplicationCycleError), manager.LoadPolicy())
+}
+
 func (s *policyManagerSuite) TestSavePolicy() {
 	testPolicy := getBasicPolicy()
 
@@ -167,6 +318,21 @@ func (s *policyManagerSuite) TestSavePolicy() {
 	err = manager.SavePolicy()
 
 	assert.Nil(s.T(), err)
+
+	// StorageAdapter gets a copy of the policy, including when saving changes.
+	manager.EnableAutoUpdate()
+	testAdapter.On("SavePolicy", mock.Anything).Return(nil)
+
+	assert.Nil(s.T(), manager.AddPermission(basicRoleOneName, basicResourceTwoName, &Permission{Action: readAction}))
+
+	for _, call := range testAdapter.Calls {
+		if call.Method == "SavePolicy" {
+			saved := call.Arguments.Get(0).(*PolicyDefinition)
+
+			assert.NotSame(s.T(), manager.getSnapshot().policy, saved)
+			assert.NotSame(s.T(), manager.getSnapshot().policy.Roles[basicRoleOneName], saved.Roles[basicRoleOneName])
+		}
+	}
 }
 
 func (s *policyManagerSuite) TestGetPolicy() {
@@ -180,6 +346,12 @@ func (s *policyManagerSuite) TestGetPolicy() {
 	policy := manager.GetPolicy()
 
 	assert.Equal(s.T(), testPolicy, policy)
+
+	// Returned policy is a copy.
+	policy.Roles[basicRoleOneName].Grants[basicResourceOneName][0].Action = "changed"
+	delete(policy.Roles, basicRoleOneName)
+
+	assert.Equal(s.T(), testPolicy, manager.GetPolicy())
 }
 
 func (s *policyManagerSuite) TestGetRole() {
@@ -201,6 +373,234 @@ func (s *policyManagerSuite) TestGetRole() {
 
 	assert.Equal(s.T(), testPolicy.Roles[basicRoleOneName], role)
 	assert.Nil(s.T(), err)
+
+	// Returned Role is a copy.
+	role.Parents = append(role.Parents, basicParentRoleName)
+	role.Grants[basicResourceOneName] = append(role.Grants[basicResourceOneName], &Permission{Action: deleteAction})
+	role.Grants[basicResourceOneName][0].Action = "changed"
+
+	role, _ = manager.GetRole(basicRoleOneName)
+
+	assert.Equal(s.T(), getBasicRoleOne(), role)
+
+	// Returned Role's Conditions are copied as well.
+	testPolicy.Roles[basicRoleOneName].Grants[basicResourceOneName][0].Conditions = Conditions{
+		&EqualCondition{
+			Left:  &ValueDescriptor{Source: Explicit, Value: "one"},
+			Right: &ValueDescriptor{Source: Explicit, Value: "one"},
+		},
+	}
+
+	assert.Nil(s.T(), manager.LoadPolicy())
+
+	testRequest := &AccessRequest{
+		Subject:  UseSubject([]string{basicRoleOneName}),
+		Resource: UseResource(basicResourceOneName),
+		Actions:  []string{testPolicy.Roles[basicRoleOneName].Grants[basicResourceOneName][0].Action},
+	}
+
+	accessManager := NewAccessManager(manager)
+
+	assert.Nil(s.T(), accessManager.Authorize(testRequest))
+
+	role, _ = manager.GetRole(basicRoleOneName)
+	role.Grants[basicResourceOneName][0].Conditions[0].(*EqualCondition).Right.Value = "changed"
+
+	assert.Nil(s.T(), accessManager.Authorize(testRequest))
+
+	// Roles fetched by AccessManager are not copied.
+	snapshotRole, err := manager.getSnapshotRole(basicRoleOneName)
+
+	assert.Nil(s.T(), err)
+	assert.Same(s.T(), manager.getSnapshot().policy.Roles[basicRoleOneName], snapshotRole)
+	assert.NotSame(s.T(), snapshotRole, role)
+
+	_, err = manager.getSnapshotRole("INCORRECT_ROLE")
+
+	assert.IsType(s.T(), new(RoleNotFoundError), err)
+}
+
+func (s *policyManagerSuite) TestGetRolesGranting() {
+	ownerCondition := &EqualCondition{
+		Left:  &ValueDescriptor{Source: ResourceField, Field: "OwnerID"},
+		Right: &ValueDescriptor{Source: SubjectField, Field: "ID"},
+	}
+
+	testPolicy := &PolicyDefinition{
+		Roles: Roles{
+			"Admin": {
+				ID:      "Admin",
+				Parents: []string{"Editor"},
+				Grants: GrantsMap{
+					"Project": {&Permission{Action: "manage"}},
+				},
+			},
+			"Editor": {
+				ID:      "Editor",
+				Parents: []string{"Viewer", "Admin"},
+				Grants: GrantsMap{
+					"Document": {
+						&Permission{Action: deleteAction, Conditions: Conditions{ownerCondition}},
+						&Permission{Action: readAction},
+					},
+				},
+			},
+			"Viewer": {
+				ID: "Viewer",
+				Grants: GrantsMap{
+					"Document": {&Permission{Action: readAction}},
+				},
+			},
+			"Suspended": {
+				ID: "Suspended",
+				Grants: GrantsMap{
+					"*": {&Permission{Action: "*", Effect: DenyEffect}},
+				},
+			},
+		},
+		Actions: &ActionHierarchy{
+			Global: ActionImplications{
+				"manage": {deleteAction},
+			},
+		},
+		Resources: ResourceDefinitions{
+			"Document": {Parent: "Project"},
+		},
+	}
+
+	testAdapter := new(storageAdapterMock)
+	testAdapter.On("LoadPolicy").Return(testPolicy, nil)
+
+	manager, _ := NewPolicyManager(testAdapter, false)
+
+	rolesAccess, err := manager.GetRolesGranting(deleteAction, "Document")
+
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), rolesAccess, 2)
+
+	assert.Equal(s.T(), "Admin", rolesAccess[0].RoleName)
+	assert.Equal(s.T(), []*AppliedPermission{
+		{
+			Permission:      testPolicy.Roles["Editor"].Grants["Document"][0],
+			RoleName:        "Editor",
+			InheritancePath: []string{"Admin", "Editor"},
+			ResourceName:    "Document",
+		},
+		{
+			Permission:      testPolicy.Roles["Admin"].Grants["Project"][0],
+			RoleName:        "Admin",
+			InheritancePath: []string{"Admin"},
+			ResourceName:    "Project",
+		},
+	}, rolesAccess[0].Permissions)
+
+	assert.Equal(s.T(), "Editor", rolesAccess[1].RoleName)
+	assert.Equal(s.T(), []*AppliedPermission{
+		{
+			Permission:      testPolicy.Roles["Editor"].Grants["Document"][0],
+			RoleName:        "Editor",
+			InheritancePath: []string{"Editor"},
+			ResourceName:    "Document",
+		},
+		{
+			Permission:      testPolicy.Roles["Admin"].Grants["Project"][0],
+			RoleName:        "Admin",
+			InheritancePath: []string{"Editor", "Admin"},
+			ResourceName:    "Project",
+		},
+	}, rolesAccess[1].Permissions)
+
+	// Returned Permissions are copies.
+	rolesAccess[0].Permissions[0].Permission.Conditions[0].(*EqualCondition).Right.Field = "changed"
+
+	assert.Equal(s.T(), "ID", manager.getSnapshot().policy.Roles["Editor"].Grants["Document"][0].Conditions[0].(*EqualCondition).Right.Field)
+
+	rolesAccess, err = manager.GetRolesGranting(readAction, "Document")
+
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), rolesAccess, 3)
+	assert.Equal(s.T(), "Viewer", rolesAccess[2].RoleName)
+	assert.Equal(s.T(), []string{"Admin", "Editor", "Viewer"}, rolesAccess[0].Permissions[1].InheritancePath)
+
+	rolesAccess, err = manager.GetRolesGranting(createAction, "Document")
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []*RoleAccess{}, rolesAccess)
+
+	// Missing parent Role.
+	testPolicy.Roles["Viewer"].Parents = []string{"Guest"}
+
+	assert.Nil(s.T(), manager.LoadPolicy())
+
+	_, err = manager.GetRolesGranting(readAction, "Document")
+
+	assert.IsType(s.T(), new(RoleNotFoundError), err)
+}
+
+func (s *policyManagerSuite) TestGetRolesGranting_DiamondHierarchy() {
+	testAdapter := new(storageAdapterMock)
+	testAdapter.On("LoadPolicy").Return(getDiamondHierarchyPolicy(30), nil)
+
+	manager, _ := NewPolicyManager(testAdapter, false)
+
+	// Every Role is visited once - otherwise the number of inheritance paths (2^30) would never be traversed.
+	rolesAccess, err := manager.GetRolesGranting(readAction, basicResourceOneName)
+
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), rolesAccess, 61)
+
+	assert.Equal(s.T(), "Role0", rolesAccess[0].RoleName)
+	assert.Len(s.T(), rolesAccess[0].Permissions, 2)
+	assert.Equal(s.T(), "Role30A", rolesAccess[0].Permissions[0].RoleName)
+	assert.Len(s.T(), rolesAccess[0].Permissions[0].InheritancePath, 31)
+	assert.Equal(s.T(), "Role30B", rolesAccess[0].Permissions[1].RoleName)
+}
+
+func (s *policyManagerSuite) TestGetRoleContext() {
+	testPolicy := getBasicPolicy()
+
+	testAdapter := new(storageAdapterMock)
+	testAdapter.On("LoadPolicy").Return(testPolicy, nil)
+
+	manager, _ := NewPolicyManager(testAdapter, false)
+
+	role, err := manager.GetRoleContext(context.Background(), basicRoleOneName)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), testPolicy.Roles[basicRoleOneName], role)
+
+	_, err = manager.GetRoleContext(context.Background(), "INCORRECT_ROLE")
+
+	assert.IsType(s.T(), new(RoleNotFoundError), err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	role, err = manager.GetRoleContext(ctx, basicRoleOneName)
+
+	assert.Nil(s.T(), role)
+	assert.Equal(s.T(), context.Canceled, err)
+}
+
+func (s *policyManagerSuite) TestGetPolicyVersion() {
+	testAdapter := new(storageAdapterMock)
+	testAdapter.On("LoadPolicy").Return(getBasicPolicy(), nil)
+
+	policyManager, _ := NewPolicyManager(testAdapter, false)
+
+	version := policyManager.GetPolicyVersion()
+
+	assert.Nil(s.T(), policyManager.AddRole(&Role{ID: "NEW_ROLE"}))
+	assert.Greater(s.T(), policyManager.GetPolicyVersion(), version)
+
+	version = policyManager.GetPolicyVersion()
+
+	// Failed changes do not change the version.
+	assert.IsType(s.T(), new(RoleNotFoundError), policyManager.DeleteRole("NOT_EXISTING"))
+	assert.Equal(s.T(), version, policyManager.GetPolicyVersion())
+
+	assert.Nil(s.T(), policyManager.UpsertPermissionPreset("preset", &Permission{Action: readAction}))
+	assert.Greater(s.T(), policyManager.GetPolicyVersion(), version)
 }
 
 func (s *policyManagerSuite) TestAddRole() {
@@ -238,6 +638,28 @@ func (s *policyManagerSuite) TestAddRole() {
 
 	// It should still be one
 	testAdapter.AssertNumberOfCalls(s.T(), "SavePolicy", 1)
+
+	// Failed change is not applied.
+	testInvalidRole := &Role{
+		ID: "INVALID_ROLE",
+		Grants: GrantsMap{
+			basicResourceOneName: {&Permission{Preset: "NOT_EXISTING"}},
+		},
+	}
+
+	err = manager.AddRole(testInvalidRole)
+
+	assert.IsType(s.T(), new(PermissionPresetNotFoundError), err)
+	assert.Nil(s.T(), manager.GetPolicy().Roles["INVALID_ROLE"])
+	testAdapter.AssertNumberOfCalls(s.T(), "SavePolicy", 1)
+
+	// Unknown Effect
+	testInvalidRole.Grants[basicResourceOneName] = Permissions{&Permission{Action: readAction, Effect: Effect("DENY")}}
+
+	err = manager.AddRole(testInvalidRole)
+
+	assert.IsType(s.T(), new(UnknownEffectError), err)
+	assert.Nil(s.T(), manager.GetPolicy().Roles["INVALID_ROLE"])
 }
 
 func (s *policyManagerSuite) TestUpdateRole() {
@@ -275,6 +697,20 @@ func (s *policyManagerSuite) TestUpdateRole() {
 
 	// It should still be one
 	testAdapter.AssertNumberOfCalls(s.T(), "SavePolicy", 1)
+
+	// Unknown Effect
+	testInvalidRole := &Role{
+		ID: basicRoleOneName,
+		Grants: GrantsMap{
+			basicResourceOneName: {&Permission{Action: readAction, Effect: Effect("DENY")}},
+		},
+	}
+
+	err = manager.UpdateRole(testInvalidRole)
+
+	assert.IsType(s.T(), new(UnknownEffectError), err)
+	assert.Nil(s.T(), manager.GetPolicy().Roles[basicRoleOneName].Grants[basicResourceOneName])
+	testAdapter.AssertNumberOfCalls(s.T(), "SavePolicy", 1)
 }
 
 func (s *policyManagerSuite) TestUpsertRole() {
@@ -372,6 +808,18 @@ func (s *policyManagerSuite) TestAddPermission() {
 	err = manager.AddPermission(basicRoleOneName, basicResourceTwoName, testPermission)
 
 	assert.IsType(s.T(), new(PermissionPresetNotFoundError), err)
+
+	// Unknown Effect
+	testPermission = &Permission{
+		Action: deleteAction,
+		Effect: Effect("DENY"),
+	}
+
+	err = manager.AddPermission(basicRoleOneName, basicResourceTwoName, testPermission)
+
+	assert.IsType(s.T(), new(UnknownEffectError), err)
+	assert.Len(s.T(), manager.GetPolicy().Roles[basicRoleOneName].Grants[basicResourceTwoName], 2)
+	testAdapter.AssertNumberOfCalls(s.T(), "SavePolicy", 1)
 }
 
 func (s *policyManagerSuite) TestDeletePermission() {
@@ -431,6 +879,8 @@ func (s *policyManagerSuite) TestAddPermissionPreset() {
 		testPresetName: testPreset,
 	}
 
+	assert.Nil(s.T(), manager.LoadPolicy())
+
 	err := manager.AddPermissionPreset(testPresetName, testPreset)
 
 	assert.IsType(s.T(), new(PermissionPresetAlreadyExistsError), err)
@@ -438,6 +888,8 @@ func (s *policyManagerSuite) TestAddPermissionPreset() {
 	// Presets set to nil, Preset does not exist, without auto update
 	testPolicy.PermissionPresets = nil
 
+	assert.Nil(s.T(), manager.LoadPolicy())
+
 	err = manager.AddPermissionPreset(testPresetName, testPreset)
 
 	policy := manager.GetPolicy()
@@ -473,6 +925,8 @@ func (s *policyManagerSuite) TestUpdatePermissionPreset() {
 		testPresetName: testPreset,
 	}
 
+	assert.Nil(s.T(), manager.LoadPolicy())
+
 	testIncorrectPreset := &Permission{
 		Action: "test-action-2",
 	}
@@ -484,7 +938,7 @@ func (s *policyManagerSuite) TestUpdatePermissionPreset() {
 	// Preset exists
 	err = manager.UpdatePermissionPreset(testPresetName, testPreset)
 
-	preset := manager.getPermissionPreset(testPresetName)
+	preset := manager.getPermissionPreset(manager.getSnapshot().policy, testPresetName)
 
 	assert.Nil(s.T(), err)
 	assert.Equal(s.T(), testPreset.Action, preset.Action)
@@ -513,13 +967,13 @@ func (s *policyManagerSuite) TestUpsertPermissionPreset() {
 
 	// Preset does not exist
 	err := manager.UpsertPermissionPreset(testPresetName, testPreset)
-	preset := manager.getPermissionPreset(testPresetName)
+	preset := manager.getPermissionPreset(manager.getSnapshot().policy, testPresetName)
 
 	assert.Nil(s.T(), err)
 	assert.Equal(s.T(), testPreset, preset)
 
 	err = manager.UpsertPermissionPreset(testPresetName, testPreset)
-	preset = manager.getPermissionPreset(testPresetName)
+	preset = manager.getPermissionPreset(manager.getSnapshot().policy, testPresetName)
 
 	assert.Nil(s.T(), err)
 	assert.Equal(s.T(), testPreset, preset)
@@ -601,3 +1055,129 @@ func (s *policyManagerSuite) TestEnableAutoUpdate() {
 	assert.True(s.T(), manager.autoUpdate)
 
 }
+
+func (s *policyManagerSuite) TestConcurrentAuthorizeAndChanges() {
+	testPolicy := getBasicPolicy()
+	testPolicy.Roles[basicRoleOneName].Parents = []string{basicParentRoleName}
+	testPolicy.Roles[basicParentRoleName] = &Role{
+		ID: basicParentRoleName,
+		Grants: GrantsMap{
+			basicResourceOneName: {
+				&Permission{
+					Action: updateAction,
+					Conditions: Conditions{
+						&MatchesCondition{
+							Left:  &ValueDescriptor{Source: ResourceField, Field: "CreatedBy"},
+							Right: &ValueDescriptor{Source: Explicit, Value: "^user-"},
+						},
+						&IPInCIDRCondition{
+							Left:  &ValueDescriptor{Source: ContextField, Field: "IP"},
+							Right: &ValueDescriptor{Source: Explicit, Value: "10.0.0.0/8"},
+						},
+						// Patterns and networks taken from the request are not precompiled.
+						&MatchesCondition{
+							Left:  &ValueDescriptor{Source: ResourceField, Field: "CreatedBy"},
+							Right: &ValueDescriptor{Source: ContextField, Field: "Pattern"},
+						},
+						&GlobCondition{
+							Left:  &ValueDescriptor{Source: ResourceField, Field: "CreatedBy"},
+							Right: &ValueDescriptor{Source: ContextField, Field: "Glob"},
+						},
+						&IPInCIDRCondition{
+							Left:  &ValueDescriptor{Source: ContextField, Field: "IP"},
+							Right: &ValueDescriptor{Source: ContextField, Field: "Networks"},
+						},
+						&IPNotInCIDRCondition{
+							Left:  &ValueDescriptor{Source: ContextField, Field: "IP"},
+							Right: &ValueDescriptor{Source: ContextField, Field: "BlockedNetworks"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testAdapter := new(storageAdapterMock)
+	testAdapter.On("LoadPolicy").Return(testPolicy, nil)
+	testAdapter.On("SavePolicy", mock.Anything).Return(nil)
+
+	manager, err := NewPolicyManager(testAdapter, true)
+
+	assert.Nil(s.T(), err)
+
+	indexedManager := NewAccessManager(manager)
+	notIndexedManager := NewAccessManager(newNotIndexedPolicyProvider(manager))
+	notIndexedManager.SetDecisionCache(NewDecisionCache(10, time.Minute))
+
+	testSubject := UseSubject([]string{basicRoleOneName})
+	testResource := &resourceMock{CreatedBy: "user-1"}
+	testResource.On("GetResourceName").Return(basicResourceOneName)
+
+	done := make(chan struct{})
+	errs := make(chan error, 100)
+
+	var readers sync.WaitGroup
+
+	for _, accessManager := range []*AccessManager{indexedManager, notIndexedManager} {
+		for i := 0; i < 4; i++ {
+			readers.Add(1)
+
+			go func(accessManager *AccessManager) {
+				defer readers.Done()
+
+				for {
+					select {
+					case <-done:
+						return
+					default:
+					}
+
+					// Permissions for these Actions are never changed.
+					err := accessManager.Authorize(&AccessRequest{
+						Subject:  testSubject,
+						Resource: testResource,
+						Actions:  []string{readAction, updateAction},
+						Context: Context{
+							"IP":              "10.1.2.3",
+							"Pattern":         "^user-",
+							"Glob":            "user-*",
+							"Networks":        "10.0.0.0/8",
+							"BlockedNetworks": []string{"192.168.0.0/16"},
+						},
+					})
+					if err != nil {
+						errs <- err
+						return
+					}
+
+					_, _ = accessManager.AllowedActions(testSubject, testResource, nil)
+					_ = manager.GetPolicy()
+				}
+			}(accessManager)
+		}
+	}
+
+	for i := 0; i < 50; i++ {
+		assert.Nil(s.T(), manager.AddPermission(basicRoleOneName, basicResourceOneName, &Permission{Action: deleteAction}))
+		assert.Nil(s.T(), manager.DeletePermission(basicRoleOneName, basicResourceOneName, deleteAction))
+
+		// Role taken from the policy shares its Conditions with the published one.
+		role, err := manager.GetRole(basicParentRoleName)
+
+		assert.Nil(s.T(), err)
+
+		role.Description = fmt.Sprintf("Version %d", i)
+
+		assert.Nil(s.T(), manager.UpdateRole(role))
+		assert.Nil(s.T(), manager.UpsertPermissionPreset("preset", &Permission{Action: readAction}))
+		assert.Nil(s.T(), manager.LoadPolicy())
+	}
+
+	close(done)
+	readers.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.Nil(s.T(), err)
+	}
+}
@@ -0,0 +1,285 @@
+package restrict
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ConstantPredicateType - ConstantPredicate's type identifier.
+	ConstantPredicateType = "CONSTANT"
+	// AndPredicateType - AndPredicate's type identifier.
+	AndPredicateType = "AND"
+	// OrPredicateType - OrPredicate's type identifier.
+	OrPredicateType = "OR"
+	// NotPredicateType - NotPredicate's type identifier.
+	NotPredicateType = "NOT"
+	// FieldPredicateType - FieldPredicate's type identifier.
+	FieldPredicateType = "FIELD"
+)
+
+// PredicateOperator - operator used by FieldPredicate to compare Resource's field with a value.
+type PredicateOperator string
+
+const (
+	// EqualOperator - field is equal to the value.
+	EqualOperator PredicateOperator = "=="
+	// NotEqualOperator - field is not equal to the value.
+	NotEqualOperator PredicateOperator = "!="
+	// GreaterThanOperator - field is greater than the value.
+	GreaterThanOperator PredicateOperator = ">"
+	// GreaterThanOrEqualOperator - field is greater than or equal to the value.
+	GreaterThanOrEqualOperator PredicateOperator = ">="
+	// LessThanOperator - field is less than the value.
+	LessThanOperator PredicateOperator = "<"
+	// LessThanOrEqualOperator - field is less than or equal to the value.
+	LessThanOrEqualOperator PredicateOperator = "<="
+	// InOperator - field is an element of the value (a collection).
+	InOperator PredicateOperator = "IN"
+	// NotInOperator - field is not an element of the value (a collection).
+	NotInOperator PredicateOperator = "NOT IN"
+)
+
+// flipped - returns the operator that gives the same result when operands are swapped.
+func (o PredicateOperator) flipped() PredicateOperator {
+	switch o {
+	case GreaterThanOperator:
+		return LessThanOperator
+	case GreaterThanOrEqualOperator:
+		return LessThanOrEqualOperator
+	case LessThanOperator:
+		return GreaterThanOperator
+	case LessThanOrEqualOperator:
+		return GreaterThanOrEqualOperator
+	}
+
+	return o
+}
+
+// Predicate - a node of residual predicate's AST, describing which Resources satisfy the policy.
+// Predicates are returned by AccessManager's PartialEvaluate, and can be rendered into other
+// query languages (e.g. SQL) by a PredicateRenderer.
+type Predicate interface {
+	// Type - returns Predicate's type.
+	Type() string
+	// String - returns human-readable representation of the Predicate.
+	String() string
+}
+
+// PredicateRenderer - interface that has to be implemented by entities rendering Predicates
+// into other query languages. Returns rendered query and its arguments.
+type PredicateRenderer interface {
+	Render(predicate Predicate) (string, []interface{}, error)
+}
+
+// ConstantPredicate - Predicate that is always true or always false, regardless of the Resource.
+type ConstantPredicate struct {
+	// Value - Predicate's value.
+	Value bool
+}
+
+// Type - returns Predicate's type.
+func (p *ConstantPredicate) Type() string {
+	return ConstantPredicateType
+}
+
+// String - returns human-readable representation of the Predicate.
+func (p *ConstantPredicate) String() string {
+	return strconv.FormatBool(p.Value)
+}
+
+// AndPredicate - Predicate satisfied when all of nested Predicates are satisfied.
+type AndPredicate struct {
+	// Predicates - nested Predicates.
+	Predicates []Predicate
+}
+
+// Type - returns Predicate's type.
+func (p *AndPredicate) Type() string {
+	return AndPredicateType
+}
+
+// String - returns human-readable representation of the Predicate.
+func (p *AndPredicate) String() string {
+	return joinPredicates(p.Predicates, " AND ")
+}
+
+// OrPredicate - Predicate satisfied when any of nested Predicates is satisfied.
+type OrPredicate struct {
+	// Predicates - nested Predicates.
+	Predicates []Predicate
+}
+
+// Type - returns Predicate's type.
+func (p *OrPredicate) Type() string {
+	return OrPredicateType
+}
+
+// String - returns human-readable representation of the Predicate.
+func (p *OrPredicate) String() string {
+	return joinPredicates(p.Predicates, " OR ")
+}
+
+// NotPredicate - Predicate satisfied when nested Predicate is not satisfied.
+type NotPredicate struct {
+	// Predicate - nested Predicate.
+	Predicate Predicate
+}
+
+// Type - returns Predicate's type.
+func (p *NotPredicate) Type() string {
+	return NotPredicateType
+}
+
+// String - returns human-readable representation of the Predicate.
+func (p *NotPredicate) String() string {
+	return "NOT " + wrapPredicate(p.Predicate)
+}
+
+// FieldPredicate - Predicate comparing Resource's field with a value, resolved during partial evaluation.
+type FieldPredicate struct {
+	// Field - Resource's field, as defined in ValueDescriptor (can be a path to a nested value).
+	Field string
+	// Operator - operator used for comparison.
+	Operator PredicateOperator
+	// Value - value the field is compared with.
+	Value interface{}
+}
+
+// Type - returns Predicate's type.
+func (p *FieldPredicate) Type() string {
+	return FieldPredicateType
+}
+
+// String - returns human-readable representation of the Predicate.
+func (p *FieldPredicate) String() string {
+	field := "Resource." + p.Field
+
+	value := fmt.Sprintf("%v", p.Value)
+
+	if stringValue, ok := p.Value.(string); ok {
+		value = strconv.Quote(stringValue)
+	}
+
+	return fmt.Sprintf("%s %s %s", field, p.Operator, value)
+}
+
+// joinPredicates - joins string representations of given Predicates with given separator.
+func joinPredicates(predicates []Predicate, separator string) string {
+	parts := []string{}
+
+	for _, predicate := range predicates {
+		parts = append(parts, wrapPredicate(predicate))
+	}
+
+	return strings.Join(parts, separator)
+}
+
+// wrapPredicate - returns string representation of given Predicate, wrapped in parentheses
+// if it's composite.
+func wrapPredicate(predicate Predicate) string {
+	if predicate == nil {
+		return ""
+	}
+
+	switch predicate.(type) {
+	case *AndPredicate, *OrPredicate:
+		return "(" + predicate.String() + ")"
+	}
+
+	return predicate.String()
+}
+
+// newConstantPredicate - returns new ConstantPredicate instance.
+func newConstantPredicate(value bool) *ConstantPredicate {
+	return &ConstantPredicate{
+		Value: value,
+	}
+}
+
+// isConstant - returns true if given Predicate is a ConstantPredicate with given value.
+func isConstant(predicate Predicate, value bool) bool {
+	constant, ok := predicate.(*ConstantPredicate)
+
+	return ok && constant.Value == value
+}
+
+// newAndPredicate - returns a Predicate satisfied when all given Predicates are satisfied,
+// simplifying constant ones and flattening nested AndPredicates.
+func newAndPredicate(predicates ...Predicate) Predicate {
+	result := []Predicate{}
+
+	for _, predicate := range predicates {
+		if isConstant(predicate, false) {
+			return newConstantPredicate(false)
+		}
+
+		if isConstant(predicate, true) {
+			continue
+		}
+
+		if and, ok := predicate.(*AndPredicate); ok {
+			result = append(result, and.Predicates...)
+			continue
+		}
+
+		result = append(result, predicate)
+	}
+
+	if len(result) == 0 {
+		return newConstantPredicate(true)
+	}
+
+	if len(result) == 1 {
+		return result[0]
+	}
+
+	return &AndPredicate{Predicates: result}
+}
+
+// newOrPredicate - returns a Predicate satisfied when any of given Predicates is satisfied,
+// simplifying constant ones and flattening nested OrPredicates.
+func newOrPredicate(predicates ...Predicate) Predicate {
+	result := []Predicate{}
+
+	for _, predicate := range predicates {
+		if isConstant(predicate, true) {
+			return newConstantPredicate(true)
+		}
+
+		if isConstant(predicate, false) {
+			continue
+		}
+
+		if or, ok := predicate.(*OrPredicate); ok {
+			result = append(result, or.Predicates...)
+			continue
+		}
+
+		result = append(result, predicate)
+	}
+
+	if len(result) == 0 {
+		return newConstantPredicate(false)
+	}
+
+	if len(result) == 1 {
+		return result[0]
+	}
+
+	return &OrPredicate{Predicates: result}
+}
+
+// newNotPredicate - returns a Predicate negating given one, simplifying constants and double negations.
+func newNotPredicate(predicate Predicate) Predicate {
+	if constant, ok := predicate.(*ConstantPredicate); ok {
+		return newConstantPredicate(!constant.Value)
+	}
+
+	if not, ok := predicate.(*NotPredicate); ok {
+		return not.Predicate
+	}
+
+	return &NotPredicate{Predicate: predicate}
+}
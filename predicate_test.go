@@ -0,0 +1,70 @@
+package restrict
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type predicateSuite struct {
+	suite.Suite
+}
+
+func TestPredicateSuite(t *testing.T) {
+	suite.Run(t, new(predicateSuite))
+}
+
+func (s *predicateSuite) TestString() {
+	testPredicate := newOrPredicate(
+		&FieldPredicate{Field: "OwnerID", Operator: EqualOperator, Value: "u1"},
+		&FieldPredicate{Field: "Public", Operator: EqualOperator, Value: true},
+		newAndPredicate(
+			&FieldPredicate{Field: "Level", Operator: GreaterThanOperator, Value: 2},
+			newNotPredicate(&FieldPredicate{Field: "Team", Operator: InOperator, Value: []interface{}{"a", "b"}}),
+		),
+	)
+
+	assert.Equal(
+		s.T(),
+		`Resource.OwnerID == "u1" OR Resource.Public == true OR (Resource.Level > 2 AND NOT Resource.Team IN [a b])`,
+		testPredicate.String(),
+	)
+
+	assert.Equal(s.T(), "true", newConstantPredicate(true).String())
+	assert.Equal(s.T(), `Resource.Team IN [a b]`, (&FieldPredicate{Field: "Team", Operator: InOperator, Value: []interface{}{"a", "b"}}).String())
+}
+
+func (s *predicateSuite) TestSimplification() {
+	testField := &FieldPredicate{Field: "OwnerID", Operator: EqualOperator, Value: "u1"}
+	otherField := &FieldPredicate{Field: "Public", Operator: EqualOperator, Value: true}
+
+	assert.Equal(s.T(), newConstantPredicate(true), newAndPredicate())
+	assert.Equal(s.T(), newConstantPredicate(false), newOrPredicate())
+	assert.Equal(s.T(), newConstantPredicate(false), newAndPredicate(testField, newConstantPredicate(false)))
+	assert.Equal(s.T(), newConstantPredicate(true), newOrPredicate(testField, newConstantPredicate(true)))
+	assert.Equal(s.T(), testField, newAndPredicate(testField, newConstantPredicate(true)))
+	assert.Equal(s.T(), testField, newOrPredicate(newConstantPredicate(false), testField))
+	assert.Equal(s.T(), testField, newNotPredicate(newNotPredicate(testField)))
+	assert.Equal(s.T(), newConstantPredicate(false), newNotPredicate(newConstantPredicate(true)))
+
+	assert.Equal(
+		s.T(),
+		&AndPredicate{Predicates: []Predicate{testField, otherField, testField}},
+		newAndPredicate(newAndPredicate(testField, otherField), testField),
+	)
+
+	assert.Equal(
+		s.T(),
+		&OrPredicate{Predicates: []Predicate{testField, otherField, testField}},
+		newOrPredicate(testField, newOrPredicate(otherField, testField)),
+	)
+}
+
+func (s *predicateSuite) TestFlipped() {
+	assert.Equal(s.T(), LessThanOperator, GreaterThanOperator.flipped())
+	assert.Equal(s.T(), LessThanOrEqualOperator, GreaterThanOrEqualOperator.flipped())
+	assert.Equal(s.T(), GreaterThanOperator, LessThanOperator.flipped())
+	assert.Equal(s.T(), GreaterThanOrEqualOperator, LessThanOrEqualOperator.flipped())
+	assert.Equal(s.T(), EqualOperator, EqualOperator.flipped())
+}
@@ -0,0 +1,44 @@
+package renderers
+
+import "fmt"
+
+// ColumnNotFoundError - thrown when Resource's field cannot be mapped to a column.
+type ColumnNotFoundError struct {
+	field string
+}
+
+// newColumnNotFoundError - returns new ColumnNotFoundError instance.
+func newColumnNotFoundError(field string) *ColumnNotFoundError {
+	return &ColumnNotFoundError{
+		field: field,
+	}
+}
+
+// Error - error interface implementation.
+func (e *ColumnNotFoundError) Error() string {
+	return fmt.Sprintf("column for field: \"%s\" could not be found", e.field)
+}
+
+// PredicateNotSupportedError - thrown when Predicate cannot be rendered.
+type PredicateNotSupportedError struct {
+	predicate string
+	reason    error
+}
+
+// newPredicateNotSupportedError - returns new PredicateNotSupportedError instance.
+func newPredicateNotSupportedError(predicate string, reason error) *PredicateNotSupportedError {
+	return &PredicateNotSupportedError{
+		predicate: predicate,
+		reason:    reason,
+	}
+}
+
+// Error - error interface implementation.
+func (e *PredicateNotSupportedError) Error() string {
+	return fmt.Sprintf("predicate: \"%s\" is not supported. Reason: %s", e.predicate, e.reason.Error())
+}
+
+// Reason - returns underlying reason (an error) of unsupported Predicate.
+func (e *PredicateNotSupportedError) Reason() error {
+	return e.reason
+}
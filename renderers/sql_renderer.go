@@ -0,0 +1,202 @@
+// Package renderers provides implementations of restrict.PredicateRenderer.
+package renderers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/el-mike/restrict/v2"
+)
+
+// PlaceholderFormat - alias type for describing how query's arguments are referenced.
+type PlaceholderFormat string
+
+const (
+	// QuestionPlaceholder - arguments are referenced with "?" (e.g. MySQL, SQLite).
+	QuestionPlaceholder PlaceholderFormat = "?"
+	// DollarPlaceholder - arguments are referenced with numbered "$n" (e.g. PostgreSQL).
+	DollarPlaceholder PlaceholderFormat = "$"
+)
+
+// identifierRegexp - describes field names that can be safely used as column names.
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// sqlOperators - SQL equivalents of PredicateOperators comparing a field with a value.
+var sqlOperators = map[restrict.PredicateOperator]string{
+	restrict.EqualOperator:              "=",
+	restrict.NotEqualOperator:           "<>",
+	restrict.GreaterThanOperator:        ">",
+	restrict.GreaterThanOrEqualOperator: ">=",
+	restrict.LessThanOperator:           "<",
+	restrict.LessThanOrEqualOperator:    "<=",
+}
+
+// SQLRenderer - PredicateRenderer implementation, rendering Predicates into SQL WHERE clauses.
+// Values are never inlined - they are returned as query's arguments instead.
+type SQLRenderer struct {
+	placeholder PlaceholderFormat
+	columns     map[string]string
+}
+
+// NewSQLRenderer - returns new SQLRenderer instance. Columns map Resource's fields (as used
+// in ValueDescriptors) to column names - when nil, fields are used as column names, as long as
+// they are valid identifiers.
+func NewSQLRenderer(placeholder PlaceholderFormat, columns map[string]string) *SQLRenderer {
+	return &SQLRenderer{
+		placeholder: placeholder,
+		columns:     columns,
+	}
+}
+
+// Render - renders given Predicate into SQL WHERE clause (without the WHERE keyword),
+// and returns it along with query's arguments.
+func (r *SQLRenderer) Render(predicate restrict.Predicate) (string, []interface{}, error) {
+	query := &sqlQuery{
+		renderer: r,
+		args:     []interface{}{},
+	}
+
+	clause, err := query.render(predicate)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return clause, query.args, nil
+}
+
+// column - returns the column name for given Resource's field.
+func (r *SQLRenderer) column(field string) (string, error) {
+	if r.columns != nil {
+		column, ok := r.columns[field]
+		if !ok {
+			return "", newColumnNotFoundError(field)
+		}
+
+		return column, nil
+	}
+
+	if !identifierRegexp.MatchString(field) {
+		return "", newColumnNotFoundError(field)
+	}
+
+	return field, nil
+}
+
+// sqlQuery - state of a single Predicate's rendering.
+type sqlQuery struct {
+	renderer *SQLRenderer
+	args     []interface{}
+}
+
+// render - renders given Predicate.
+func (q *sqlQuery) render(predicate restrict.Predicate) (string, error) {
+	switch p := predicate.(type) {
+	case *restrict.ConstantPredicate:
+		if p.Value {
+			return "1 = 1", nil
+		}
+
+		return "1 = 0", nil
+	case *restrict.AndPredicate:
+		return q.renderComposite(p.Predicates, " AND ")
+	case *restrict.OrPredicate:
+		return q.renderComposite(p.Predicates, " OR ")
+	case *restrict.NotPredicate:
+		nested, err := q.render(p.Predicate)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("NOT (%s)", nested), nil
+	case *restrict.FieldPredicate:
+		return q.renderField(p)
+	}
+
+	if predicate == nil {
+		return "", newPredicateNotSupportedError("nil", fmt.Errorf("Predicate cannot be nil"))
+	}
+
+	return "", newPredicateNotSupportedError(predicate.Type(), fmt.Errorf("unknown Predicate type"))
+}
+
+// renderComposite - renders given Predicates, joined with given operator.
+func (q *sqlQuery) renderComposite(predicates []restrict.Predicate, operator string) (string, error) {
+	parts := []string{}
+
+	for _, predicate := range predicates {
+		part, err := q.render(predicate)
+		if err != nil {
+			return "", err
+		}
+
+		parts = append(parts, part)
+	}
+
+	return "(" + strings.Join(parts, operator) + ")", nil
+}
+
+// renderField - renders FieldPredicate.
+func (q *sqlQuery) renderField(predicate *restrict.FieldPredicate) (string, error) {
+	column, err := q.renderer.column(predicate.Field)
+	if err != nil {
+		return "", err
+	}
+
+	switch predicate.Operator {
+	case restrict.InOperator, restrict.NotInOperator:
+		return q.renderMembership(column, predicate)
+	}
+
+	operator, ok := sqlOperators[predicate.Operator]
+	if !ok {
+		return "", newPredicateNotSupportedError(predicate.String(), fmt.Errorf("unknown operator: \"%s\"", predicate.Operator))
+	}
+
+	if predicate.Value == nil {
+		switch predicate.Operator {
+		case restrict.EqualOperator:
+			return fmt.Sprintf("%s IS NULL", column), nil
+		case restrict.NotEqualOperator:
+			return fmt.Sprintf("%s IS NOT NULL", column), nil
+		}
+	}
+
+	return fmt.Sprintf("%s %s %s", column, operator, q.addArg(predicate.Value)), nil
+}
+
+// renderMembership - renders FieldPredicate with IN or NOT IN operator.
+func (q *sqlQuery) renderMembership(column string, predicate *restrict.FieldPredicate) (string, error) {
+	elements, ok := predicate.Value.([]interface{})
+	if !ok {
+		return "", newPredicateNotSupportedError(predicate.String(), fmt.Errorf("value is not a list"))
+	}
+
+	// Empty lists are not valid in SQL.
+	if len(elements) == 0 {
+		if predicate.Operator == restrict.InOperator {
+			return "1 = 0", nil
+		}
+
+		return "1 = 1", nil
+	}
+
+	placeholders := []string{}
+
+	for _, element := range elements {
+		placeholders = append(placeholders, q.addArg(element))
+	}
+
+	return fmt.Sprintf("%s %s (%s)", column, predicate.Operator, strings.Join(placeholders, ", ")), nil
+}
+
+// addArg - adds given value to query's arguments, and returns its placeholder.
+func (q *sqlQuery) addArg(value interface{}) string {
+	q.args = append(q.args, value)
+
+	if q.renderer.placeholder == DollarPlaceholder {
+		return fmt.Sprintf("$%d", len(q.args))
+	}
+
+	return "?"
+}
@@ -0,0 +1,115 @@
+package renderers
+
+import (
+	"testing"
+
+	"github.com/el-mike/restrict/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type sqlRendererSuite struct {
+	suite.Suite
+}
+
+func TestSQLRendererSuite(t *testing.T) {
+	suite.Run(t, new(sqlRendererSuite))
+}
+
+func (s *sqlRendererSuite) TestRender() {
+	testPredicate := &restrict.OrPredicate{
+		Predicates: []restrict.Predicate{
+			&restrict.FieldPredicate{Field: "OwnerID", Operator: restrict.EqualOperator, Value: "u1"},
+			&restrict.AndPredicate{
+				Predicates: []restrict.Predicate{
+					&restrict.FieldPredicate{Field: "Public", Operator: restrict.EqualOperator, Value: true},
+					&restrict.FieldPredicate{Field: "Team", Operator: restrict.InOperator, Value: []interface{}{"a", "b"}},
+					&restrict.NotPredicate{
+						Predicate: &restrict.FieldPredicate{Field: "DeletedAt", Operator: restrict.EqualOperator, Value: nil},
+					},
+				},
+			},
+		},
+	}
+
+	renderer := NewSQLRenderer(QuestionPlaceholder, nil)
+
+	query, args, err := renderer.Render(testPredicate)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "(OwnerID = ? OR (Public = ? AND Team IN (?, ?) AND NOT (DeletedAt IS NULL)))", query)
+	assert.Equal(s.T(), []interface{}{"u1", true, "a", "b"}, args)
+
+	renderer = NewSQLRenderer(DollarPlaceholder, map[string]string{
+		"OwnerID":   "owner_id",
+		"Public":    "is_public",
+		"Team":      "team",
+		"DeletedAt": "deleted_at",
+	})
+
+	query, args, err = renderer.Render(testPredicate)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "(owner_id = $1 OR (is_public = $2 AND team IN ($3, $4) AND NOT (deleted_at IS NULL)))", query)
+	assert.Equal(s.T(), []interface{}{"u1", true, "a", "b"}, args)
+}
+
+func (s *sqlRendererSuite) TestRender_Operators() {
+	renderer := NewSQLRenderer(QuestionPlaceholder, nil)
+
+	testCases := []struct {
+		predicate restrict.Predicate
+		query     string
+		args      []interface{}
+	}{
+		{&restrict.ConstantPredicate{Value: true}, "1 = 1", []interface{}{}},
+		{&restrict.ConstantPredicate{Value: false}, "1 = 0", []interface{}{}},
+		{&restrict.FieldPredicate{Field: "a", Operator: restrict.NotEqualOperator, Value: 1}, "a <> ?", []interface{}{1}},
+		{&restrict.FieldPredicate{Field: "a", Operator: restrict.GreaterThanOperator, Value: 1}, "a > ?", []interface{}{1}},
+		{&restrict.FieldPredicate{Field: "a", Operator: restrict.GreaterThanOrEqualOperator, Value: 1}, "a >= ?", []interface{}{1}},
+		{&restrict.FieldPredicate{Field: "a", Operator: restrict.LessThanOperator, Value: 1}, "a < ?", []interface{}{1}},
+		{&restrict.FieldPredicate{Field: "a", Operator: restrict.LessThanOrEqualOperator, Value: 1}, "a <= ?", []interface{}{1}},
+		{&restrict.FieldPredicate{Field: "a", Operator: restrict.EqualOperator, Value: nil}, "a IS NULL", []interface{}{}},
+		{&restrict.FieldPredicate{Field: "a", Operator: restrict.NotEqualOperator, Value: nil}, "a IS NOT NULL", []interface{}{}},
+		{&restrict.FieldPredicate{Field: "a", Operator: restrict.NotInOperator, Value: []interface{}{1}}, "a NOT IN (?)", []interface{}{1}},
+		{&restrict.FieldPredicate{Field: "a", Operator: restrict.InOperator, Value: []interface{}{}}, "1 = 0", []interface{}{}},
+		{&restrict.FieldPredicate{Field: "a", Operator: restrict.NotInOperator, Value: []interface{}{}}, "1 = 1", []interface{}{}},
+		{&restrict.FieldPredicate{Field: "t.a", Operator: restrict.EqualOperator, Value: 1}, "t.a = ?", []interface{}{1}},
+	}
+
+	for _, testCase := range testCases {
+		query, args, err := renderer.Render(testCase.predicate)
+
+		assert.Nil(s.T(), err)
+		assert.Equal(s.T(), testCase.query, query)
+		assert.Equal(s.T(), testCase.args, args)
+	}
+}
+
+func (s *sqlRendererSuite) TestRender_Errors() {
+	renderer := NewSQLRenderer(QuestionPlaceholder, nil)
+
+	_, _, err := renderer.Render(&restrict.FieldPredicate{Field: "a; DROP TABLE users", Operator: restrict.EqualOperator, Value: 1})
+
+	assert.IsType(s.T(), new(ColumnNotFoundError), err)
+
+	_, _, err = renderer.Render(&restrict.FieldPredicate{Field: "a", Operator: "LIKE", Value: 1})
+
+	assert.IsType(s.T(), new(PredicateNotSupportedError), err)
+
+	_, _, err = renderer.Render(&restrict.FieldPredicate{Field: "a", Operator: restrict.InOperator, Value: 1})
+
+	assert.IsType(s.T(), new(PredicateNotSupportedError), err)
+
+	_, _, err = renderer.Render(nil)
+
+	assert.IsType(s.T(), new(PredicateNotSupportedError), err)
+
+	renderer = NewSQLRenderer(QuestionPlaceholder, map[string]string{})
+
+	_, _, err = renderer.Render(&restrict.NotPredicate{
+		Predicate: &restrict.FieldPredicate{Field: "a", Operator: restrict.EqualOperator, Value: 1},
+	})
+
+	assert.IsType(s.T(), new(ColumnNotFoundError), err)
+}
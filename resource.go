@@ -8,6 +8,15 @@ type Resource interface {
 	GetResourceName() string
 }
 
+// HierarchicalResource - optional interface for Resources that belong to a parent Resource,
+// e.g. a Document inside a Folder. When no Permission applies to the Resource itself,
+// AccessManager checks Permissions granted for its parent, up the chain.
+type HierarchicalResource interface {
+	Resource
+	// GetParentResource - returns Resource's parent, or nil if Resource has no parent.
+	GetParentResource() Resource
+}
+
 // baseResource - Resource implementation, to be used when proper Resource
 // is impossible or not feasible to obtain.
 type baseResource struct {
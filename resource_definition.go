@@ -0,0 +1,108 @@
+package restrict
+
+import "fmt"
+
+// ConditionsTarget - describes which Resource the Conditions of inherited Permissions are checked against.
+type ConditionsTarget string
+
+const (
+	// OriginalResource - Conditions are checked against the Resource from AccessRequest. Default ConditionsTarget.
+	OriginalResource ConditionsTarget = "original"
+	// AncestorResource - Conditions are checked against the ancestor Resource the Permissions are granted for.
+	// Falls back to OriginalResource when ancestor's instance is not known, i.e. when the ancestor
+	// comes from ResourceDefinition's Parent rather than HierarchicalResource.
+	AncestorResource ConditionsTarget = "ancestor"
+)
+
+// ResourceDefinition - describes Resource's place in the Resources hierarchy.
+type ResourceDefinition struct {
+	// Parent - name of the parent Resource, which Permissions are inherited when no Permission
+	// applies to the Resource itself. Used only when Resource does not provide its parent
+	// with HierarchicalResource interface.
+	Parent string `json:"parent,omitempty" yaml:"parent,omitempty"`
+	// ConditionsTarget - Resource that Conditions of Permissions inherited from this Resource
+	// are checked against. Defaults to OriginalResource.
+	ConditionsTarget ConditionsTarget `json:"conditionsTarget,omitempty" yaml:"conditionsTarget,omitempty"`
+}
+
+// copy - returns a copy of the ResourceDefinition.
+func (d *ResourceDefinition) copy() *ResourceDefinition {
+	if d == nil {
+		return nil
+	}
+
+	definition := *d
+
+	return &definition
+}
+
+// ResourceDefinitions - alias type for map of ResourceDefinitions, keyed by Resource name.
+type ResourceDefinitions map[string]*ResourceDefinition
+
+// copy - returns a deep copy of the ResourceDefinitions.
+func (rd ResourceDefinitions) copy() ResourceDefinitions {
+	if rd == nil {
+		return nil
+	}
+
+	result := make(ResourceDefinitions, len(rd))
+
+	for resourceName, definition := range rd {
+		result[resourceName] = definition.copy()
+	}
+
+	return result
+}
+
+// validate - returns an error if any of the definitions is malformed, or declared Parents form a cycle.
+func (rd ResourceDefinitions) validate() error {
+	for resourceName, definition := range rd {
+		if definition == nil {
+			continue
+		}
+
+		switch definition.ConditionsTarget {
+		case "", OriginalResource, AncestorResource:
+		default:
+			return newResourceDefinitionMalformedError(
+				resourceName,
+				fmt.Errorf("unknown ConditionsTarget: \"%s\"", definition.ConditionsTarget),
+			)
+		}
+
+		checkedResources := []string{resourceName}
+
+		for parent := rd.getParent(resourceName); parent != ""; parent = rd.getParent(parent) {
+			checkedResources = append(checkedResources, parent)
+
+			if parent == resourceName {
+				return newResourceHierarchyCycleError(checkedResources)
+			}
+
+			// Cycle not involving given Resource will be reported for one of the Resources forming it.
+			if len(checkedResources) > len(rd)+1 {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// getParent - returns declared Parent of given Resource, or empty string if there is none.
+func (rd ResourceDefinitions) getParent(resourceName string) string {
+	if definition := rd[resourceName]; definition != nil {
+		return definition.Parent
+	}
+
+	return ""
+}
+
+// getConditionsTarget - returns ConditionsTarget of given definition, or the default one.
+func (d *ResourceDefinition) getConditionsTarget() ConditionsTarget {
+	if d == nil || d.ConditionsTarget == "" {
+		return OriginalResource
+	}
+
+	return d.ConditionsTarget
+}
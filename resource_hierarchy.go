@@ -0,0 +1,109 @@
+package restrict
+
+import (
+	"reflect"
+
+	"github.com/el-mike/restrict/v2/internal/utils"
+)
+
+// maxResourceHierarchyDepth - maximum number of Resource's ancestors. HierarchicalResources returning
+// new parent instances every time cannot be checked for cycles, so the depth has to be bound.
+const maxResourceHierarchyDepth = 64
+
+// resourceHierarchy - iterates over the Resource and its ancestors, detecting cycles.
+type resourceHierarchy struct {
+	// resource - current Resource, nil if only its name is known (i.e. it comes
+	// from ResourceDefinition's Parent).
+	resource Resource
+	// name - current Resource's name.
+	name string
+
+	// checkedNames - names of all checked Resources, in order.
+	checkedNames []string
+	// checkedResources - instances of checked Resources.
+	checkedResources []Resource
+	// checkedDefinitions - names of Resources reached via ResourceDefinition's Parent.
+	checkedDefinitions []string
+}
+
+// newResourceHierarchy - returns new resourceHierarchy instance, starting with given Resource.
+func newResourceHierarchy(resource Resource, name string) *resourceHierarchy {
+	return &resourceHierarchy{
+		resource:         resource,
+		name:             name,
+		checkedNames:     []string{name},
+		checkedResources: []Resource{resource},
+	}
+}
+
+// next - moves to the parent of current Resource, provided by HierarchicalResource or,
+// if it's not available, by current Resource's definition. Returns false if there is no parent.
+func (h *resourceHierarchy) next(definition *ResourceDefinition) (bool, error) {
+	if hierarchical, ok := h.resource.(HierarchicalResource); ok && !utils.IsNilPointer(h.resource) {
+		parent := hierarchical.GetParentResource()
+
+		if parent != nil && !utils.IsNilPointer(parent) {
+			h.name = parent.GetResourceName()
+			h.checkedNames = append(h.checkedNames, h.name)
+
+			if h.isTooDeep() {
+				return false, newResourceHierarchyTooDeepError(h.checkedNames[0], maxResourceHierarchyDepth)
+			}
+
+			if containsResource(h.checkedResources, parent) {
+				return false, newResourceHierarchyCycleError(h.checkedNames)
+			}
+
+			h.resource = parent
+			h.checkedResources = append(h.checkedResources, parent)
+
+			return true, nil
+		}
+	}
+
+	if definition == nil || definition.Parent == "" {
+		return false, nil
+	}
+
+	h.resource = nil
+	h.name = definition.Parent
+	h.checkedNames = append(h.checkedNames, h.name)
+
+	if h.isTooDeep() {
+		return false, newResourceHierarchyTooDeepError(h.checkedNames[0], maxResourceHierarchyDepth)
+	}
+
+	if utils.StringSliceContains(h.checkedDefinitions, h.name) {
+		return false, newResourceHierarchyCycleError(h.checkedNames)
+	}
+
+	h.checkedDefinitions = append(h.checkedDefinitions, h.name)
+
+	return true, nil
+}
+
+// isAncestor - returns true if current Resource is an ancestor of the original one.
+func (h *resourceHierarchy) isAncestor() bool {
+	return len(h.checkedNames) > 1
+}
+
+// isTooDeep - returns true if current Resource exceeds maximum depth of the hierarchy.
+func (h *resourceHierarchy) isTooDeep() bool {
+	return len(h.checkedNames)-1 > maxResourceHierarchyDepth
+}
+
+// containsResource - returns true if given Resource instance is present in passed slice.
+// Resources of non-comparable types are never considered the same.
+func containsResource(resources []Resource, resource Resource) bool {
+	if !reflect.TypeOf(resource).Comparable() {
+		return false
+	}
+
+	for _, checked := range resources {
+		if checked != nil && reflect.TypeOf(checked) == reflect.TypeOf(resource) && checked == resource {
+			return true
+		}
+	}
+
+	return false
+}
@@ -3,12 +3,87 @@ package restrict
 import (
 	"encoding/json"
 
+	"github.com/el-mike/restrict/v2/internal/utils"
 	"gopkg.in/yaml.v3"
 )
 
-// GrantsMap - alias type for map of Permission slices.
+// GrantsMap - alias type for map of Permission slices. Keys are Resource names or
+// glob patterns matching them (e.g. "billing/*" or "*").
 type GrantsMap map[string]Permissions
 
+// matching - returns Resource names (and patterns) Permissions are granted for, matching given
+// Resource name, in order of precedence: exact Resource name first, then patterns from the most
+// specific one.
+func (g GrantsMap) matching(resourceName string) []string {
+	keys := make([]string, 0, len(g))
+
+	for key := range g {
+		keys = append(keys, key)
+	}
+
+	return utils.FindMatches(keys, resourceName)
+}
+
+// forAction - returns Permissions granted for given Resource name, matching given Action.
+// Precedence of Resource names is resolved per Action - Permissions are taken from the most specific
+// Resource name (see matching) granting any Permission for the Action (see Permissions' forAction),
+// so e.g. Permissions granted for "*" still apply to Actions not mentioned for the exact Resource name.
+// Deny Permissions granted for less specific matching Resource names are always included as well,
+// as precedence never bypasses them.
+func (g GrantsMap) forAction(resourceName, action string) Permissions {
+	keys := g.matching(resourceName)
+
+	for i, key := range keys {
+		result := g[key].forAction(action)
+		if len(result) == 0 {
+			continue
+		}
+
+		for _, other := range keys[i+1:] {
+			for _, permission := range g[other].denying([]string{action}) {
+				if !result.contains(permission) {
+					result = append(result, permission)
+				}
+			}
+		}
+
+		return result
+	}
+
+	return nil
+}
+
+// forActions - returns Permissions granted for given Resource name, matching any of given Actions
+// (see forAction), in order of the Actions, without duplicates.
+func (g GrantsMap) forActions(resourceName string, actions []string) Permissions {
+	result := Permissions{}
+
+	for _, action := range actions {
+		for _, permission := range g.forAction(resourceName, action) {
+			if !result.contains(permission) {
+				result = append(result, permission)
+			}
+		}
+	}
+
+	return result
+}
+
+// copy - returns a deep copy of the GrantsMap.
+func (g GrantsMap) copy() GrantsMap {
+	if g == nil {
+		return nil
+	}
+
+	result := make(GrantsMap, len(g))
+
+	for key, permissions := range g {
+		result[key] = permissions.copy()
+	}
+
+	return result
+}
+
 // Role - describes privileges of a Role's members.
 type Role struct {
 	// ID - unique identifier of the Role.
@@ -22,9 +97,40 @@ type Role struct {
 	Parents []string `json:"parents,omitempty" yaml:"parents,omitempty"`
 }
 
+// copy - returns a deep copy of the Role.
+func (r *Role) copy() *Role {
+	if r == nil {
+		return nil
+	}
+
+	role := *r
+	role.Grants = r.Grants.copy()
+
+	if r.Parents != nil {
+		role.Parents = append([]string{}, r.Parents...)
+	}
+
+	return &role
+}
+
 // Roles - alias type for map of Roles.
 type Roles map[string]*Role
 
+// copy - returns a deep copy of the Roles.
+func (rs Roles) copy() Roles {
+	if rs == nil {
+		return nil
+	}
+
+	result := make(Roles, len(rs))
+
+	for key, role := range rs {
+		result[key] = role.copy()
+	}
+
+	return result
+}
+
 // UnmarshalJSON - unmarshals a JSON-coded map of Roles.
 func (rs *Roles) UnmarshalJSON(jsonData []byte) error {
 	*rs = Roles{}
@@ -0,0 +1,106 @@
+package restrict
+
+import (
+	"sort"
+
+	"github.com/el-mike/restrict/v2/internal/utils"
+)
+
+// RoleAccess - describes how a Role is granted an Action on a Resource.
+type RoleAccess struct {
+	// RoleName - name of the Role.
+	RoleName string
+	// Permissions - Permissions matching the Action, granted for the Role directly or through its parents,
+	// including the ones explicitly denying it. Every Permission applies only when its Conditions are satisfied.
+	Permissions []*AppliedPermission
+}
+
+// getRolesAccess - returns RoleAccess for every Role in given PolicyDefinition that is granted given Action
+// on given Resource (or its ancestors), sorted by Role's name. Given PolicyIndex has to be built from
+// the PolicyDefinition - Roles' flattened ancestors are taken from it.
+func (p *PolicyDefinition) getRolesAccess(index *PolicyIndex, action, resourceName string) ([]*RoleAccess, error) {
+	result := []*RoleAccess{}
+
+	if p == nil {
+		return result, nil
+	}
+
+	roleNames := []string{}
+
+	for roleName := range p.Roles {
+		roleNames = append(roleNames, roleName)
+	}
+
+	sort.Strings(roleNames)
+
+	for _, roleName := range roleNames {
+		permissions := []*AppliedPermission{}
+		checkedResources := []string{}
+
+		// Ancestors declared in ResourceDefinitions are checked as well.
+		for name := resourceName; name != "" && !utils.StringSliceContains(checkedResources, name); name = p.Resources.getParent(name) {
+			checkedResources = append(checkedResources, name)
+
+			actions := append([]string{action}, p.Actions.getImplyingActions(name, action)...)
+
+			if err := p.collectRoleAccess(index, roleName, name, actions, &permissions); err != nil {
+				return nil, err
+			}
+		}
+
+		if !hasAllowingPermission(permissions) {
+			continue
+		}
+
+		result = append(result, &RoleAccess{
+			RoleName:    roleName,
+			Permissions: permissions,
+		})
+	}
+
+	return result, nil
+}
+
+// collectRoleAccess - collects Permissions granted for given Actions and Resource name by given Role
+// and its parents (depth-first). Every ancestor is visited once, with the first inheritance path leading
+// to it, and inheritance cycles are skipped, as they would not bring any new Permissions.
+func (p *PolicyDefinition) collectRoleAccess(
+	index *PolicyIndex,
+	roleName string,
+	resourceName string,
+	actions []string,
+	permissions *[]*AppliedPermission,
+) error {
+	for _, ancestor := range index.getAncestors(roleName) {
+		if ancestor.isCycle {
+			continue
+		}
+
+		role, ok := p.Roles[ancestor.name]
+		if !ok || role == nil {
+			return newRoleNotFoundError(ancestor.name)
+		}
+
+		for _, permission := range role.Grants.forActions(resourceName, actions) {
+			*permissions = append(*permissions, &AppliedPermission{
+				Permission:      permission.copy(),
+				RoleName:        ancestor.name,
+				InheritancePath: append([]string{}, ancestor.path...),
+				ResourceName:    resourceName,
+			})
+		}
+	}
+
+	return nil
+}
+
+// hasAllowingPermission - returns true if any of given Permissions allows its Action.
+func hasAllowingPermission(permissions []*AppliedPermission) bool {
+	for _, applied := range permissions {
+		if !applied.Permission.isDeny() {
+			return true
+		}
+	}
+
+	return false
+}
@@ -118,3 +118,67 @@ func (s *roleSuite) TestUnmarshalYAML_InvalidData() {
 	assert.Error(s.T(), err)
 	assert.NotPanics(s.T(), func() { yaml.Unmarshal(rolesData, &testRoles) }) // nolint
 }
+
+func (s *roleSuite) TestGrantsMapMatching() {
+	testGrants := GrantsMap{
+		"billing/invoices": Permissions{&Permission{Action: "read"}},
+		"billing/*":        Permissions{&Permission{Action: "update"}},
+		"*":                Permissions{&Permission{Action: "*"}},
+	}
+
+	assert.Equal(s.T(), []string{"billing/invoices", "billing/*", "*"}, testGrants.matching("billing/invoices"))
+	assert.Equal(s.T(), []string{"billing/*", "*"}, testGrants.matching("billing/payments"))
+	assert.Equal(s.T(), []string{"*"}, testGrants.matching("billing/payments/items"))
+	assert.Equal(s.T(), []string{"*"}, testGrants.matching("users"))
+
+	delete(testGrants, "*")
+
+	assert.Equal(s.T(), []string{}, testGrants.matching("users"))
+
+	var nilGrants GrantsMap
+
+	assert.Equal(s.T(), []string{}, nilGrants.matching("users"))
+}
+
+func (s *roleSuite) TestGrantsMapForActions() {
+	readPermission := &Permission{Action: "read"}
+	denyBillingPermission := &Permission{Action: "read", Effect: DenyEffect}
+	billingPermission := &Permission{Action: "update"}
+	denyAllPermission := &Permission{Action: "*", Effect: DenyEffect}
+
+	testGrants := GrantsMap{
+		"billing/invoices": {readPermission},
+		"billing/*":        {billingPermission, denyBillingPermission},
+		"*":                {denyAllPermission},
+	}
+
+	// Deny Permissions of other matching patterns are included, in order of patterns' precedence.
+	assert.Equal(
+		s.T(),
+		Permissions{readPermission, denyBillingPermission, denyAllPermission},
+		testGrants.forActions("billing/invoices", []string{"read"}),
+	)
+	// Precedence is resolved per Action - patterns apply to Actions not granted for the exact Resource name.
+	assert.Equal(
+		s.T(),
+		Permissions{billingPermission, denyAllPermission},
+		testGrants.forActions("billing/invoices", []string{"update"}),
+	)
+	assert.Equal(
+		s.T(),
+		Permissions{readPermission, denyBillingPermission, denyAllPermission, billingPermission},
+		testGrants.forActions("billing/invoices", []string{"read", "update"}),
+	)
+	assert.Equal(
+		s.T(),
+		Permissions{billingPermission, denyAllPermission},
+		testGrants.forActions("billing/payments", []string{"update"}),
+	)
+	assert.Equal(s.T(), Permissions{denyAllPermission}, testGrants.forActions("users", []string{"read"}))
+
+	delete(testGrants, "*")
+
+	assert.Equal(s.T(), Permissions{billingPermission}, testGrants.forActions("billing/invoices", []string{"update"}))
+	assert.Equal(s.T(), Permissions{}, testGrants.forActions("billing/invoices", []string{"delete"}))
+	assert.Equal(s.T(), Permissions{}, testGrants.forActions("users", []string{"read"}))
+}
@@ -0,0 +1,344 @@
+package restrict
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// TraceStepKind - kind of a single evaluation step.
+type TraceStepKind string
+
+const (
+	// ActionStep - evaluation of an Action.
+	ActionStep TraceStepKind = "action"
+	// ResourceStep - evaluation of an Action for the Resource, or one of its ancestors.
+	ResourceStep TraceStepKind = "resource"
+	// RoleStep - evaluation of a Role's Permissions, or one of its parents.
+	RoleStep TraceStepKind = "role"
+	// PermissionStep - check of a single Permission.
+	PermissionStep TraceStepKind = "permission"
+	// ConditionStep - check of a single Condition.
+	ConditionStep TraceStepKind = "condition"
+)
+
+// TraceOutcome - outcome of a single evaluation step.
+type TraceOutcome string
+
+const (
+	// AllowedOutcome - Action has been allowed.
+	AllowedOutcome TraceOutcome = "allowed"
+	// DeniedOutcome - Action has been denied.
+	DeniedOutcome TraceOutcome = "denied"
+	// AppliedOutcome - Permission has applied, as its Conditions were satisfied or skipped.
+	AppliedOutcome TraceOutcome = "applied"
+	// NotApplicableOutcome - Permission (or any Permission for a Resource) has not applied.
+	NotApplicableOutcome TraceOutcome = "not applicable"
+	// SatisfiedOutcome - Condition has been satisfied.
+	SatisfiedOutcome TraceOutcome = "satisfied"
+	// NotSatisfiedOutcome - Condition has not been satisfied.
+	NotSatisfiedOutcome TraceOutcome = "not satisfied"
+	// SkippedOutcome - step has been skipped, e.g. because of an already checked parent Role.
+	SkippedOutcome TraceOutcome = "skipped"
+	// FailedOutcome - step has failed with an error not related to the policy.
+	FailedOutcome TraceOutcome = "failed"
+)
+
+// TraceValue - a value resolved by ValueDescriptor while checking a Condition.
+type TraceValue struct {
+	// Name - name of Condition's field holding the ValueDescriptor, e.g. "left" or "right".
+	Name string `json:"name,omitempty"`
+	// Source - ValueDescriptor's Source.
+	Source string `json:"source"`
+	// Field - ValueDescriptor's Field, empty for Explicit and CurrentTime Sources.
+	Field string `json:"field,omitempty"`
+	// Value - resolved value.
+	Value interface{} `json:"value"`
+	// Error - resolution error, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// MarshalJSON - marshals TraceValue into JSON. Values that cannot be marshaled
+// are represented by their default string format.
+func (v *TraceValue) MarshalJSON() ([]byte, error) {
+	type traceValue TraceValue
+
+	marshalable := traceValue(*v)
+
+	if _, err := json.Marshal(v.Value); err != nil {
+		marshalable.Value = fmt.Sprintf("%v", v.Value)
+	}
+
+	return json.Marshal(&marshalable)
+}
+
+// String - Stringer implementation.
+func (v *TraceValue) String() string {
+	descriptor := v.Source
+
+	if v.Field != "" {
+		descriptor += fmt.Sprintf(" \"%s\"", v.Field)
+	}
+
+	if v.Name != "" {
+		descriptor = v.Name + ": " + descriptor
+	}
+
+	if v.Error != "" {
+		return fmt.Sprintf("%s - error: %s", descriptor, v.Error)
+	}
+
+	return fmt.Sprintf("%s = %#v", descriptor, v.Value)
+}
+
+// TraceStep - single step of AccessRequest's evaluation, along with its nested steps.
+type TraceStep struct {
+	// Kind - kind of the step.
+	Kind TraceStepKind `json:"kind"`
+	// Name - name of evaluated Action, Resource or Role, Action of a Permission, or type of a Condition.
+	Name string `json:"name"`
+	// Outcome - outcome of the step. Empty for steps that do not decide anything by themselves.
+	Outcome TraceOutcome `json:"outcome,omitempty"`
+	// Details - additional information about the step, e.g. failure's reason.
+	Details string `json:"details,omitempty"`
+	// Values - values resolved while checking a Condition.
+	Values []*TraceValue `json:"values,omitempty"`
+	// Steps - nested steps.
+	Steps []*TraceStep `json:"steps,omitempty"`
+
+	// condition - checked Condition, used for naming resolved values.
+	condition Condition
+}
+
+// setOutcome - sets step's outcome and details. Safe to call on nil step.
+func (s *TraceStep) setOutcome(outcome TraceOutcome, details string) {
+	if s == nil {
+		return
+	}
+
+	s.Outcome = outcome
+	s.Details = details
+}
+
+// write - writes human-readable representation of the step and its nested steps.
+func (s *TraceStep) write(builder *strings.Builder, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	builder.WriteString(fmt.Sprintf("%s%s \"%s\"", indent, s.Kind, s.Name))
+
+	if s.Outcome != "" {
+		builder.WriteString(fmt.Sprintf(": %s", s.Outcome))
+	}
+
+	if s.Details != "" {
+		builder.WriteString(fmt.Sprintf(" (%s)", s.Details))
+	}
+
+	builder.WriteString("\n")
+
+	for _, value := range s.Values {
+		builder.WriteString(fmt.Sprintf("%s  %s\n", indent, value))
+	}
+
+	for _, step := range s.Steps {
+		step.write(builder, depth+1)
+	}
+}
+
+// Trace - a tree of evaluation steps, recorded when AccessRequest's Trace is set to true.
+type Trace struct {
+	// Steps - evaluation steps for every Action.
+	Steps []*TraceStep `json:"steps"`
+}
+
+// String - returns human-readable representation of the Trace.
+func (t *Trace) String() string {
+	builder := &strings.Builder{}
+
+	for _, step := range t.Steps {
+		step.write(builder, 0)
+	}
+
+	return builder.String()
+}
+
+// JSON - returns indented JSON representation of the Trace.
+func (t *Trace) JSON() ([]byte, error) {
+	return json.MarshalIndent(t, "", "  ")
+}
+
+// tracer - records evaluation steps. All methods are safe to call on nil tracer,
+// in which case nothing is recorded.
+type tracer struct {
+	trace *Trace
+	stack []*TraceStep
+}
+
+// newTracer - returns new tracer instance.
+func newTracer() *tracer {
+	return &tracer{
+		trace: &Trace{
+			Steps: []*TraceStep{},
+		},
+	}
+}
+
+// getTrace - returns recorded Trace, or nil if tracing is disabled.
+func (t *tracer) getTrace() *Trace {
+	if t == nil {
+		return nil
+	}
+
+	return t.trace
+}
+
+// start - starts a new step, nested in the current one.
+func (t *tracer) start(kind TraceStepKind, name string) *TraceStep {
+	if t == nil {
+		return nil
+	}
+
+	step := &TraceStep{
+		Kind: kind,
+		Name: name,
+	}
+
+	if len(t.stack) == 0 {
+		t.trace.Steps = append(t.trace.Steps, step)
+	} else {
+		parent := t.stack[len(t.stack)-1]
+		parent.Steps = append(parent.Steps, step)
+	}
+
+	t.stack = append(t.stack, step)
+
+	return step
+}
+
+// startCondition - starts a new step for given Condition.
+func (t *tracer) startCondition(condition Condition) *TraceStep {
+	if t == nil {
+		return nil
+	}
+
+	step := t.start(ConditionStep, condition.Type())
+	step.condition = condition
+
+	return step
+}
+
+// finish - finishes given step, along with any unfinished steps nested in it.
+func (t *tracer) finish(step *TraceStep) {
+	if t == nil || step == nil {
+		return
+	}
+
+	for i := len(t.stack) - 1; i >= 0; i-- {
+		if t.stack[i] == step {
+			t.stack = t.stack[:i]
+			return
+		}
+	}
+}
+
+// add - adds a finished step, nested in the current one.
+func (t *tracer) add(kind TraceStepKind, name string, outcome TraceOutcome, details string) {
+	step := t.start(kind, name)
+	step.setOutcome(outcome, details)
+	t.finish(step)
+}
+
+// recordValue - records a value resolved by given ValueDescriptor in the current step.
+func (t *tracer) recordValue(descriptor *ValueDescriptor, value interface{}, err error) {
+	if t == nil || len(t.stack) == 0 || descriptor == nil {
+		return
+	}
+
+	step := t.stack[len(t.stack)-1]
+
+	traceValue := &TraceValue{
+		Name:   getDescriptorName(step.condition, descriptor),
+		Source: descriptor.Source.String(),
+		Field:  descriptor.Field,
+		Value:  value,
+	}
+
+	if err != nil {
+		traceValue.Error = err.Error()
+	}
+
+	step.Values = append(step.Values, traceValue)
+}
+
+// valueDescriptorType - reflect.Type of ValueDescriptor pointer.
+var valueDescriptorType = reflect.TypeOf(&ValueDescriptor{})
+
+// getDescriptorName - returns the name of given Condition's field holding given ValueDescriptor
+// (its JSON name, if defined), or empty string if there is no such field.
+func getDescriptorName(condition Condition, descriptor *ValueDescriptor) string {
+	if condition == nil {
+		return ""
+	}
+
+	rCondition := reflect.ValueOf(condition)
+
+	for rCondition.Kind() == reflect.Ptr || rCondition.Kind() == reflect.Interface {
+		if rCondition.IsNil() {
+			return ""
+		}
+
+		rCondition = rCondition.Elem()
+	}
+
+	if rCondition.Kind() != reflect.Struct {
+		return ""
+	}
+
+	conditionType := rCondition.Type()
+
+	for i := 0; i < conditionType.NumField(); i++ {
+		field := conditionType.Field(i)
+
+		if field.Type != valueDescriptorType || field.PkgPath != "" {
+			continue
+		}
+
+		if rCondition.Field(i).Pointer() != reflect.ValueOf(descriptor).Pointer() {
+			continue
+		}
+
+		if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+			return name
+		}
+
+		return strings.ToLower(field.Name)
+	}
+
+	return ""
+}
+
+// checkCondition - checks given Condition, recording the step if tracing is enabled.
+func checkCondition(condition Condition, request *AccessRequest) error {
+	step := request.tracer.startCondition(condition)
+	defer request.tracer.finish(step)
+
+	err := checkConditionContext(condition, request)
+
+	switch err.(type) {
+	case nil:
+		step.setOutcome(SatisfiedOutcome, "")
+	case *ConditionNotSatisfiedError:
+		details := ""
+
+		if reason := err.(*ConditionNotSatisfiedError).Reason; reason != nil {
+			details = reason.Error()
+		}
+
+		step.setOutcome(NotSatisfiedOutcome, details)
+	default:
+		step.setOutcome(FailedOutcome, err.Error())
+	}
+
+	return err
+}
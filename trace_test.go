@@ -0,0 +1,149 @@
+package restrict
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type traceSuite struct {
+	suite.Suite
+}
+
+func TestTraceSuite(t *testing.T) {
+	suite.Run(t, new(traceSuite))
+}
+
+func (s *traceSuite) TestNilTracer() {
+	var testTracer *tracer
+
+	assert.NotPanics(s.T(), func() {
+		step := testTracer.start(ActionStep, readAction)
+		step.setOutcome(AllowedOutcome, "")
+		testTracer.add(RoleStep, basicRoleOneName, SkippedOutcome, "")
+		testTracer.recordValue(&ValueDescriptor{Source: Explicit}, 1, nil)
+		testTracer.finish(step)
+	})
+
+	assert.Nil(s.T(), testTracer.getTrace())
+}
+
+func (s *traceSuite) TestTracer() {
+	testTracer := newTracer()
+
+	actionStep := testTracer.start(ActionStep, readAction)
+	roleStep := testTracer.start(RoleStep, basicRoleOneName)
+
+	testTracer.add(RoleStep, basicParentRoleName, SkippedOutcome, "inheritance cycle")
+
+	testCondition := &EqualCondition{
+		Left:  &ValueDescriptor{Source: ResourceField, Field: "CreatedBy"},
+		Right: &ValueDescriptor{Source: SubjectField, Field: "ID"},
+	}
+
+	conditionStep := testTracer.startCondition(testCondition)
+
+	testTracer.recordValue(testCondition.Left, "user-1", nil)
+	testTracer.recordValue(testCondition.Right, nil, assert.AnError)
+	testTracer.recordValue(&ValueDescriptor{Source: Explicit, Value: 1}, 1, nil)
+
+	// Finishing the outer step finishes nested ones.
+	testTracer.finish(roleStep)
+
+	actionStep.setOutcome(DeniedOutcome, "")
+	testTracer.finish(actionStep)
+
+	testTracer.add(ActionStep, updateAction, AllowedOutcome, "")
+
+	trace := testTracer.getTrace()
+
+	assert.Len(s.T(), trace.Steps, 2)
+	assert.Equal(s.T(), []*TraceStep{roleStep}, trace.Steps[0].Steps)
+	assert.Len(s.T(), roleStep.Steps, 2)
+	assert.Equal(s.T(), conditionStep, roleStep.Steps[1])
+
+	assert.Equal(s.T(), "left", conditionStep.Values[0].Name)
+	assert.Equal(s.T(), "user-1", conditionStep.Values[0].Value)
+	assert.Equal(s.T(), "right", conditionStep.Values[1].Name)
+	assert.Equal(s.T(), assert.AnError.Error(), conditionStep.Values[1].Error)
+	assert.Equal(s.T(), "", conditionStep.Values[2].Name)
+
+	expected := "action \"read\": denied\n" +
+		"  role \"BasicRoleOne\"\n" +
+		"    role \"BasicParentRole\": skipped (inheritance cycle)\n" +
+		"    condition \"EQUAL\"\n" +
+		"      left: ResourceField \"CreatedBy\" = \"user-1\"\n" +
+		"      right: SubjectField \"ID\" - error: " + assert.AnError.Error() + "\n" +
+		"      Explicit = 1\n" +
+		"action \"update\": allowed\n"
+
+	assert.Equal(s.T(), expected, trace.String())
+}
+
+func (s *traceSuite) TestJSON() {
+	testTracer := newTracer()
+
+	step := testTracer.start(ConditionStep, EqualConditionType)
+	step.condition = &EqualCondition{}
+
+	testTracer.recordValue(&ValueDescriptor{Source: ContextField, Field: "Channel"}, make(chan int), nil)
+	testTracer.recordValue(&ValueDescriptor{Source: Explicit}, []int{1, 2}, nil)
+
+	step.setOutcome(NotSatisfiedOutcome, "values are not equal")
+	testTracer.finish(step)
+
+	traceJSON, err := testTracer.getTrace().JSON()
+
+	assert.Nil(s.T(), err)
+
+	result := map[string]interface{}{}
+
+	assert.Nil(s.T(), json.Unmarshal(traceJSON, &result))
+
+	steps := result["steps"].([]interface{})
+	conditionStep := steps[0].(map[string]interface{})
+	values := conditionStep["values"].([]interface{})
+
+	assert.Equal(s.T(), "condition", conditionStep["kind"])
+	assert.Equal(s.T(), "not satisfied", conditionStep["outcome"])
+	assert.Equal(s.T(), "values are not equal", conditionStep["details"])
+
+	// Values that cannot be marshaled are represented as strings.
+	assert.IsType(s.T(), "", values[0].(map[string]interface{})["value"])
+	assert.Equal(s.T(), "Channel", values[0].(map[string]interface{})["field"])
+	assert.Equal(s.T(), []interface{}{float64(1), float64(2)}, values[1].(map[string]interface{})["value"])
+}
+
+func (s *traceSuite) TestCheckCondition() {
+	testRequest := &AccessRequest{tracer: newTracer()}
+
+	testWorkingCondition := new(conditionMock)
+	testWorkingCondition.On("Type").Return(basicConditionOne)
+	testWorkingCondition.On("Check", mock.Anything).Return(nil)
+
+	testFailingCondition := new(conditionMock)
+	testFailingCondition.On("Type").Return(basicConditionOne)
+	testFailingCondition.On("Check", mock.Anything).Return(NewConditionNotSatisfiedError(testFailingCondition, testRequest, assert.AnError))
+
+	testErrorCondition := new(conditionMock)
+	testErrorCondition.On("Type").Return(basicConditionOne)
+	testErrorCondition.On("Check", mock.Anything).Return(assert.AnError)
+
+	assert.Nil(s.T(), checkCondition(testWorkingCondition, testRequest))
+	assert.IsType(s.T(), new(ConditionNotSatisfiedError), checkCondition(testFailingCondition, testRequest))
+	assert.Equal(s.T(), assert.AnError, checkCondition(testErrorCondition, testRequest))
+
+	steps := testRequest.tracer.getTrace().Steps
+
+	assert.Len(s.T(), steps, 3)
+	assert.Equal(s.T(), SatisfiedOutcome, steps[0].Outcome)
+	assert.Equal(s.T(), NotSatisfiedOutcome, steps[1].Outcome)
+	assert.Equal(s.T(), assert.AnError.Error(), steps[1].Details)
+	assert.Equal(s.T(), FailedOutcome, steps[2].Outcome)
+
+	// No tracing.
+	assert.Nil(s.T(), checkCondition(testWorkingCondition, &AccessRequest{}))
+}
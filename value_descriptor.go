@@ -10,14 +10,38 @@ import (
 type ValueDescriptor struct {
 	// Source - source of the value, one of the predefined enum type (ValueSource).
 	Source ValueSource `json:"source,omitempty" yaml:"source,omitempty"`
-	// Field - field on the given ValueSource that should hold the value.
+	// Field - field on the given ValueSource that should hold the value. Can be a path to
+	// a nested value, e.g. "Owner.Team.ID", "Tags[0]" or "meta.region".
 	Field string `json:"field,omitempty" yaml:"field,omitempty"`
 	// Value - explicit value taken when using ValueSource.Explicit as value source.
 	Value interface{} `json:"value,omitempty" yaml:"value,omitempty"`
 }
 
+// copy - returns a deep copy of the ValueDescriptor. Explicit slices and maps are copied as well.
+func (vd *ValueDescriptor) copy() *ValueDescriptor {
+	if vd == nil {
+		return nil
+	}
+
+	descriptor := *vd
+	descriptor.Value = utils.CopyCollection(vd.Value)
+
+	return &descriptor
+}
+
 // GetValue - returns real value represented by given ValueDescriptor.
 func (vd *ValueDescriptor) GetValue(request *AccessRequest) (interface{}, error) {
+	value, err := vd.getValue(request)
+
+	if request != nil {
+		request.tracer.recordValue(vd, value, err)
+	}
+
+	return value, err
+}
+
+// getValue - resolves the value described by ValueDescriptor.
+func (vd *ValueDescriptor) getValue(request *AccessRequest) (interface{}, error) {
 	if vd == nil {
 		return nil, newValueDescriptorMalformedError(vd, fmt.Errorf("ValueDescriptor cannot be nil"))
 	}
@@ -26,10 +50,18 @@ func (vd *ValueDescriptor) GetValue(request *AccessRequest) (interface{}, error)
 		return vd.Value, nil
 	}
 
+	if vd.Source == CurrentTime {
+		return request.Now(), nil
+	}
+
 	if vd.Field == "" {
 		return nil, newValueDescriptorMalformedError(vd, fmt.Errorf("Field cannot be empty for Source: \"%s\"", vd.Source.String()))
 	}
 
+	if vd.Source == Resolver {
+		return request.resolveValue(vd.Field)
+	}
+
 	var source interface{}
 
 	if vd.Source == SubjectField {
@@ -48,13 +80,16 @@ func (vd *ValueDescriptor) GetValue(request *AccessRequest) (interface{}, error)
 		return nil, newValueDescriptorMalformedError(vd, fmt.Errorf("Source could not be find"))
 	}
 
-	if utils.IsMap(source) {
-		return utils.GetMapValue(source, vd.Field), nil
-	}
+	value, err := utils.GetPathValue(source, vd.Field)
+	if err != nil {
+		malformedError := newValueDescriptorMalformedError(vd, fmt.Errorf("Field \"%s\" could not be resolved on Source: \"%s\": %s", vd.Field, vd.Source.String(), err.Error()))
+
+		if pathError, ok := err.(*utils.PathError); ok {
+			malformedError.segment = pathError.Segment
+		}
 
-	if utils.HasField(source, vd.Field) {
-		return utils.GetStructFieldValue(source, vd.Field), nil
+		return nil, malformedError
 	}
 
-	return nil, newValueDescriptorMalformedError(vd, fmt.Errorf("Field \"%s\" does not exist on Source: \"%s\"", vd.Field, vd.Source.String()))
+	return value, nil
 }
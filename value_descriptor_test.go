@@ -2,6 +2,7 @@ package restrict
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -48,6 +49,26 @@ func (s *valueDescriptorSuite) TestGetValue_Explicit() {
 	assert.Equal(s.T(), 1, value)
 }
 
+func (s *valueDescriptorSuite) TestGetValue_CurrentTime() {
+	testNow := time.Date(2023, time.March, 1, 12, 0, 0, 0, time.UTC)
+	testRequest := (&AccessRequest{}).withTime(testNow)
+
+	testDescriptor := &ValueDescriptor{
+		Source: CurrentTime,
+	}
+
+	value, err := testDescriptor.GetValue(testRequest)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), testNow, value)
+
+	// Falls back to system time.
+	value, err = testDescriptor.GetValue(&AccessRequest{})
+
+	assert.Nil(s.T(), err)
+	assert.WithinDuration(s.T(), time.Now(), value.(time.Time), time.Minute)
+}
+
 func (s *valueDescriptorSuite) TestGetValue_Subject() {
 	testSubject := new(subjectMock)
 
@@ -138,6 +159,148 @@ func (s *valueDescriptorSuite) TestGetValue_Context() {
 	assert.Equal(s.T(), testContext["FieldTwo"], value)
 }
 
+func (s *valueDescriptorSuite) TestGetValue_NestedField() {
+	testResource := new(resourceMock)
+	testResource.FieldThree = []int{1, 2}
+
+	testRequest := &AccessRequest{
+		Resource: testResource,
+		Context: Context{
+			"meta": map[string]interface{}{
+				"region": "eu",
+			},
+			"Owner": &subjectMock{ID: "user-one"},
+		},
+	}
+
+	testDescriptor := &ValueDescriptor{
+		Source: ResourceField,
+		Field:  "FieldThree[1]",
+	}
+
+	value, err := testDescriptor.GetValue(testRequest)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 2, value)
+
+	testDescriptor = &ValueDescriptor{
+		Source: ContextField,
+		Field:  "meta.region",
+	}
+
+	value, err = testDescriptor.GetValue(testRequest)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "eu", value)
+
+	testDescriptor.Field = "Owner.ID"
+
+	value, err = testDescriptor.GetValue(testRequest)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "user-one", value)
+
+	testDescriptor.Field = "Owner.Team.ID"
+
+	value, err = testDescriptor.GetValue(testRequest)
+
+	assert.Nil(s.T(), value)
+	assert.IsType(s.T(), new(ValueDescriptorMalformedError), err)
+	assert.Equal(s.T(), "Team", err.(*ValueDescriptorMalformedError).FailedSegment())
+	assert.Contains(s.T(), err.Error(), "Team")
+}
+
+type taggedResourceMock struct {
+	resourceMock
+
+	TenantID string `json:"tenantId"`
+	ownerID  string
+}
+
+func (m *taggedResourceMock) GetOwnerID() string {
+	return m.ownerID
+}
+
+func (s *valueDescriptorSuite) TestGetValue_TagsAndGetters() {
+	testResource := &taggedResourceMock{
+		TenantID: "tenant-one",
+		ownerID:  "user-one",
+	}
+
+	testRequest := &AccessRequest{
+		Resource: testResource,
+	}
+
+	testDescriptor := &ValueDescriptor{
+		Source: ResourceField,
+		Field:  "tenantId",
+	}
+
+	value, err := testDescriptor.GetValue(testRequest)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "tenant-one", value)
+
+	testDescriptor.Field = "ownerID"
+
+	value, err = testDescriptor.GetValue(testRequest)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "user-one", value)
+
+	testDescriptor.Field = "OwnerID"
+
+	value, err = testDescriptor.GetValue(testRequest)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "user-one", value)
+}
+
+type attributeSubjectMock struct {
+	subjectMock
+
+	attributes map[string]interface{}
+}
+
+func (m *attributeSubjectMock) GetAttribute(name string) (interface{}, bool) {
+	value, ok := m.attributes[name]
+
+	return value, ok
+}
+
+func (s *valueDescriptorSuite) TestGetValue_AttributeProvider() {
+	testSubject := &attributeSubjectMock{
+		attributes: map[string]interface{}{
+			"TeamIDs": []string{"team-one"},
+		},
+	}
+
+	// Field exists, but it's not used - AttributeProvider takes precedence.
+	testSubject.ID = "user-one"
+
+	testRequest := &AccessRequest{
+		Subject: testSubject,
+	}
+
+	testDescriptor := &ValueDescriptor{
+		Source: SubjectField,
+		Field:  "TeamIDs[0]",
+	}
+
+	value, err := testDescriptor.GetValue(testRequest)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "team-one", value)
+
+	testDescriptor.Field = "ID"
+
+	value, err = testDescriptor.GetValue(testRequest)
+
+	assert.Nil(s.T(), value)
+	assert.IsType(s.T(), new(ValueDescriptorMalformedError), err)
+	assert.Equal(s.T(), "ID", err.(*ValueDescriptorMalformedError).FailedSegment())
+}
+
 func (s *valueDescriptorSuite) TestGetValue_MissingSource() {
 	testDescriptor := &ValueDescriptor{
 		Source: noopValueSource,
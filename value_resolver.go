@@ -0,0 +1,50 @@
+package restrict
+
+import "context"
+
+// ValueResolver - function resolving a value for ValueDescriptor with Resolver Source, e.g. an attribute
+// fetched from an external service. Given context is the one passed to AccessManager's AuthorizeContext
+// (or background context otherwise), and should be honored for timeouts and cancellation.
+type ValueResolver func(ctx context.Context, request *AccessRequest) (interface{}, error)
+
+// ValueResolversMap - map of ValueResolvers.
+type ValueResolversMap = map[string]ValueResolver
+
+// ValueResolvers - stores a map of ValueResolvers, based on their names.
+var ValueResolvers = ValueResolversMap{}
+
+// RegisterValueResolver - adds a new ValueResolver under given name. If given name
+// is already taken, an error is returned.
+func RegisterValueResolver(name string, resolver ValueResolver) error {
+	if ValueResolvers[name] != nil {
+		return newValueResolverAlreadyExistsError(name)
+	}
+
+	ValueResolvers[name] = resolver
+	return nil
+}
+
+// resolveValue - returns a value resolved by ValueResolver with given name. Values are memoized
+// for the duration of a single authorization, so every ValueResolver is called at most once.
+func (ar *AccessRequest) resolveValue(name string) (interface{}, error) {
+	if value, ok := ar.resolvedValues[name]; ok {
+		return value, nil
+	}
+
+	resolver := ValueResolvers[name]
+
+	if resolver == nil {
+		return nil, newValueResolverNotFoundError(name)
+	}
+
+	value, err := resolver(ar.getContext(), ar)
+	if err != nil {
+		return nil, newValueResolverError(name, err)
+	}
+
+	if ar.resolvedValues != nil {
+		ar.resolvedValues[name] = value
+	}
+
+	return value, nil
+}
@@ -0,0 +1,176 @@
+package restrict
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	departmentResolverName = "department"
+	failingResolverName    = "failing"
+)
+
+type valueResolverSuite struct {
+	suite.Suite
+
+	calls int
+}
+
+func TestValueResolverSuite(t *testing.T) {
+	suite.Run(t, new(valueResolverSuite))
+}
+
+func (s *valueResolverSuite) SetupTest() {
+	s.calls = 0
+
+	ValueResolvers[departmentResolverName] = func(ctx context.Context, request *AccessRequest) (interface{}, error) {
+		s.calls++
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		return "dep-" + request.Subject.(*subjectMock).ID, nil
+	}
+
+	ValueResolvers[failingResolverName] = func(ctx context.Context, request *AccessRequest) (interface{}, error) {
+		return nil, errors.New("service unavailable")
+	}
+}
+
+func (s *valueResolverSuite) TearDownTest() {
+	delete(ValueResolvers, departmentResolverName)
+	delete(ValueResolvers, failingResolverName)
+}
+
+func (s *valueResolverSuite) TestRegisterValueResolver() {
+	err := RegisterValueResolver(departmentResolverName, ValueResolvers[departmentResolverName])
+
+	assert.IsType(s.T(), new(ValueResolverAlreadyExistsError), err)
+
+	delete(ValueResolvers, departmentResolverName)
+
+	err = RegisterValueResolver(departmentResolverName, func(ctx context.Context, request *AccessRequest) (interface{}, error) {
+		return nil, nil
+	})
+
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), ValueResolvers[departmentResolverName])
+}
+
+func (s *valueResolverSuite) TestGetValue() {
+	testRequest := &AccessRequest{
+		Subject: &subjectMock{ID: "user-1"},
+	}
+
+	testDescriptor := &ValueDescriptor{Source: Resolver, Field: departmentResolverName}
+
+	// Without memoization, resolver is called every time.
+	value, err := testDescriptor.GetValue(testRequest)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "dep-user-1", value)
+
+	_, _ = testDescriptor.GetValue(testRequest)
+
+	assert.Equal(s.T(), 2, s.calls)
+
+	_, err = (&ValueDescriptor{Source: Resolver}).GetValue(testRequest)
+
+	assert.IsType(s.T(), new(ValueDescriptorMalformedError), err)
+
+	_, err = (&ValueDescriptor{Source: Resolver, Field: "unknown"}).GetValue(testRequest)
+
+	assert.IsType(s.T(), new(ValueResolverNotFoundError), err)
+
+	_, err = (&ValueDescriptor{Source: Resolver, Field: failingResolverName}).GetValue(testRequest)
+
+	assert.IsType(s.T(), new(ValueResolverError), err)
+	assert.Equal(s.T(), failingResolverName, err.(*ValueResolverError).ResolverName())
+	assert.EqualError(s.T(), err.(*ValueResolverError).Reason(), "service unavailable")
+}
+
+func (s *valueResolverSuite) TestAuthorize() {
+	departmentCondition := &EqualCondition{
+		Left:  &ValueDescriptor{Source: Resolver, Field: departmentResolverName},
+		Right: &ValueDescriptor{Source: ResourceField, Field: "FieldOne"},
+	}
+
+	testRole := getBasicRoleOne()
+	testRole.Grants[basicResourceOneName] = []*Permission{
+		{Action: readAction, Conditions: Conditions{departmentCondition}},
+		{Action: updateAction, Conditions: Conditions{departmentCondition}},
+		{Action: deleteAction, Conditions: Conditions{
+			&EqualCondition{
+				Left:  &ValueDescriptor{Source: Resolver, Field: failingResolverName},
+				Right: &ValueDescriptor{Source: Explicit, Value: true},
+			},
+		}},
+	}
+
+	testPolicyProvider := new(policyProviderMock)
+	testPolicyProvider.On("GetRole", basicRoleOneName).Return(testRole, nil)
+
+	manager := NewAccessManager(testPolicyProvider)
+
+	testSubject := &subjectMock{ID: "user-1"}
+	testSubject.On("GetRoles").Return([]string{basicRoleOneName})
+
+	testResource := &resourceMock{FieldOne: "dep-user-1"}
+	testResource.On("GetResourceName").Return(basicResourceOneName)
+
+	testRequest := &AccessRequest{
+		Subject:  testSubject,
+		Resource: testResource,
+		Actions:  []string{readAction, updateAction},
+	}
+
+	// Values are memoized for a single Authorize call.
+	err := manager.Authorize(testRequest)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1, s.calls)
+
+	err = manager.Authorize(testRequest)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 2, s.calls)
+
+	testResource.FieldOne = "dep-user-2"
+
+	err = manager.Authorize(testRequest)
+
+	assert.IsType(s.T(), new(AccessDeniedError), err)
+
+	// Resolver receives the context.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	testCtxResolver := func(ctx context.Context, request *AccessRequest) (interface{}, error) {
+		return nil, ctx.Err()
+	}
+
+	ValueResolvers[departmentResolverName] = testCtxResolver
+
+	err = manager.AuthorizeContext(context.Background(), testRequest)
+
+	assert.IsType(s.T(), new(AccessDeniedError), err)
+
+	err = (&EqualCondition{
+		Left:  &ValueDescriptor{Source: Resolver, Field: departmentResolverName},
+		Right: &ValueDescriptor{Source: Explicit, Value: nil},
+	}).Check(&AccessRequest{ctx: ctx})
+
+	assert.True(s.T(), errors.Is(err, context.Canceled))
+
+	// Resolver errors are returned directly.
+	testRequest.Actions = []string{deleteAction}
+
+	err = manager.Authorize(testRequest)
+
+	assert.IsType(s.T(), new(ValueResolverError), err)
+}
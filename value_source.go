@@ -21,6 +21,10 @@ const (
 	ContextField
 	// Explicit - value set explicitly in PolicyDefinition.
 	Explicit
+	// CurrentTime - current time, as provided by AccessManager's Clock.
+	CurrentTime
+	// Resolver - value returned by ValueResolver registered under the name given in ValueDescriptor's Field.
+	Resolver
 )
 
 var byValue = map[ValueSource]string{
@@ -28,6 +32,8 @@ var byValue = map[ValueSource]string{
 	ResourceField: "ResourceField",
 	ContextField:  "ContextField",
 	Explicit:      "Explicit",
+	CurrentTime:   "CurrentTime",
+	Resolver:      "Resolver",
 }
 
 var byName = map[string]ValueSource{
@@ -35,6 +41,8 @@ var byName = map[string]ValueSource{
 	"ResourceField": ResourceField,
 	"ContextField":  ContextField,
 	"Explicit":      Explicit,
+	"CurrentTime":   CurrentTime,
+	"Resolver":      Resolver,
 }
 
 // String - Stringer implementation.
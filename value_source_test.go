@@ -14,6 +14,8 @@ type valueSourcesWrapper struct {
 	Resource ValueSource `json:"resource" yaml:"resource"`
 	Context  ValueSource `json:"context" yaml:"context"`
 	Explicit ValueSource `json:"explicit" yaml:"explicit"`
+	Time     ValueSource `json:"time" yaml:"time"`
+	Resolver ValueSource `json:"resolver" yaml:"resolver"`
 }
 
 type valueSourceSuiteMock struct {
@@ -29,6 +31,8 @@ func (s *valueSourceSuiteMock) TestValueSourceString() {
 	assert.Equal(s.T(), "ResourceField", ResourceField.String())
 	assert.Equal(s.T(), "ContextField", ContextField.String())
 	assert.Equal(s.T(), "Explicit", Explicit.String())
+	assert.Equal(s.T(), "CurrentTime", CurrentTime.String())
+	assert.Equal(s.T(), "Resolver", Resolver.String())
 	assert.Equal(s.T(), "", noopValueSource.String())
 }
 
@@ -37,7 +41,9 @@ func (s *valueSourceSuiteMock) TestUnmarshalJSON() {
 		"subject": "SubjectField",
 		"resource": "ResourceField",
 		"context": "ContextField",
-		"explicit": "Explicit"
+		"explicit": "Explicit",
+		"time": "CurrentTime",
+		"resolver": "Resolver"
 	}`)
 
 	assert.True(s.T(), json.Valid(valueSourceData))
@@ -57,6 +63,8 @@ subject: "SubjectField"
 resource: "ResourceField"
 context: "ContextField"
 explicit: "Explicit"
+time: "CurrentTime"
+resolver: "Resolver"
 `)
 
 	testValueSources := &valueSourcesWrapper{}
@@ -74,6 +82,8 @@ func (s *valueSourceSuiteMock) TestMarshalJSON() {
 		Resource: ResourceField,
 		Context:  ContextField,
 		Explicit: Explicit,
+		Time:     CurrentTime,
+		Resolver: Resolver,
 	}
 
 	valueSourcesJSON, err := json.Marshal(testValueSources)
@@ -96,6 +106,8 @@ func (s *valueSourceSuiteMock) TestMarshalYAML() {
 		Resource: ResourceField,
 		Context:  ContextField,
 		Explicit: Explicit,
+		Time:     CurrentTime,
+		Resolver: Resolver,
 	}
 
 	valueSourcesYAML, err := yaml.Marshal(testValueSources)
@@ -116,4 +128,6 @@ func (s *valueSourceSuiteMock) assertValueSourcesWrapper(testValueSources *value
 	assert.Equal(s.T(), testValueSources.Resource, ResourceField)
 	assert.Equal(s.T(), testValueSources.Context, ContextField)
 	assert.Equal(s.T(), testValueSources.Explicit, Explicit)
+	assert.Equal(s.T(), testValueSources.Time, CurrentTime)
+	assert.Equal(s.T(), testValueSources.Resolver, Resolver)
 }